@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/abdukhashimov/student_aggregator/internal/config"
+	repository "github.com/abdukhashimov/student_aggregator/internal/core/repository/mongodb"
 	"github.com/abdukhashimov/student_aggregator/internal/pkg/logger"
 	"github.com/abdukhashimov/student_aggregator/internal/transport/handlers"
 	"github.com/abdukhashimov/student_aggregator/pkg/logger/factory"
@@ -62,6 +63,22 @@ func serveHttp(port int) {
 	db := mongoClient.Database(cfg.MongoDB.Database)
 	log.Info("mongo db client successfully initialized")
 
+	indexCtx, cancelIndex := context.WithTimeout(context.Background(), time.Minute)
+	err = repository.CreateIndexes(indexCtx, db)
+	cancelIndex()
+	if err != nil {
+		panic(err)
+	}
+	log.Info("mongo db indexes successfully created")
+
+	migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), time.Minute)
+	err = repository.Migrate(migrateCtx, db)
+	cancelMigrate()
+	if err != nil {
+		panic(err)
+	}
+	log.Info("mongo db documents successfully migrated")
+
 	storageClient := minio.NewClient(cfg.Storage)
 	logger.Log.Info("Minio connection success")
 
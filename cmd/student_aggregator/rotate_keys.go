@@ -0,0 +1,65 @@
+package studentaggregator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	repository "github.com/abdukhashimov/student_aggregator/internal/core/repository/mongodb"
+	"github.com/abdukhashimov/student_aggregator/internal/pkg/logger"
+	"github.com/abdukhashimov/student_aggregator/pkg/logger/factory"
+	"github.com/abdukhashimov/student_aggregator/pkg/mongodb"
+	"github.com/spf13/cobra"
+)
+
+// rotateKeysCmd represents the rotate-keys command
+var rotateKeysCmd = &cobra.Command{
+	Use:   "rotate-keys",
+	Short: "Re-encrypts student fields with the current encryption key",
+	Long: `Re-encrypts student records and their history encrypted with previous keys
+listed in ENCRYPTION_KEYS with the first one, and encrypts or decrypts fields
+added to or removed from the encryption fields config.
+Previous keys may be removed from ENCRYPTION_KEYS when the command succeeds.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		rotateKeys(timeout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rotateKeysCmd)
+
+	rotateKeysCmd.PersistentFlags().Duration("timeout", time.Hour, "rotation timeout")
+}
+
+func rotateKeys(timeout time.Duration) {
+	cfg := config.Load(config.TRANSPORT_HTTP)
+	log, err := factory.Build(&cfg.Logging)
+	if err != nil {
+		panic(err)
+	}
+	logger.SetLogger(log)
+
+	mongoClient, err := mongodb.NewClient(cfg.MongoDB.URI, cfg.MongoDB.User, cfg.MongoDB.Password)
+	if err != nil {
+		panic(err)
+	}
+	db := mongoClient.Database(cfg.MongoDB.Database)
+
+	students, err := repository.NewStudentsRepo(db, cfg.Encryption)
+	if err != nil {
+		panic(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result, err := students.RotateKeys(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("re-encrypted %d students and %d history entries\n", result.Students, result.HistoryEntries)
+}
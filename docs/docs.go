@@ -95,37 +95,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Health Check",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Health Check",
-                "responses": {
-                    "200": {
-                        "description": "OK"
-                    },
-                    "500": {
-                        "description": "Internal Server Error"
-                    }
-                }
-            }
-        },
-        "/schemas": {
+        "/erasures": {
             "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "description": "retrieves all schemas",
+                "description": "lists audit records of personal data erasures, the most recent first",
                 "consumes": [
                     "application/json"
                 ],
@@ -133,31 +110,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "schema"
+                    "people"
+                ],
+                "summary": "List Erasures",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "limit, 100 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List Schemas",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SchemasResponse"
+                            "$ref": "#/definitions/handlers.ErasuresResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized"
                     },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "post": {
-                "security": [
-                    {
-                        "UsersAuth": []
-                    }
-                ],
-                "description": "schema creation process",
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Health Check",
                 "consumes": [
                     "application/json"
                 ],
@@ -165,26 +150,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "schema"
-                ],
-                "summary": "Create Schema",
-                "parameters": [
-                    {
-                        "description": "query params",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.NewSchemaInput"
-                        }
-                    }
+                    "health"
                 ],
+                "summary": "Health Check",
                 "responses": {
-                    "201": {
-                        "description": "Created"
-                    },
-                    "422": {
-                        "description": "Unprocessable Entity"
+                    "200": {
+                        "description": "OK"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -192,14 +163,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/schemas/{id}": {
+        "/people/{email}": {
             "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "description": "get course by id",
+                "description": "combines student records with the email, or the ones records with it were merged into, into a single profile",
                 "consumes": [
                     "application/json"
                 ],
@@ -207,14 +178,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "schema"
+                    "people"
                 ],
-                "summary": "Get Schema By ID",
+                "summary": "Get Person Profile",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "schema id",
-                        "name": "id",
+                        "description": "person email",
+                        "name": "email",
                         "in": "path",
                         "required": true
                     }
@@ -223,24 +194,29 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SchemaResponse"
+                            "$ref": "#/definitions/handlers.PersonProfileResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found"
                     },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/people/{email}/erase": {
+            "post": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "description": "delete schema",
+                "description": "removes every student record of the person with their history and the person rows of stored source files.\nThe erasure is recorded to the audit log without the email.",
                 "consumes": [
                     "application/json"
                 ],
@@ -248,72 +224,97 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "schema"
+                    "people"
                 ],
-                "summary": "Delete Schema",
+                "summary": "Erase Person Data",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "schema id",
-                        "name": "id",
+                        "description": "person email",
+                        "name": "email",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "erasure reason",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.EraseInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErasureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
                     },
                     "404": {
                         "description": "Not Found"
                     },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/people/{email}/export": {
+            "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "description": "update schema by id",
+                "description": "exports every student record of the person, including merged and deleted ones, files they were imported from and their history",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/zip"
                 ],
                 "tags": [
-                    "schema"
+                    "people"
                 ],
-                "summary": "Update Schema By ID",
+                "summary": "Export Person Data",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "schema id",
-                        "name": "id",
+                        "description": "person email",
+                        "name": "email",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "update info",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.UpdateSchemaInput"
-                        }
+                        "enum": [
+                            "json",
+                            "zip"
+                        ],
+                        "type": "string",
+                        "description": "export format, json by default",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SchemaResponse"
+                            "$ref": "#/definitions/handlers.PersonExportResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
                     "404": {
                         "description": "Not Found"
                     },
@@ -326,67 +327,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/storage/upload": {
-            "post": {
+        "/progress/late": {
+            "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "description": "Creates new blob object in storage with file name",
+                "description": "lists RSS projects finished after their deadlines, the most delayed first",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "file-upload"
+                    "progress"
                 ],
-                "summary": "Uploads files to blob storage",
+                "summary": "Late Submissions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "new object name in storage",
-                        "name": "file_name",
-                        "in": "formData",
-                        "required": true
+                        "description": "project name",
+                        "name": "project",
+                        "in": "query"
                     },
                     {
-                        "type": "file",
-                        "description": "file",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "type": "string",
+                        "description": "filter expression, e.g. student_rss.total_score\u003e=100",
+                        "name": "filter",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.FileUploadInfo"
+                            "$ref": "#/definitions/handlers.LateSubmissionsResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request"
-                    },
                     "401": {
                         "description": "Unauthorized"
                     },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/students": {
+        "/progress/leaderboard": {
             "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "description": "retrieves all students",
+                "description": "ranks RSS students by the project score or by total score, equal scores share the rank",
                 "consumes": [
                     "application/json"
                 ],
@@ -394,38 +393,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "student"
+                    "progress"
                 ],
-                "summary": "List Students",
+                "summary": "Projects Leaderboard",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "skip",
-                        "name": "skip",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "email",
-                        "name": "email",
+                        "description": "project name, students are ranked by total score when empty",
+                        "name": "project",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "source",
-                        "name": "source",
+                        "type": "integer",
+                        "description": "max number of entries",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "sort",
-                        "name": "sort",
+                        "description": "filter expression, e.g. student_rss.total_score\u003e=100",
+                        "name": "filter",
                         "in": "query"
                     }
                 ],
@@ -433,26 +420,29 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.StudentsResponse"
+                            "$ref": "#/definitions/handlers.LeaderboardResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized"
                     },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/students-by-file-name/{fileName}": {
-            "delete": {
+        "/progress/students": {
+            "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "description": "delete student by file name",
+                "description": "lists submitted, late and missing projects of RSS students and flags at-risk students by configured rules",
                 "consumes": [
                     "application/json"
                 ],
@@ -460,24 +450,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "student"
+                    "progress"
                 ],
-                "summary": "Delete Student By File Name",
+                "summary": "Students Progress",
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "at-risk students only",
+                        "name": "at_risk",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "file name",
-                        "name": "fileName",
-                        "in": "path",
-                        "required": true
+                        "description": "filter expression, e.g. student_rss.total_score\u003e=100",
+                        "name": "filter",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentsProgressResponse"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -485,14 +486,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/students/{id}": {
+        "/progress/students/{id}": {
             "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "description": "get student by id",
+                "description": "shows submitted, late and missing projects of RSS student and at-risk flags",
                 "consumes": [
                     "application/json"
                 ],
@@ -500,9 +501,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "student"
+                    "progress"
                 ],
-                "summary": "Get Student By ID",
+                "summary": "Student Progress",
                 "parameters": [
                     {
                         "type": "string",
@@ -516,9 +517,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.StudentResponse"
+                            "$ref": "#/definitions/handlers.StudentProgressResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
                     "404": {
                         "description": "Not Found"
                     },
@@ -526,14 +530,16 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/quality": {
+            "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "description": "update student by id",
+                "description": "counts missing, invalid and duplicate emails, out-of-range project scores, unparsable dates and empty required fields of students in total, per source and per import file",
                 "consumes": [
                     "application/json"
                 ],
@@ -541,77 +547,76 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "student"
+                    "stats"
                 ],
-                "summary": "Update Student By ID",
+                "summary": "Data Quality Report",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "student id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "update info",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.StudentRecord"
-                        }
+                        "description": "source",
+                        "name": "source",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.StudentResponse"
+                            "$ref": "#/definitions/handlers.QualityReportResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found"
-                    },
-                    "422": {
-                        "description": "Unprocessable Entity"
+                    "401": {
+                        "description": "Unauthorized"
                     },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "delete": {
-                "security": [
+            }
+        },
+        "/reports/engagement": {
+            "get": {
+                "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "description": "delete student",
+                "description": "joins RSS students with WAC community members by email and correlates community participation with project scores and completion",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "student"
+                    "reports"
                 ],
-                "summary": "Delete Student",
+                "summary": "Engagement Report",
                 "parameters": [
                     {
+                        "enum": [
+                            "json",
+                            "xlsx"
+                        ],
                         "type": "string",
-                        "description": "student id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "report format, json by default",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EngagementReportResponse"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -619,14 +624,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
+        "/schemas": {
             "get": {
                 "security": [
                     {
                         "UsersAuth": []
                     }
                 ],
-                "description": "retrieves user profile",
+                "description": "retrieves all schemas",
                 "consumes": [
                     "application/json"
                 ],
@@ -634,28 +639,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "schema"
+                ],
+                "summary": "List Schemas",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "limit, 20 by default, 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "count total number of schemas",
+                        "name": "with_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated sort fields, minus prefix for descending order, e.g. -version,name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include schemas moved to trash",
+                        "name": "with_deleted",
+                        "in": "query"
+                    }
                 ],
-                "summary": "User Profile",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.UserProfileResponse"
+                            "$ref": "#/definitions/handlers.SchemasResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized"
                     },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            }
-        },
-        "/users": {
+            },
             "post": {
-                "description": "user sign up process",
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "schema creation process",
                 "consumes": [
                     "application/json"
                 ],
@@ -663,9 +706,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user-auth"
+                    "schema"
                 ],
-                "summary": "User SignUp",
+                "summary": "Create Schema",
                 "parameters": [
                     {
                         "description": "query params",
@@ -673,7 +716,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.SignUpUserInput"
+                            "$ref": "#/definitions/domain.NewSchemaInput"
                         }
                     }
                 ],
@@ -690,9 +733,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/login": {
-            "post": {
-                "description": "user sign in process",
+        "/schemas/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "get course by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -700,17 +748,115 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user-auth"
+                    "schema"
                 ],
-                "summary": "User SignIn",
+                "summary": "Get Schema By ID",
                 "parameters": [
                     {
-                        "description": "query params",
-                        "name": "request",
+                        "type": "string",
+                        "description": "schema id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SchemaResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "schema revision"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "moves schema to trash, it can be restored until it is purged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schema"
+                ],
+                "summary": "Delete Schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "schema id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "update schema by id, the schema is updated only if it has the revision of If-Match header when it is set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schema"
+                ],
+                "summary": "Update Schema By ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "schema id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the schema revision",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "update info",
+                        "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.SignInUserInput"
+                            "$ref": "#/definitions/domain.UpdateSchemaInput"
                         }
                     }
                 ],
@@ -718,9 +864,21 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.Tokens"
+                            "$ref": "#/definitions/handlers.SchemaResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "schema revision"
+                            }
                         }
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
                     "422": {
                         "description": "Unprocessable Entity"
                     },
@@ -729,228 +887,3318 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.FieldSchema": {
-            "type": "object",
-            "properties": {
-                "col": {
-                    "type": "string"
-                },
-                "is_map": {
-                    "type": "boolean"
-                },
-                "is_multiple": {
-                    "type": "boolean"
-                },
-                "map_start": {
-                    "type": "boolean"
-                },
-                "name": {
-                    "type": "string"
+        },
+        "/stats/students": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "counts students by source, status, file, location and membership type, RSS project scores distribution and WAC attendance",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Students Statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "source",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter expression, e.g. student_rss.projects.score\u003e=80 AND student_wac.attended_events\u003e2",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentsStatsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
                 }
             }
         },
-        "domain.NewSchemaInput": {
-            "type": "object",
-            "required": [
-                "fields",
-                "headers",
+        "/stats/timeseries": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "counts RSS applications, WAC joins or RSS project completions per time interval",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Students Time Series",
+                "parameters": [
+                    {
+                        "enum": [
+                            "applications",
+                            "joins",
+                            "completions"
+                        ],
+                        "type": "string",
+                        "description": "counted metric",
+                        "name": "metric",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month",
+                            "year"
+                        ],
+                        "type": "string",
+                        "description": "time bucket, month by default",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "source",
+                            "status",
+                            "location",
+                            "membership_type",
+                            "project"
+                        ],
+                        "type": "string",
+                        "description": "group by dimension, project is available for completions only",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "inclusive start date, e.g. 2022-01-01",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "exclusive end date, e.g. 2023-01-01",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "source",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter expression, e.g. student_rss.projects.score\u003e=80 AND student_wac.attended_events\u003e2",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TimeSeriesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/storage/upload": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "Creates new blob object in storage with file name",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "file-upload"
+                ],
+                "summary": "Uploads files to blob storage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "new object name in storage",
+                        "name": "file_name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FileUploadInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "retrieves all students",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "List Students",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "limit, 20 by default, 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "skip, ignored with cursor",
+                        "name": "skip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "count total number of students",
+                        "name": "with_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "source",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated sort fields, minus prefix for descending order, e.g. -score,last_name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "tag name",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter expression, e.g. student_rss.projects.score\u003e=80 AND student_wac.attended_events\u003e2",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include students moved to trash",
+                        "name": "with_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students-by-file-name/{fileName}": {
+            "delete": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "delete student by file name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Delete Student By File Name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "file name",
+                        "name": "fileName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "sets a field, adds or removes a tag, changes status by a workflow transition or deletes students selected either by ids or by filter expression, at most 1000 students at once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Bulk Students Operation",
+                "parameters": [
+                    {
+                        "description": "students and operation",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.BulkStudentsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BulkResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/duplicates": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "finds students which are probably the same person registered with different emails",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "List Duplicate Students",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "minimal score from 0 to 1, default 0.75",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "source",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DuplicatesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/duplicates/merge": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "merges duplicate students into the primary one keeping provenance of merged records",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Merge Duplicate Students",
+                "parameters": [
+                    {
+                        "description": "merge info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.MergeStudentsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/export": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "exports students matching the same filters as students list to CSV or XLSX file",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Export Students",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "file format",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated columns, projects adds project_N_* columns, all columns by default",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "export in the layout of the schema to import the file back, columns are ignored",
+                        "name": "schema_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "source",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated sort fields, minus prefix for descending order, e.g. -score,last_name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "tag name",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter expression, e.g. student_rss.projects.score\u003e=80 AND student_wac.attended_events\u003e2",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include students moved to trash",
+                        "name": "with_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/search": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "full-text search by names, email, company, location and project names ordered by relevance",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Search Students",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "source",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit, 20 by default, 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentsSearchResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "get student by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Get Student By ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "student revision"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "update student by id, the student is updated only if it has the revision of If-Match header when it is set.\nsource and merged_from are read-only, status, status_changes, tags, notes and annotations are changed by their own endpoints, they may be omitted or sent unchanged only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Update Student By ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the student revision",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "update info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.StudentRecord"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "student revision"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "moves student to trash, it can be restored until it is purged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Delete Student",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "applies JSON Merge Patch (application/merge-patch+json, also used for application/json) or JSON Patch (application/json-patch+json) to student JSON representation.\nid, source, file_name, imported_at and merged_from are read-only, total_score and parsed dates are recalculated.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json",
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Patch Student By ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the student revision",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "merge patch object or array of patch operations",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "student revision"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/{id}/annotations/{key}": {
+            "put": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "sets custom annotation value of student by key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "annotations"
+                ],
+                "summary": "Set Student Annotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "annotation key, lowercase letters, digits and underscores",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "annotation",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.AnnotationInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "student revision"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "deletes custom annotation of student",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "annotations"
+                ],
+                "summary": "Delete Student Annotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "annotation key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "student revision"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "lists versions of student record with changed fields, who and how changed them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Get Student History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentHistoryResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/{id}/history/{version}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "restores student record to the version, deleted students are restored with the same id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "student"
+                ],
+                "summary": "Restore Student Version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "version to restore",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the student revision",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "student revision"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/{id}/notes": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "adds a note authored by the current user to student",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "annotations"
+                ],
+                "summary": "Add Student Note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "note",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.NoteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "student revision"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/{id}/notes/{noteId}": {
+            "put": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "replaces text of student note",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "annotations"
+                ],
+                "summary": "Update Student Note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "note id",
+                        "name": "noteId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "note",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.NoteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "student revision"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "deletes student note",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "annotations"
+                ],
+                "summary": "Delete Student Note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "note id",
+                        "name": "noteId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "student revision"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/{id}/status": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "changes student status if the workflow allows the transition, the change is recorded with its reason and author.\nStudent without status may be given any workflow status.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "Change Student Status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "student revision from ETag, the status is changed only if it matches",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "new status",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.StatusInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "student revision"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/{id}/tags": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "tags student, tags are kept when the student is imported again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "annotations"
+                ],
+                "summary": "Add Student Tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "tag",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.TagInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "student revision"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/students/{id}/tags/{tag}": {
+            "delete": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "removes tag of student",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "annotations"
+                ],
+                "summary": "Remove Student Tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "tag name",
+                        "name": "tag",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "student revision"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "lists students and schemas moved to trash, the most recently deleted first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "List Trash",
+                "parameters": [
+                    {
+                        "enum": [
+                            "student",
+                            "schema"
+                        ],
+                        "type": "string",
+                        "description": "item type, all types by default",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limit, 100 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TrashResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/trash/schemas/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "restores schema moved to trash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore Schema From Trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "schema id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SchemaResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/trash/students/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "restores student moved to trash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore Student From Trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "student id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "retrieves user profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "User Profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserProfileResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/users": {
+            "post": {
+                "description": "user sign up process",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-auth"
+                ],
+                "summary": "User SignUp",
+                "parameters": [
+                    {
+                        "description": "query params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.SignUpUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "description": "user sign in process",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user-auth"
+                ],
+                "summary": "User SignIn",
+                "parameters": [
+                    {
+                        "description": "query params",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.SignInUserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Tokens"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/workflow": {
+            "get": {
+                "security": [
+                    {
+                        "UsersAuth": []
+                    }
+                ],
+                "description": "returns student statuses, allowed transitions between them and automatic transitions applied on import",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "Get Workflow",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Workflow"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.Actor": {
+            "type": "object",
+            "properties": {
+                "file_name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "via": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Annotation": {
+            "type": "object",
+            "properties": {
+                "set_at": {
+                    "type": "string"
+                },
+                "set_by": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AnnotationInput": {
+            "type": "object",
+            "required": [
+                "value"
+            ],
+            "properties": {
+                "value": {
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "domain.AttendanceStats": {
+            "type": "object",
+            "properties": {
+                "attended": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "registered": {
+                    "type": "integer"
+                },
+                "registered_not_visited": {
+                    "type": "integer"
+                },
+                "students": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.AutomaticTransition": {
+            "type": "object",
+            "properties": {
+                "condition": {
+                    "type": "string"
+                },
+                "filter": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BulkItemError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BulkOperation": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "field": {
+                    "description": "Field and Value are set by set_field operation.",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "status": {
+                    "description": "Status and Reason are set by transition operation, students are changed only if the workflow allows it.",
+                    "type": "string"
+                },
+                "tag": {
+                    "description": "Tag is added or removed by add_tag and remove_tag operations.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "set_field",
+                        "add_tag",
+                        "remove_tag",
+                        "delete",
+                        "transition"
+                    ]
+                },
+                "value": {}
+            }
+        },
+        "domain.BulkResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BulkItemError"
+                    }
+                },
+                "matched": {
+                    "description": "Matched is the number of selected students, Modified is the number of students changed by the operation.",
+                    "type": "integer"
+                },
+                "modified": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.BulkStudentsInput": {
+            "type": "object",
+            "required": [
+                "ids",
+                "operation"
+            ],
+            "properties": {
+                "filter": {
+                    "type": "string"
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "operation": {
+                    "$ref": "#/definitions/domain.BulkOperation"
+                }
+            }
+        },
+        "domain.Correlation": {
+            "type": "object",
+            "properties": {
+                "coefficient": {
+                    "type": "number"
+                },
+                "samples": {
+                    "type": "integer"
+                },
+                "x": {
+                    "type": "string"
+                },
+                "y": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CountItem": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DuplicateCandidate": {
+            "type": "object",
+            "properties": {
+                "first": {
+                    "$ref": "#/definitions/domain.StudentRecord"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "type": "number"
+                },
+                "second": {
+                    "$ref": "#/definitions/domain.StudentRecord"
+                }
+            }
+        },
+        "domain.EngagementGroup": {
+            "type": "object",
+            "properties": {
+                "avg_score": {
+                    "type": "number"
+                },
+                "completion_rate": {
+                    "type": "number"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "students": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.EngagementReport": {
+            "type": "object",
+            "properties": {
+                "by_attendance": {
+                    "description": "ByAttendance groups matched students by the number of attended events.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.EngagementGroup"
+                    }
+                },
+                "by_membership": {
+                    "description": "ByMembership compares community members with other RSS students.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.EngagementGroup"
+                    }
+                },
+                "correlations": {
+                    "description": "Correlations are calculated over matched students.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Correlation"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "matched": {
+                    "description": "Matched is the number of RSS students being WAC community members.",
+                    "type": "integer"
+                },
+                "rss_students": {
+                    "type": "integer"
+                },
+                "students": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.EngagementRow"
+                    }
+                },
+                "wac_members": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.EngagementRow": {
+            "type": "object",
+            "properties": {
+                "attended_events": {
+                    "type": "integer"
+                },
+                "community_member": {
+                    "type": "boolean"
+                },
+                "completion_rate": {
+                    "type": "number"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "registered": {
+                    "type": "integer"
+                },
+                "registered_not_visited": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "submitted": {
+                    "type": "integer"
+                },
+                "total_score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.EraseInput": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "domain.Erasure": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "email_hash": {
+                    "type": "string"
+                },
+                "files": {
+                    "description": "Files are stored source files the person rows were removed from.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "history_entries": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "type": "string"
+                },
+                "students": {
+                    "description": "Students and HistoryEntries are the numbers of removed documents.",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "new": {},
+                "old": {}
+            }
+        },
+        "domain.FieldConflict": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SourcedValue"
+                    }
+                }
+            }
+        },
+        "domain.FieldSchema": {
+            "type": "object",
+            "properties": {
+                "col": {
+                    "type": "string"
+                },
+                "is_date": {
+                    "type": "boolean"
+                },
+                "is_map": {
+                    "type": "boolean"
+                },
+                "is_multiple": {
+                    "type": "boolean"
+                },
+                "lookup": {
+                    "type": "string"
+                },
+                "map_start": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FileCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "imported_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.HistoryEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "$ref": "#/definitions/domain.Actor"
+                },
+                "at": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldChange"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "restored_from": {
+                    "description": "RestoredFrom is the version restored by the change.",
+                    "type": "integer"
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/domain.StudentRecord"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ImportQuality": {
+            "type": "object",
+            "properties": {
+                "duplicate_emails": {
+                    "description": "DuplicateEmails is the number of students sharing email with another student of the same source.",
+                    "type": "integer"
+                },
+                "empty_fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "empty_required_fields": {
+                    "description": "EmptyRequiredFields sums EmptyFields, which counts empty required fields by field name.",
+                    "type": "integer"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "imported_at": {
+                    "type": "string"
+                },
+                "invalid_emails": {
+                    "type": "integer"
+                },
+                "missing_emails": {
+                    "type": "integer"
+                },
+                "out_of_range_scores": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "students": {
+                    "type": "integer"
+                },
+                "students_with_issues": {
+                    "type": "integer"
+                },
+                "unparsable_dates": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ImportReference": {
+            "type": "object",
+            "properties": {
+                "file_name": {
+                    "type": "string"
+                },
+                "imported_at": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.LateSubmission": {
+            "type": "object",
+            "properties": {
+                "deadline": {
+                    "type": "string"
+                },
+                "delay_hours": {
+                    "type": "number"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "project": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Leaderboard": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.LeaderboardEntry"
+                    }
+                },
+                "project": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "student_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.LookupTable": {
+            "type": "object",
+            "required": [
+                "name",
+                "values"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "on_unknown": {
+                    "type": "string",
+                    "enum": [
+                        "keep",
+                        "null",
+                        "error"
+                    ]
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.LookupValue"
+                    }
+                }
+            }
+        },
+        "domain.LookupValue": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.MergeStudentsInput": {
+            "type": "object",
+            "required": [
+                "duplicate_ids",
+                "primary_id"
+            ],
+            "properties": {
+                "duplicate_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "primary_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.MergedRecord": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "merged_at": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.NewSchemaInput": {
+            "type": "object",
+            "required": [
+                "fields",
+                "headers",
                 "name",
                 "schema_type",
                 "version"
             ],
             "properties": {
-                "fields": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.FieldSchema"
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldSchema"
+                    }
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "boolean"
+                },
+                "lookups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.LookupTable"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 3
+                },
+                "options": {
+                    "$ref": "#/definitions/domain.ReadOptions"
+                },
+                "schema_type": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Note": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.NoteInput": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "text": {
+                    "type": "string",
+                    "maxLength": 5000
+                }
+            }
+        },
+        "domain.ParseFileInput": {
+            "type": "object",
+            "required": [
+                "file_name",
+                "schema_id"
+            ],
+            "properties": {
+                "file_name": {
+                    "type": "string"
+                },
+                "schema_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.PersonExport": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.HistoryEntry"
+                    }
+                },
+                "imports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ImportReference"
+                    }
+                },
+                "records": {
+                    "description": "Records are student records of the person including ones in trash and the ones merged with them.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.StudentRecord"
+                    }
+                }
+            }
+        },
+        "domain.PersonIdentity": {
+            "type": "object",
+            "properties": {
+                "company": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.PersonProfile": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldConflict"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "identity": {
+                    "$ref": "#/definitions/domain.PersonIdentity"
+                },
+                "sources": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/domain.StudentRecord"
+                        }
+                    }
+                },
+                "timeline": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TimelineEvent"
+                    }
+                }
+            }
+        },
+        "domain.Project": {
+            "type": "object",
+            "properties": {
+                "deadline": {
+                    "type": "string"
+                },
+                "deadline_time": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "finished_time": {
+                    "description": "FinishedTime and DeadlineTime are FinishedAt and Deadline parsed on save.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ProjectScoreStats": {
+            "type": "object",
+            "properties": {
+                "avg": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "percentiles": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "project": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.QualityMetrics": {
+            "type": "object",
+            "properties": {
+                "duplicate_emails": {
+                    "description": "DuplicateEmails is the number of students sharing email with another student of the same source.",
+                    "type": "integer"
+                },
+                "empty_fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "empty_required_fields": {
+                    "description": "EmptyRequiredFields sums EmptyFields, which counts empty required fields by field name.",
+                    "type": "integer"
+                },
+                "invalid_emails": {
+                    "type": "integer"
+                },
+                "missing_emails": {
+                    "type": "integer"
+                },
+                "out_of_range_scores": {
+                    "type": "integer"
+                },
+                "students": {
+                    "type": "integer"
+                },
+                "students_with_issues": {
+                    "type": "integer"
+                },
+                "unparsable_dates": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.QualityReport": {
+            "type": "object",
+            "properties": {
+                "by_import": {
+                    "description": "ByImport is ordered by import time, the most recent first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ImportQuality"
+                    }
+                },
+                "by_source": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SourceQuality"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/domain.QualityMetrics"
+                }
+            }
+        },
+        "domain.ReadOptions": {
+            "type": "object",
+            "properties": {
+                "calc_formulas": {
+                    "type": "boolean"
+                },
+                "hyperlinks": {
+                    "type": "boolean"
+                },
+                "ignore_merged_cells": {
+                    "description": "IgnoreMergedCells leaves cells covered by merged ranges empty, they hold the range value by default.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.Schema": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "description": "DeletedAt is set when the schema is moved to trash, DeletedBy is the username of the user who did it.",
+                    "type": "string"
+                },
+                "deleted_by": {
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldSchema"
+                    }
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lookups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.LookupTable"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "$ref": "#/definitions/domain.ReadOptions"
+                },
+                "revision": {
+                    "description": "Revision is incremented on every update, schemas created before revisions were introduced have 0.",
+                    "type": "integer"
+                },
+                "schema_type": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Series": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TimePoint"
+                    }
+                }
+            }
+        },
+        "domain.SignInUserInput": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SignUpUserInput": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 8
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 6
+                }
+            }
+        },
+        "domain.SourceQuality": {
+            "type": "object",
+            "properties": {
+                "duplicate_emails": {
+                    "description": "DuplicateEmails is the number of students sharing email with another student of the same source.",
+                    "type": "integer"
+                },
+                "empty_fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
                     }
                 },
-                "headers": {
+                "empty_required_fields": {
+                    "description": "EmptyRequiredFields sums EmptyFields, which counts empty required fields by field name.",
+                    "type": "integer"
+                },
+                "invalid_emails": {
+                    "type": "integer"
+                },
+                "missing_emails": {
+                    "type": "integer"
+                },
+                "out_of_range_scores": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "students": {
+                    "type": "integer"
+                },
+                "students_with_issues": {
+                    "type": "integer"
+                },
+                "unparsable_dates": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.SourcedValue": {
+            "type": "object",
+            "properties": {
+                "source": {
+                    "type": "string"
+                },
+                "student_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.StatusChange": {
+            "type": "object",
+            "properties": {
+                "automatic": {
+                    "description": "Automatic is set when the status is changed by the workflow on import.",
                     "type": "boolean"
                 },
-                "name": {
-                    "type": "string",
-                    "minLength": 3
+                "changed_at": {
+                    "type": "string"
                 },
-                "schema_type": {
+                "changed_by": {
                     "type": "string"
                 },
-                "version": {
+                "from": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to": {
                     "type": "string"
                 }
             }
         },
-        "domain.ParseFileInput": {
+        "domain.StatusInput": {
             "type": "object",
             "required": [
-                "file_name",
-                "schema_id"
+                "status"
             ],
             "properties": {
-                "file_name": {
-                    "type": "string"
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000
                 },
-                "schema_id": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "domain.Project": {
+        "domain.StudentProgress": {
             "type": "object",
             "properties": {
-                "deadline": {
-                    "type": "string"
+                "at_risk": {
+                    "type": "boolean"
                 },
-                "finished_at": {
+                "at_risk_reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "average_score": {
+                    "description": "AverageScore is total score divided by the number of required projects.",
+                    "type": "number"
+                },
+                "email": {
                     "type": "string"
                 },
+                "late": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "missing": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "name": {
                     "type": "string"
                 },
-                "score": {
+                "student_id": {
+                    "type": "string"
+                },
+                "submitted": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "total_score": {
                     "type": "integer"
                 }
             }
         },
-        "domain.Schema": {
+        "domain.StudentRecord": {
             "type": "object",
             "properties": {
-                "fields": {
+                "annotations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/domain.Annotation"
+                    }
+                },
+                "application_date": {
+                    "type": "string"
+                },
+                "application_time": {
+                    "description": "ApplicationTime is ApplicationDate parsed on save.",
+                    "type": "string"
+                },
+                "attended_events": {
+                    "type": "integer"
+                },
+                "company": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt is set when the record is moved to trash, DeletedBy is the username of the user who did it.",
+                    "type": "string"
+                },
+                "deleted_by": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imported_at": {
+                    "type": "string"
+                },
+                "imported_from": {
+                    "description": "ImportedFrom lists every file the record is imported from, FileName is the last one.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.FieldSchema"
+                        "type": "string"
                     }
                 },
-                "headers": {
-                    "type": "boolean"
+                "join_date": {
+                    "type": "string"
                 },
-                "id": {
+                "join_time": {
+                    "description": "JoinTime is JoinDate parsed on save.",
                     "type": "string"
                 },
-                "name": {
+                "last_name": {
                     "type": "string"
                 },
-                "schema_type": {
+                "location": {
+                    "type": "string"
+                },
+                "membership_type": {
+                    "type": "string"
+                },
+                "merged_from": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.MergedRecord"
+                    }
+                },
+                "notes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Note"
+                    }
+                },
+                "position": {
+                    "type": "string"
+                },
+                "preffered_language": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Project"
+                    }
+                },
+                "receives_community_updates": {
+                    "type": "boolean"
+                },
+                "registered": {
+                    "type": "integer"
+                },
+                "registered_not_visited": {
+                    "type": "integer"
+                },
+                "revision": {
+                    "description": "Revision is incremented on every update, records saved before revisions were introduced have 0.",
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "RSS, WAC",
                     "type": "string"
                 },
-                "version": {
-                    "type": "string"
+                "status": {
+                    "description": "Status is changed by the workflow transitions only, StatusChanges records them.",
+                    "type": "string"
+                },
+                "status_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.StatusChange"
+                    }
+                },
+                "status_items": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "description": "Tags, Notes and Annotations are set by staff independently of imported data and are kept on re-import.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Tag"
+                    }
+                },
+                "total_score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.StudentSearchResult": {
+            "type": "object",
+            "properties": {
+                "highlights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "type": "number"
+                },
+                "student": {
+                    "$ref": "#/definitions/domain.StudentRecord"
+                }
+            }
+        },
+        "domain.StudentsStats": {
+            "type": "object",
+            "properties": {
+                "attendance": {
+                    "$ref": "#/definitions/domain.AttendanceStats"
+                },
+                "by_file": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FileCount"
+                    }
+                },
+                "by_location": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CountItem"
+                    }
+                },
+                "by_membership_type": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CountItem"
+                    }
+                },
+                "by_source": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CountItem"
+                    }
+                },
+                "by_status": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CountItem"
+                    }
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ProjectScoreStats"
+                    }
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "domain.SignInUserInput": {
+        "domain.Tag": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "added_at": {
                     "type": "string"
                 },
-                "password": {
+                "added_by": {
+                    "type": "string"
+                },
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "domain.SignUpUserInput": {
+        "domain.TagInput": {
             "type": "object",
             "required": [
-                "email",
-                "password",
-                "username"
+                "name"
             ],
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string",
-                    "maxLength": 200,
-                    "minLength": 8
-                },
-                "username": {
+                "name": {
                     "type": "string",
-                    "maxLength": 200,
-                    "minLength": 6
+                    "maxLength": 50
                 }
             }
         },
-        "domain.StudentRecord": {
+        "domain.TimePoint": {
             "type": "object",
             "properties": {
-                "application_date": {
-                    "type": "string"
-                },
-                "attended_events": {
+                "count": {
                     "type": "integer"
                 },
-                "company": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "file_name": {
-                    "type": "string"
-                },
-                "first_name": {
-                    "type": "string"
-                },
-                "full_name": {
-                    "type": "string"
-                },
-                "join_date": {
-                    "type": "string"
-                },
-                "last_name": {
+                "start": {
                     "type": "string"
-                },
-                "location": {
+                }
+            }
+        },
+        "domain.TimeSeries": {
+            "type": "object",
+            "properties": {
+                "group_by": {
                     "type": "string"
                 },
-                "membership_type": {
+                "interval": {
                     "type": "string"
                 },
-                "position": {
+                "metric": {
                     "type": "string"
                 },
-                "preffered_language": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "projects": {
+                "series": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.Project"
+                        "$ref": "#/definitions/domain.Series"
                     }
+                }
+            }
+        },
+        "domain.TimelineEvent": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
                 },
-                "receives_community_updates": {
-                    "type": "boolean"
-                },
-                "registered": {
-                    "type": "integer"
+                "event": {
+                    "type": "string"
                 },
-                "registered_not_visited": {
-                    "type": "integer"
+                "file_name": {
+                    "type": "string"
                 },
                 "source": {
-                    "description": "RSS, WAC",
                     "type": "string"
                 },
-                "status": {
+                "student_id": {
                     "type": "string"
-                },
-                "status_items": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
                 }
             }
         },
@@ -976,6 +4224,31 @@ const docTemplate = `{
                 }
             }
         },
+        "domain.TrashItem": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "type": "string"
+                },
+                "deleted_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name is student email or schema name.",
+                    "type": "string"
+                },
+                "purge_at": {
+                    "description": "PurgeAt is the time the item is deleted permanently after, items are kept forever when it is not set.",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
         "domain.UpdateSchemaInput": {
             "type": "object",
             "required": [
@@ -992,13 +4265,25 @@ const docTemplate = `{
                         "$ref": "#/definitions/domain.FieldSchema"
                     }
                 },
+                "group_by": {
+                    "type": "string"
+                },
                 "headers": {
                     "type": "boolean"
                 },
+                "lookups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.LookupTable"
+                    }
+                },
                 "name": {
                     "type": "string",
                     "minLength": 3
                 },
+                "options": {
+                    "$ref": "#/definitions/domain.ReadOptions"
+                },
                 "schema_type": {
                     "type": "string"
                 },
@@ -1021,6 +4306,81 @@ const docTemplate = `{
                 }
             }
         },
+        "domain.Workflow": {
+            "type": "object",
+            "properties": {
+                "automatic": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AutomaticTransition"
+                    }
+                },
+                "initial_status": {
+                    "type": "string"
+                },
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "transitions": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "handlers.BulkResponse": {
+            "type": "object",
+            "properties": {
+                "result": {
+                    "$ref": "#/definitions/domain.BulkResult"
+                }
+            }
+        },
+        "handlers.DuplicatesResponse": {
+            "type": "object",
+            "properties": {
+                "duplicates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.DuplicateCandidate"
+                    }
+                }
+            }
+        },
+        "handlers.EngagementReportResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/domain.EngagementReport"
+                }
+            }
+        },
+        "handlers.ErasureResponse": {
+            "type": "object",
+            "properties": {
+                "erasure": {
+                    "$ref": "#/definitions/domain.Erasure"
+                }
+            }
+        },
+        "handlers.ErasuresResponse": {
+            "type": "object",
+            "properties": {
+                "erasures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Erasure"
+                    }
+                }
+            }
+        },
         "handlers.FileUploadInfo": {
             "type": "object",
             "properties": {
@@ -1035,6 +4395,49 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.LateSubmissionsResponse": {
+            "type": "object",
+            "properties": {
+                "late": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.LateSubmission"
+                    }
+                }
+            }
+        },
+        "handlers.LeaderboardResponse": {
+            "type": "object",
+            "properties": {
+                "leaderboard": {
+                    "$ref": "#/definitions/domain.Leaderboard"
+                }
+            }
+        },
+        "handlers.PersonExportResponse": {
+            "type": "object",
+            "properties": {
+                "export": {
+                    "$ref": "#/definitions/domain.PersonExport"
+                }
+            }
+        },
+        "handlers.PersonProfileResponse": {
+            "type": "object",
+            "properties": {
+                "profile": {
+                    "$ref": "#/definitions/domain.PersonProfile"
+                }
+            }
+        },
+        "handlers.QualityReportResponse": {
+            "type": "object",
+            "properties": {
+                "quality": {
+                    "$ref": "#/definitions/domain.QualityReport"
+                }
+            }
+        },
         "handlers.SchemaResponse": {
             "type": "object",
             "properties": {
@@ -1046,11 +4449,39 @@ const docTemplate = `{
         "handlers.SchemasResponse": {
             "type": "object",
             "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
                 "schemas": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/domain.Schema"
                     }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.StudentHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.HistoryEntry"
+                    }
+                }
+            }
+        },
+        "handlers.StudentProgressResponse": {
+            "type": "object",
+            "properties": {
+                "progress": {
+                    "$ref": "#/definitions/domain.StudentProgress"
                 }
             }
         },
@@ -1062,14 +4493,72 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.StudentsProgressResponse": {
+            "type": "object",
+            "properties": {
+                "progress": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.StudentProgress"
+                    }
+                }
+            }
+        },
         "handlers.StudentsResponse": {
             "type": "object",
             "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
                 "students": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/domain.StudentRecord"
                     }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.StudentsSearchResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.StudentSearchResult"
+                    }
+                }
+            }
+        },
+        "handlers.StudentsStatsResponse": {
+            "type": "object",
+            "properties": {
+                "stats": {
+                    "$ref": "#/definitions/domain.StudentsStats"
+                }
+            }
+        },
+        "handlers.TimeSeriesResponse": {
+            "type": "object",
+            "properties": {
+                "time_series": {
+                    "$ref": "#/definitions/domain.TimeSeries"
+                }
+            }
+        },
+        "handlers.TrashResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TrashItem"
+                    }
                 }
             }
         },
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	logConfig "github.com/abdukhashimov/student_aggregator/pkg/logger/config"
 
@@ -23,12 +25,17 @@ const (
 )
 
 type Config struct {
-	Transport Transport
-	Logging   logConfig.Logging `yaml:"logging"`
-	Project   ProjectConfig     `yaml:"project"`
-	MongoDB   MongoDBConfig     `yaml:"mongodb"`
-	Http      HttpConfig        `yaml:"http"`
-	Storage   StorageConfig     `yaml:"storage"`
+	Transport  Transport
+	Logging    logConfig.Logging `yaml:"logging"`
+	Project    ProjectConfig     `yaml:"project"`
+	MongoDB    MongoDBConfig     `yaml:"mongodb"`
+	Http       HttpConfig        `yaml:"http"`
+	Storage    StorageConfig     `yaml:"storage"`
+	Progress   ProgressConfig    `yaml:"progress"`
+	Trash      TrashConfig       `yaml:"trash"`
+	Workflow   WorkflowConfig    `yaml:"workflow"`
+	Encryption EncryptionConfig  `yaml:"encryption"`
+	Quality    QualityConfig     `yaml:"quality"`
 }
 
 type ProjectConfig struct {
@@ -64,6 +71,162 @@ type StorageConfig struct {
 	BucketName      string `yaml:"bucketName"`
 }
 
+type ProgressConfig struct {
+	// RequiredProjects are RSS projects every student should submit, all known projects are required when empty.
+	RequiredProjects []string    `yaml:"requiredProjects"`
+	AtRisk           AtRiskRules `yaml:"atRisk"`
+}
+
+// AtRiskRules flag a student at risk when any rule is broken, unset rules are not checked.
+type AtRiskRules struct {
+	MinAverageScore    *float64 `yaml:"minAverageScore"`
+	MaxLateProjects    *int     `yaml:"maxLateProjects"`
+	MaxMissingProjects *int     `yaml:"maxMissingProjects"`
+}
+
+type TrashConfig struct {
+	// RetentionDays is how long deleted students and schemas are kept in trash, they are never purged when it is 0.
+	RetentionDays        int `yaml:"retentionDays"`
+	PurgeIntervalMinutes int `yaml:"purgeIntervalMinutes"`
+}
+
+// QualityFields are student fields which may be required by the data quality report,
+// missing emails are reported separately.
+var QualityFields = []string{
+	"student_rss.first_name",
+	"student_rss.last_name",
+	"student_rss.application_date",
+	"student_rss.projects",
+	"student_wac.full_name",
+	"student_wac.join_date",
+	"student_wac.location",
+	"student_wac.company",
+	"student_wac.position",
+	"student_wac.membership_type",
+}
+
+// QualityConfig defines what the data quality report counts as issues.
+type QualityConfig struct {
+	// MinScore and MaxScore bound RSS project scores, scores are not checked when MaxScore is 0.
+	MinScore int `yaml:"minScore"`
+	MaxScore int `yaml:"maxScore"`
+	// RequiredFields are QualityFields every student of the source should have, keyed by source.
+	RequiredFields map[string][]string `yaml:"requiredFields"`
+}
+
+// EncryptableFields are student fields which may be encrypted at rest.
+var EncryptableFields = []string{
+	"email",
+	"student_rss.first_name",
+	"student_rss.last_name",
+	"student_wac.full_name",
+	"student_wac.location",
+	"student_wac.company",
+	"student_wac.position",
+}
+
+// EncryptionConfig lists student fields encrypted at rest, encryption is disabled when there are none.
+// Encrypted fields can not be searched, filtered, sorted or grouped by in statistics,
+// students are looked up by email with its blind index.
+type EncryptionConfig struct {
+	Fields []string `yaml:"fields"`
+	// Keys are comma separated base64 encoded 32 byte keys. Values are encrypted with the first key,
+	// previous keys are listed after it until values are re-encrypted by the rotate-keys command.
+	// Keys are kept when no fields are configured any more, until the command decrypts the values.
+	Keys string `env:"ENCRYPTION_KEYS"`
+	// IndexKey is base64 encoded 32 byte key of email blind index.
+	IndexKey string `env:"ENCRYPTION_INDEX_KEY"`
+}
+
+// Enabled reports whether any student field is encrypted.
+func (ec EncryptionConfig) Enabled() bool {
+	return len(ec.Fields) > 0
+}
+
+// KeyList returns encryption keys, the current key first.
+func (ec EncryptionConfig) KeyList() []string {
+	var keys []string
+	for _, key := range strings.Split(ec.Keys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// Encrypted reports whether the stored student field is encrypted.
+func (ec EncryptionConfig) Encrypted(field string) bool {
+	for _, f := range ec.Fields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Conditions of automatic status transitions.
+const (
+	// ConditionProjectsFinished holds for RSS students who submitted all required projects.
+	ConditionProjectsFinished = "projectsFinished"
+	// ConditionAtRisk holds for RSS students breaking any at-risk rule.
+	ConditionAtRisk = "atRisk"
+)
+
+// WorkflowConfig is the student status workflow, students may change status only by listed transitions.
+type WorkflowConfig struct {
+	// InitialStatus is set to imported students without status, no status is set when it is empty.
+	InitialStatus string `yaml:"initialStatus"`
+	// Transitions lists statuses a student may change to from each status.
+	Transitions map[string][]string `yaml:"transitions"`
+	// Automatic transitions are checked in order for students of every imported file.
+	Automatic []AutomaticTransition `yaml:"automatic"`
+}
+
+// AutomaticTransition changes status of students matching both condition and filter, unset ones always match.
+type AutomaticTransition struct {
+	From      string `yaml:"from"`
+	To        string `yaml:"to"`
+	Condition string `yaml:"condition"`
+	// Filter is a student filter expression, e.g. student_wac.attended_events>2.
+	Filter string `yaml:"filter"`
+	Reason string `yaml:"reason"`
+}
+
+// Statuses returns all statuses of the workflow sorted by name.
+func (wc WorkflowConfig) Statuses() []string {
+	known := map[string]bool{}
+	if wc.InitialStatus != "" {
+		known[wc.InitialStatus] = true
+	}
+	for from, to := range wc.Transitions {
+		known[from] = true
+		for _, status := range to {
+			known[status] = true
+		}
+	}
+
+	statuses := make([]string, 0, len(known))
+	for status := range known {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	return statuses
+}
+
+// Allows reports whether the workflow has transition from status to status.
+func (wc WorkflowConfig) Allows(from string, to string) bool {
+	for _, status := range wc.Transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
 func Load(transport Transport) *Config {
 	cfg := Config{Transport: transport}
 
@@ -21,6 +21,22 @@ func validateConfig(cfg *Config) error {
 		return err
 	}
 
+	if err := validateTrashConfig(cfg); err != nil {
+		return err
+	}
+
+	if err := validateWorkflowConfig(cfg); err != nil {
+		return err
+	}
+
+	if err := validateEncryptionConfig(cfg); err != nil {
+		return err
+	}
+
+	if err := validateQualityConfig(cfg); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -114,6 +130,86 @@ func validateStorageConfig(cfg *Config) error {
 	return nil
 }
 
+func validateTrashConfig(cfg *Config) error {
+	if cfg.Trash.RetentionDays < 0 {
+		return fmt.Errorf("application config. trash retention days should not be negative")
+	}
+
+	if cfg.Trash.RetentionDays > 0 && cfg.Trash.PurgeIntervalMinutes <= 0 {
+		return buildError("trash purge interval")
+	}
+
+	return nil
+}
+
+func validateWorkflowConfig(cfg *Config) error {
+	wc := cfg.Workflow
+	if wc.InitialStatus != "" && len(wc.Transitions) == 0 {
+		return buildError("workflow transitions")
+	}
+
+	for i, rule := range wc.Automatic {
+		if !wc.Allows(rule.From, rule.To) {
+			return fmt.Errorf("application config. workflow automatic transition %d from %q to %q is not allowed", i, rule.From, rule.To)
+		}
+		if rule.Condition != "" && rule.Condition != ConditionProjectsFinished && rule.Condition != ConditionAtRisk {
+			return fmt.Errorf("application config. workflow automatic transition %d has unknown condition %q", i, rule.Condition)
+		}
+		if rule.Condition == "" && rule.Filter == "" {
+			return fmt.Errorf("application config. workflow automatic transition %d should have condition or filter", i)
+		}
+	}
+
+	return nil
+}
+
+func validateEncryptionConfig(cfg *Config) error {
+	ec := cfg.Encryption
+	if !ec.Enabled() && len(ec.KeyList()) == 0 {
+		return nil
+	}
+
+	for _, field := range ec.Fields {
+		known := false
+		for _, encryptable := range EncryptableFields {
+			known = known || field == encryptable
+		}
+		if !known {
+			return fmt.Errorf("application config. field %q can not be encrypted", field)
+		}
+	}
+
+	if len(ec.KeyList()) == 0 {
+		return buildError("encryption keys")
+	}
+	if ec.IndexKey == "" {
+		return buildError("encryption index key")
+	}
+
+	return nil
+}
+
+func validateQualityConfig(cfg *Config) error {
+	qc := cfg.Quality
+	if qc.MaxScore != 0 && qc.MaxScore < qc.MinScore {
+		return fmt.Errorf("application config. quality max score should not be less than min score")
+	}
+
+	for source, fields := range qc.RequiredFields {
+		for _, field := range fields {
+			known := false
+			for _, qualityField := range QualityFields {
+				known = known || field == qualityField
+			}
+			if !known {
+				return fmt.Errorf("application config. quality required field %q of %s is unknown", field, source)
+			}
+		}
+	}
+
+	return nil
+}
+
 func buildError(key string) error {
 	return fmt.Errorf("application config. %s is not specified", key)
 }
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// annotationKeyPattern restricts annotation keys, so they are safe to use in stored field paths.
+var annotationKeyPattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// Tag labels student independently of imported data.
+type Tag struct {
+	Name    string    `json:"name" bson:"name"`
+	AddedBy string    `json:"added_by,omitempty" bson:"added_by,omitempty"`
+	AddedAt time.Time `json:"added_at" bson:"added_at"`
+}
+
+// Note is a staff note on student, e.g. interview notes.
+type Note struct {
+	ID        string     `json:"id" bson:"id"`
+	Text      string     `json:"text" bson:"text"`
+	Author    string     `json:"author,omitempty" bson:"author,omitempty"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// Annotation is a custom staff value of student stored by key, e.g. interview score.
+type Annotation struct {
+	Value string    `json:"value" bson:"value"`
+	SetBy string    `json:"set_by,omitempty" bson:"set_by,omitempty"`
+	SetAt time.Time `json:"set_at" bson:"set_at"`
+}
+
+type TagInput struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+type NoteInput struct {
+	Text string `json:"text" validate:"required,max=5000"`
+}
+
+type AnnotationInput struct {
+	Value string `json:"value" validate:"required,max=1000"`
+}
+
+// NormalizeTag returns tag name in the form tags are stored and compared in.
+func NormalizeTag(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// ValidateAnnotationKey checks key consists of lowercase letters, digits and underscores only.
+func ValidateAnnotationKey(key string) error {
+	if !annotationKeyPattern.MatchString(key) {
+		return fmt.Errorf("%w: annotation key should be 1 to 50 lowercase letters, digits or underscores", ErrInvalidParams)
+	}
+
+	return nil
+}
+
+// HasTag reports whether student is tagged with name.
+func (s *StudentRecord) HasTag(name string) bool {
+	for _, tag := range s.Tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
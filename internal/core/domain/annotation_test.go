@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{"unchanged", "scholarship", "scholarship"},
+		{"surrounding spaces", "  top talent ", "top talent"},
+		{"inner spaces", "top \t  talent", "top talent"},
+		{"blank", "   ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTag(tt.tag); got != tt.want {
+				t.Errorf("NormalizeTag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAnnotationKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{"valid", "interview_score_2", false},
+		{"empty", "", true},
+		{"uppercase", "Score", true},
+		{"dot", "interview.score", true},
+		{"dollar", "$score", true},
+		{"too long", "abcdefghijabcdefghijabcdefghijabcdefghijabcdefghijk", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAnnotationKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAnnotationKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("ValidateAnnotationKey() error = %v, want ErrInvalidParams", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
+)
+
+// Bulk operation types.
+const (
+	BulkSetField  = "set_field"
+	BulkAddTag    = "add_tag"
+	BulkRemoveTag = "remove_tag"
+	BulkDelete    = "delete"
+	// BulkTransition changes status of every student by a transition of the workflow.
+	BulkTransition = "transition"
+)
+
+// MaxBulkIDs limits the number of ids of a single bulk operation and the number of students matched by its filter.
+const MaxBulkIDs = 1000
+
+// ErrBulkTooLarge is returned when bulk operation filter matches more than MaxBulkIDs students.
+var ErrBulkTooLarge = fmt.Errorf("%w: filter matches more than %d students", ErrInvalidParams, MaxBulkIDs)
+
+// StudentBulkFields whitelists fields bulk operations can set. Status is changed by transition operation only.
+var StudentBulkFields = query.Fields{
+	"student_wac.location":                   query.String,
+	"student_wac.position":                   query.String,
+	"student_wac.company":                    query.String,
+	"student_wac.membership_type":            query.String,
+	"student_wac.receives_community_updates": query.Bool,
+	"student_wac.attended_events":            query.Number,
+	"student_wac.registered_not_visited":     query.Number,
+	"student_wac.registered":                 query.Number,
+}
+
+// bulkValueTypes names value types of StudentBulkFields.
+var bulkValueTypes = map[query.Type]string{
+	query.String: "string",
+	query.Number: "non-negative whole number",
+	query.Bool:   "boolean",
+}
+
+// BulkStudentsInput selects students by ids or by filter expression and the operation applied to them.
+type BulkStudentsInput struct {
+	IDs       []string      `json:"ids" validate:"omitempty,max=1000,dive,required"`
+	Filter    string        `json:"filter"`
+	Operation BulkOperation `json:"operation" validate:"required"`
+}
+
+type BulkOperation struct {
+	Type string `json:"type" validate:"required,oneof=set_field add_tag remove_tag delete transition"`
+	// Field and Value are set by set_field operation.
+	Field string      `json:"field,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+	// Tag is added or removed by add_tag and remove_tag operations.
+	Tag string `json:"tag,omitempty"`
+	// Status and Reason are set by transition operation, students are changed only if the workflow allows it.
+	Status string `json:"status,omitempty"`
+	Reason string `json:"reason,omitempty" validate:"max=1000"`
+}
+
+// BulkStudentsOptions are validated BulkStudentsInput with parsed filter.
+type BulkStudentsOptions struct {
+	IDs       []string
+	Filter    query.Expr
+	Operation BulkOperation
+}
+
+type BulkResult struct {
+	// Matched is the number of selected students, Modified is the number of students changed by the operation.
+	Matched  int64           `json:"matched"`
+	Modified int64           `json:"modified"`
+	Errors   []BulkItemError `json:"errors"`
+}
+
+// BulkItemError is an error of the operation on a single student.
+type BulkItemError struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// Validate checks the operation has members its type requires and set_field value matches the field type.
+// Whole numbers are converted to int, so they are stored the same way as imported ones.
+func (o *BulkOperation) Validate() error {
+	switch o.Type {
+	case BulkSetField:
+		fieldType, ok := StudentBulkFields[o.Field]
+		if !ok {
+			return fmt.Errorf("%w: field %q can not be set", ErrInvalidParams, o.Field)
+		}
+		if o.Value == nil {
+			return fmt.Errorf("%w: value is required", ErrInvalidParams)
+		}
+
+		switch fieldType {
+		case query.String:
+			_, ok = o.Value.(string)
+		case query.Bool:
+			_, ok = o.Value.(bool)
+		case query.Number:
+			var n float64
+			if n, ok = o.Value.(float64); ok && n >= 0 && n == math.Trunc(n) {
+				o.Value = int(n)
+			} else {
+				ok = false
+			}
+		}
+		if !ok {
+			return fmt.Errorf("%w: value of %s should be a %s", ErrInvalidParams, o.Field, bulkValueTypes[fieldType])
+		}
+	case BulkAddTag, BulkRemoveTag:
+		o.Tag = NormalizeTag(o.Tag)
+		if o.Tag == "" {
+			return fmt.Errorf("%w: tag is required", ErrInvalidParams)
+		}
+	case BulkTransition:
+		if o.Status == "" {
+			return fmt.Errorf("%w: status is required", ErrInvalidParams)
+		}
+	case BulkDelete:
+	default:
+		return fmt.Errorf("%w: unknown operation %q", ErrInvalidParams, o.Type)
+	}
+
+	return nil
+}
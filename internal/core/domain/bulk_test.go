@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBulkOperationValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		op        BulkOperation
+		wantValue interface{}
+		wantErr   bool
+	}{
+		{"set string", BulkOperation{Type: BulkSetField, Field: "student_wac.location", Value: "Tatooine"}, "Tatooine", false},
+		{"whole number is converted", BulkOperation{Type: BulkSetField, Field: "student_wac.registered", Value: 3.0}, 3, false},
+		{"fractional number", BulkOperation{Type: BulkSetField, Field: "student_wac.registered", Value: 2.5}, 2.5, true},
+		{"wrong type", BulkOperation{Type: BulkSetField, Field: "student_wac.receives_community_updates", Value: "yes"}, "yes", true},
+		{"field is not allowed", BulkOperation{Type: BulkSetField, Field: "email", Value: "a@b.c"}, "a@b.c", true},
+		{"missing value", BulkOperation{Type: BulkSetField, Field: "student_wac.location"}, nil, true},
+		{"status is set by workflow only", BulkOperation{Type: BulkSetField, Field: "status", Value: "accepted"}, "accepted", true},
+		{"add tag", BulkOperation{Type: BulkAddTag, Tag: "scholarship"}, nil, false},
+		{"missing tag", BulkOperation{Type: BulkRemoveTag}, nil, true},
+		{"delete", BulkOperation{Type: BulkDelete}, nil, false},
+		{"transition", BulkOperation{Type: BulkTransition, Status: "accepted"}, nil, false},
+		{"transition without status", BulkOperation{Type: BulkTransition}, nil, true},
+		{"unknown operation", BulkOperation{Type: "archive"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.op.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("Validate() error = %v, want ErrInvalidParams", err)
+			}
+			if !reflect.DeepEqual(tt.op.Value, tt.wantValue) {
+				t.Errorf("Validate() value = %#v, want %#v", tt.op.Value, tt.wantValue)
+			}
+		})
+	}
+}
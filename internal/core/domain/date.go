@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// dateLayouts are layouts of dates found in imported files, dates without time zone are treated as UTC.
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"2006/01/02",
+	"02.01.2006",
+	"01/02/2006",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+}
+
+// ParseDate parses date string of any known layout.
+// Returns UTC time pointer or nil for empty and unknown dates.
+func ParseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			t = t.UTC()
+			return &t
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	day := time.Date(2022, 10, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  *time.Time
+	}{
+		{"", nil},
+		{"2022-10-05", &day},
+		{" 05.10.2022 ", &day},
+		{"10/05/2022", &day},
+		{"October 5, 2022", &day},
+		{"2022-10-05T12:00:00+02:00", timePtr(time.Date(2022, 10, 5, 10, 0, 0, 0, time.UTC))},
+		{"yesterday", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got := ParseDate(tt.value)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("ParseDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStudentRecordNormalize(t *testing.T) {
+	s := StudentRecord{
+		Email: " Obi@Jedi.Rules ",
+		StudentRSS: StudentRSS{
+			ApplicationDate: "2022-10-05",
+			Projects: []Project{
+				{Name: "Lightsaber", Score: 40, FinishedAt: "2022-10-05"},
+				{Name: "Force", Score: 50, Deadline: "unknown"},
+			},
+		},
+	}
+
+	s.Normalize()
+
+	if s.Email != "obi@jedi.rules" || s.TotalScore != 90 {
+		t.Errorf("Normalize() email = %q, total score = %d", s.Email, s.TotalScore)
+	}
+	if s.ApplicationTime == nil || s.Projects[0].FinishedTime == nil {
+		t.Error("Normalize() should parse dates")
+	}
+	if s.JoinTime != nil || s.Projects[1].DeadlineTime != nil {
+		t.Error("Normalize() should leave empty and unknown dates empty")
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
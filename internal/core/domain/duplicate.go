@@ -0,0 +1,19 @@
+package domain
+
+type DuplicateCandidate struct {
+	First   StudentRecord `json:"first"`
+	Second  StudentRecord `json:"second"`
+	Score   float64       `json:"score"`
+	Reasons []string      `json:"reasons"`
+}
+
+type FindDuplicatesOptions struct {
+	Source    string
+	Threshold float64
+	Limit     int
+}
+
+type MergeStudentsInput struct {
+	PrimaryID    string   `json:"primary_id" validate:"required"`
+	DuplicateIDs []string `json:"duplicate_ids" validate:"required,min=1,dive,required"`
+}
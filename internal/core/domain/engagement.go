@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// EngagementReport joins RSS students with WAC community members by email
+// and compares community participation with RSS project results.
+type EngagementReport struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	RSSStudents int       `json:"rss_students"`
+	WACMembers  int       `json:"wac_members"`
+	// Matched is the number of RSS students being WAC community members.
+	Matched int `json:"matched"`
+	// Correlations are calculated over matched students.
+	Correlations []Correlation `json:"correlations"`
+	// ByMembership compares community members with other RSS students.
+	ByMembership []EngagementGroup `json:"by_membership"`
+	// ByAttendance groups matched students by the number of attended events.
+	ByAttendance []EngagementGroup `json:"by_attendance"`
+	Students     []EngagementRow   `json:"students"`
+}
+
+// Correlation is Pearson correlation coefficient of two metrics.
+// Coefficient is nil when it is undefined, e.g. one of the metrics is constant.
+type Correlation struct {
+	X           string   `json:"x"`
+	Y           string   `json:"y"`
+	Samples     int      `json:"samples"`
+	Coefficient *float64 `json:"coefficient"`
+}
+
+type EngagementGroup struct {
+	Group          string  `json:"group"`
+	Students       int     `json:"students"`
+	AvgScore       float64 `json:"avg_score"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+type EngagementRow struct {
+	StudentID            string  `json:"student_id"`
+	Email                string  `json:"email"`
+	Name                 string  `json:"name"`
+	TotalScore           int     `json:"total_score"`
+	Submitted            int     `json:"submitted"`
+	CompletionRate       float64 `json:"completion_rate"`
+	CommunityMember      bool    `json:"community_member"`
+	AttendedEvents       int     `json:"attended_events"`
+	RegisteredNotVisited int     `json:"registered_not_visited"`
+	Registered           int     `json:"registered"`
+}
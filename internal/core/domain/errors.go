@@ -6,4 +6,11 @@ var (
 	ErrInternalError = errors.New("internal server error")
 	ErrNotFound      = errors.New("resource does not exist")
 	DuplicationError = errors.New("duplication error")
+	ErrSelfMerge     = errors.New("record can not be merged into itself")
+	ErrInvalidCursor = errors.New("invalid page cursor")
+	ErrUnknownColumn = errors.New("unknown column")
+	ErrInvalidParams = errors.New("invalid parameters")
+	ErrInvalidPatch  = errors.New("invalid patch")
+	// ErrPreconditionFailed is returned when the stored revision differs from the expected one.
+	ErrPreconditionFailed = errors.New("resource was modified")
 )
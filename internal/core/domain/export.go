@@ -0,0 +1,11 @@
+package domain
+
+// ExportStudentsOptions defines students export file format, columns and students to export.
+// All columns are exported when Columns is empty.
+// If SchemaID is set, students are exported in the layout of the schema and Columns are ignored.
+type ExportStudentsOptions struct {
+	Format   string
+	Columns  []string
+	SchemaID string
+	ListStudentsOptions
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// History actions.
+const (
+	HistoryCreated  = "created"
+	HistoryUpdated  = "updated"
+	HistoryDeleted  = "deleted"
+	HistoryRestored = "restored"
+	// HistoryUndeleted is recorded when a student is restored from trash.
+	HistoryUndeleted = "undeleted"
+)
+
+// Change channels.
+const (
+	ViaAPI    = "api"
+	ViaImport = "import"
+)
+
+type actorKey struct{}
+
+// Actor is the user and the channel a change is made by.
+type Actor struct {
+	UserID   string `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Username string `json:"username,omitempty" bson:"username,omitempty"`
+	Via      string `json:"via" bson:"via"`
+	FileName string `json:"file_name,omitempty" bson:"file_name,omitempty"`
+}
+
+// HistoryEntry is a version of student record.
+// Snapshot holds the record after the change, snapshots of deleted records have their deletion time set.
+type HistoryEntry struct {
+	ID        string        `json:"id" bson:"_id,omitempty"`
+	StudentID string        `json:"student_id" bson:"student_id"`
+	Version   int           `json:"version" bson:"version"`
+	Action    string        `json:"action" bson:"action"`
+	Actor     Actor         `json:"actor" bson:"actor"`
+	At        time.Time     `json:"at" bson:"at"`
+	Changes   []FieldChange `json:"changes" bson:"changes"`
+	// RestoredFrom is the version restored by the change.
+	RestoredFrom int           `json:"restored_from,omitempty" bson:"restored_from,omitempty"`
+	Snapshot     StudentRecord `json:"snapshot" bson:"snapshot"`
+}
+
+// FieldChange is a changed field with its dotted path, old and new values.
+type FieldChange struct {
+	Field string      `json:"field" bson:"field"`
+	Old   interface{} `json:"old" bson:"old"`
+	New   interface{} `json:"new" bson:"new"`
+}
+
+// ContextWithActor returns ctx carrying actor of the changes made with it.
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns actor of ctx, changes are considered made via API when ctx has no actor.
+func ActorFromContext(ctx context.Context) Actor {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	if !ok {
+		return Actor{Via: ViaAPI}
+	}
+
+	return actor
+}
@@ -0,0 +1,17 @@
+package domain
+
+// PageOptions defines requested page of a list.
+// Cursor continues the list after the last item of the previous page, Skip is used only without Cursor.
+type PageOptions struct {
+	Cursor    string
+	Limit     int
+	Skip      int
+	WithTotal bool
+}
+
+// PageInfo describes a returned page of a list.
+type PageInfo struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int64 `json:"total,omitempty"`
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"fmt"
+	"net/mail"
+)
+
+// Patch media types.
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// StudentPatch is a JSON Merge Patch or JSON Patch document of student record JSON representation.
+type StudentPatch struct {
+	Type string
+	Data []byte
+}
+
+// Validate checks student record fields which can not be checked by their types.
+// Returns an error wrapping ErrInvalidParams.
+func (s *StudentRecord) Validate() error {
+	if s.Source != RSS && s.Source != WAC {
+		return fmt.Errorf("%w: source should be one of %s, %s", ErrInvalidParams, RSS, WAC)
+	}
+
+	if s.Email != "" {
+		if _, err := mail.ParseAddress(s.Email); err != nil {
+			return fmt.Errorf("%w: email %q is invalid", ErrInvalidParams, s.Email)
+		}
+	}
+
+	for i, item := range s.StatusItems {
+		if item == "" {
+			return fmt.Errorf("%w: status_items[%d] should not be empty", ErrInvalidParams, i)
+		}
+	}
+
+	for i, p := range s.Projects {
+		if p.Name == "" {
+			return fmt.Errorf("%w: projects[%d].name should not be empty", ErrInvalidParams, i)
+		}
+		if p.Score < 0 {
+			return fmt.Errorf("%w: projects[%d].score should not be negative", ErrInvalidParams, i)
+		}
+	}
+
+	counters := []struct {
+		name  string
+		value int
+	}{
+		{"attended_events", s.AttendedEvents},
+		{"registered_not_visited", s.RegisteredNotVisited},
+		{"registered", s.Registered},
+	}
+	for _, c := range counters {
+		if c.value < 0 {
+			return fmt.Errorf("%w: %s should not be negative", ErrInvalidParams, c.name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+const (
+	TimelineImported = "imported"
+	TimelineMerged   = "merged"
+)
+
+// PersonProfile combines every student record of one person found by normalized email.
+type PersonProfile struct {
+	Email     string                     `json:"email"`
+	Identity  PersonIdentity             `json:"identity"`
+	Sources   map[string][]StudentRecord `json:"sources"`
+	Timeline  []TimelineEvent            `json:"timeline"`
+	Conflicts []FieldConflict            `json:"conflicts"`
+}
+
+// PersonIdentity holds the most recent non-empty identity values among person records.
+type PersonIdentity struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	FullName  string `json:"full_name"`
+	Location  string `json:"location"`
+	Company   string `json:"company"`
+	Position  string `json:"position"`
+}
+
+type TimelineEvent struct {
+	At        time.Time `json:"at"`
+	Event     string    `json:"event"`
+	Source    string    `json:"source"`
+	FileName  string    `json:"file_name"`
+	StudentID string    `json:"student_id"`
+}
+
+// FieldConflict lists different values of the same field found in person records.
+type FieldConflict struct {
+	Field  string         `json:"field"`
+	Values []SourcedValue `json:"values"`
+}
+
+type SourcedValue struct {
+	Value     string `json:"value"`
+	Source    string `json:"source"`
+	StudentID string `json:"student_id"`
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// PersonExport holds every stored piece of personal data of a person found by email.
+type PersonExport struct {
+	Email      string    `json:"email"`
+	ExportedAt time.Time `json:"exported_at"`
+	// Records are student records of the person including ones in trash and the ones merged with them.
+	Records []StudentRecord   `json:"records"`
+	Imports []ImportReference `json:"imports"`
+	History []HistoryEntry    `json:"history"`
+}
+
+// ImportReference is a source file person data was imported from.
+type ImportReference struct {
+	FileName   string    `json:"file_name"`
+	Source     string    `json:"source"`
+	StudentID  string    `json:"student_id"`
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+type EraseInput struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
+// Erasure is the audit record of personal data erasure. The email is kept hashed only,
+// so repeated requests for the same person can be matched without storing it.
+type Erasure struct {
+	ID          string    `json:"id" bson:"_id,omitempty"`
+	EmailHash   string    `json:"email_hash" bson:"email_hash"`
+	Reason      string    `json:"reason" bson:"reason"`
+	RequestedBy string    `json:"requested_by,omitempty" bson:"requested_by,omitempty"`
+	At          time.Time `json:"at" bson:"at"`
+	// Students and HistoryEntries are the numbers of removed documents.
+	Students       int64 `json:"students" bson:"students"`
+	HistoryEntries int64 `json:"history_entries" bson:"history_entries"`
+	// Files are stored source files the person rows were removed from.
+	Files []string `json:"files" bson:"files"`
+}
+
+// KeyRotationResult holds the numbers of documents re-encrypted with the current key.
+type KeyRotationResult struct {
+	Students       int64 `json:"students"`
+	HistoryEntries int64 `json:"history_entries"`
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
+)
+
+// At-risk reasons.
+const (
+	RiskLowScore        = "low_score"
+	RiskLateProjects    = "late_projects"
+	RiskMissingProjects = "missing_projects"
+)
+
+type LeaderboardOptions struct {
+	// Project ranks students by the project score, students are ranked by total score when empty.
+	Project string
+	Filter  query.Expr
+	Limit   int
+}
+
+// LeaderboardEntry is a ranked student, students with equal scores share the rank.
+type LeaderboardEntry struct {
+	Rank      int    `json:"rank"`
+	StudentID string `json:"student_id"`
+	Email     string `json:"email"`
+	Name      string `json:"name"`
+	Score     int    `json:"score"`
+}
+
+type Leaderboard struct {
+	Project string             `json:"project,omitempty"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+type ProgressOptions struct {
+	Project string
+	Filter  query.Expr
+	// AtRisk selects at-risk students only.
+	AtRisk bool
+}
+
+// LateSubmission is a project finished after its deadline.
+type LateSubmission struct {
+	StudentID  string    `json:"student_id"`
+	Email      string    `json:"email"`
+	Name       string    `json:"name"`
+	Project    string    `json:"project"`
+	Score      int       `json:"score"`
+	FinishedAt time.Time `json:"finished_at"`
+	Deadline   time.Time `json:"deadline"`
+	DelayHours float64   `json:"delay_hours"`
+}
+
+// StudentProgress sums up RSS student projects against the required ones.
+type StudentProgress struct {
+	StudentID  string `json:"student_id"`
+	Email      string `json:"email"`
+	Name       string `json:"name"`
+	TotalScore int    `json:"total_score"`
+	// AverageScore is total score divided by the number of required projects.
+	AverageScore  float64  `json:"average_score"`
+	Submitted     []string `json:"submitted"`
+	Late          []string `json:"late"`
+	Missing       []string `json:"missing"`
+	AtRisk        bool     `json:"at_risk"`
+	AtRiskReasons []string `json:"at_risk_reasons"`
+}
+
+// IsLate reports whether project was finished after its deadline.
+// Parsed times are used when present, otherwise string dates are parsed.
+// Returns finish time, deadline and the result.
+func (p *Project) IsLate() (time.Time, time.Time, bool) {
+	finished, deadline := p.FinishedTime, p.DeadlineTime
+	if finished == nil {
+		finished = ParseDate(p.FinishedAt)
+	}
+	if deadline == nil {
+		deadline = ParseDate(p.Deadline)
+	}
+	if finished == nil || deadline == nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	return *finished, *deadline, finished.After(*deadline)
+}
+
+// IsSubmitted reports whether project has a score or a finish date.
+func (p *Project) IsSubmitted() bool {
+	return p.Score > 0 || p.FinishedAt != "" || p.FinishedTime != nil
+}
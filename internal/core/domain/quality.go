@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// QualityOptions restricts students included into the data quality report.
+type QualityOptions struct {
+	Source string
+}
+
+// QualityMetrics counts data quality issues of students. A student may have several issues of a kind,
+// e.g. several unparsable dates, StudentsWithIssues counts every student once.
+type QualityMetrics struct {
+	Students           int64 `json:"students"`
+	StudentsWithIssues int64 `json:"students_with_issues"`
+	MissingEmails      int64 `json:"missing_emails"`
+	InvalidEmails      int64 `json:"invalid_emails"`
+	// DuplicateEmails is the number of students sharing email with another student of the same source.
+	DuplicateEmails  int64 `json:"duplicate_emails"`
+	OutOfRangeScores int64 `json:"out_of_range_scores"`
+	UnparsableDates  int64 `json:"unparsable_dates"`
+	// EmptyRequiredFields sums EmptyFields, which counts empty required fields by field name.
+	EmptyRequiredFields int64            `json:"empty_required_fields"`
+	EmptyFields         map[string]int64 `json:"empty_fields,omitempty"`
+}
+
+type SourceQuality struct {
+	Source string `json:"source"`
+	QualityMetrics
+}
+
+// ImportQuality are metrics of students last imported from the file.
+type ImportQuality struct {
+	FileName   string    `json:"file_name"`
+	Source     string    `json:"source"`
+	ImportedAt time.Time `json:"imported_at"`
+	QualityMetrics
+}
+
+type QualityReport struct {
+	Total    QualityMetrics  `json:"total"`
+	BySource []SourceQuality `json:"by_source"`
+	// ByImport is ordered by import time, the most recent first.
+	ByImport []ImportQuality `json:"by_import"`
+}
+
+// HasIssues reports whether metrics count any issue.
+func (m *QualityMetrics) HasIssues() bool {
+	return m.MissingEmails+m.InvalidEmails+m.DuplicateEmails+m.OutOfRangeScores+m.UnparsableDates+m.EmptyRequiredFields > 0
+}
+
+// Add adds other metrics to m.
+func (m *QualityMetrics) Add(other QualityMetrics) {
+	m.Students += other.Students
+	m.StudentsWithIssues += other.StudentsWithIssues
+	m.MissingEmails += other.MissingEmails
+	m.InvalidEmails += other.InvalidEmails
+	m.DuplicateEmails += other.DuplicateEmails
+	m.OutOfRangeScores += other.OutOfRangeScores
+	m.UnparsableDates += other.UnparsableDates
+	m.EmptyRequiredFields += other.EmptyRequiredFields
+	for field, count := range other.EmptyFields {
+		if m.EmptyFields == nil {
+			m.EmptyFields = make(map[string]int64, len(other.EmptyFields))
+		}
+		m.EmptyFields[field] += count
+	}
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/abdukhashimov/student_aggregator/pkg/parser"
+import (
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/pkg/parser"
+)
 
 type Schema struct {
 	ID         string        `json:"id" bson:"_id,omitempty"`
@@ -10,6 +14,29 @@ type Schema struct {
 	SchemaType string        `json:"schema_type" bson:"schema_type"`
 	Headers    bool          `json:"headers" bson:"headers"`
 	Fields     []FieldSchema `json:"fields"  bson:"fields"`
+	Lookups    []LookupTable `json:"lookups,omitempty" bson:"lookups,omitempty"`
+	Options    *ReadOptions  `json:"options,omitempty" bson:"options,omitempty"`
+	GroupBy    string        `json:"group_by,omitempty" bson:"group_by,omitempty"`
+	// Revision is incremented on every update, schemas created before revisions were introduced have 0.
+	Revision int `json:"revision,omitempty" bson:"revision,omitempty"`
+	// DeletedAt is set when the schema is moved to trash, DeletedBy is the username of the user who did it.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
+}
+
+// SchemaSortFields whitelists fields available for schemas sorting.
+var SchemaSortFields = SortFields{
+	"id":          "_id",
+	"name":        "name",
+	"version":     "version",
+	"schema_type": "schema_type",
+}
+
+type ListSchemasOptions struct {
+	Sort []SortField
+	// WithDeleted includes schemas moved to trash.
+	WithDeleted bool
+	PageOptions
 }
 
 type FieldSchema struct {
@@ -18,6 +45,29 @@ type FieldSchema struct {
 	IsMultiple bool   `json:"is_multiple" bson:"is_multiple"`
 	IsMap      bool   `json:"is_map" bson:"is_map"`
 	MapStart   bool   `json:"map_start" bson:"map_start"`
+	Lookup     string `json:"lookup,omitempty" bson:"lookup,omitempty"`
+	IsDate     bool   `json:"is_date,omitempty" bson:"is_date,omitempty"`
+}
+
+// ReadOptions defines how spreadsheet cells are read during parsing.
+type ReadOptions struct {
+	CalcFormulas bool `json:"calc_formulas" bson:"calc_formulas"`
+	// IgnoreMergedCells leaves cells covered by merged ranges empty, they hold the range value by default.
+	IgnoreMergedCells bool `json:"ignore_merged_cells" bson:"ignore_merged_cells"`
+	Hyperlinks        bool `json:"hyperlinks" bson:"hyperlinks"`
+}
+
+// LookupTable maps raw spreadsheet values to labels, e.g. membership codes to membership names.
+// OnUnknown defines what happens with values missing in the table: keep (default), null or error.
+type LookupTable struct {
+	Name      string        `json:"name" bson:"name" validate:"required"`
+	Values    []LookupValue `json:"values" bson:"values" validate:"required"`
+	OnUnknown string        `json:"on_unknown" bson:"on_unknown,omitempty" validate:"omitempty,oneof=keep null error"`
+}
+
+type LookupValue struct {
+	From string `json:"from" bson:"from"`
+	To   string `json:"to" bson:"to"`
 }
 
 type NewSchemaInput struct {
@@ -26,6 +76,9 @@ type NewSchemaInput struct {
 	SchemaType string        `json:"schema_type" validate:"required"`
 	Headers    bool          `json:"headers" validate:"required"`
 	Fields     []FieldSchema `json:"fields" validate:"required"`
+	Lookups    []LookupTable `json:"lookups" validate:"omitempty,dive"`
+	Options    *ReadOptions  `json:"options"`
+	GroupBy    string        `json:"group_by" validate:"omitempty,alpha"`
 }
 
 type UpdateSchemaInput struct {
@@ -35,6 +88,9 @@ type UpdateSchemaInput struct {
 	SchemaType *string        `json:"schema_type" bson:"schema_type,omitempty" validate:"omitempty,required"`
 	Headers    *bool          `json:"headers" bson:"headers,omitempty" validate:"omitempty,required"`
 	Fields     *[]FieldSchema `json:"fields" bson:"fields,omitempty" validate:"omitempty,required"`
+	Lookups    *[]LookupTable `json:"lookups" bson:"lookups,omitempty" validate:"omitempty,dive"`
+	Options    *ReadOptions   `json:"options" bson:"options,omitempty"`
+	GroupBy    *string        `json:"group_by" bson:"group_by,omitempty" validate:"omitempty,alpha"`
 }
 
 func (s *Schema) ConvertToParserSchema() parser.Schema {
@@ -46,13 +102,40 @@ func (s *Schema) ConvertToParserSchema() parser.Schema {
 			IsMultiple: v.IsMultiple,
 			IsMap:      v.IsMap,
 			MapStart:   v.MapStart,
+			Lookup:     v.Lookup,
+			IsDate:     v.IsDate,
+		})
+	}
+
+	var lookups []parser.LookupTable
+	for _, v := range s.Lookups {
+		values := make(map[string]string, len(v.Values))
+		for _, lv := range v.Values {
+			values[lv.From] = lv.To
+		}
+		lookups = append(lookups, parser.LookupTable{
+			Name:      v.Name,
+			Values:    values,
+			OnUnknown: v.OnUnknown,
 		})
 	}
 
+	var options parser.ReadOptions
+	if s.Options != nil {
+		options = parser.ReadOptions{
+			CalcFormulas:      s.Options.CalcFormulas,
+			IgnoreMergedCells: s.Options.IgnoreMergedCells,
+			Hyperlinks:        s.Options.Hyperlinks,
+		}
+	}
+
 	return parser.Schema{
 		Version:    s.Version,
 		SchemaType: s.SchemaType,
 		Headers:    s.Headers,
 		Fields:     fields,
+		Lookups:    lookups,
+		Options:    options,
+		GroupBy:    s.GroupBy,
 	}
 }
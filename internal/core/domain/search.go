@@ -0,0 +1,16 @@
+package domain
+
+// SearchStudentsOptions holds full-text search query and its restrictions.
+type SearchStudentsOptions struct {
+	Query  string
+	Source string
+	Limit  int
+}
+
+// StudentSearchResult is a student matching search query with its relevance
+// and matched fields values where the query terms are wrapped with <em></em>.
+type StudentSearchResult struct {
+	Student    StudentRecord     `json:"student" bson:",inline"`
+	Score      float64           `json:"score" bson:"score"`
+	Highlights map[string]string `json:"highlights" bson:"-"`
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const maxSortFields = 5
+
+// SortFields whitelists sortable fields of a resource mapping their public names to storage field paths.
+type SortFields map[string]string
+
+// SortField is a storage field path and its sort direction.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses comma separated list of sort fields like "-score,last_name",
+// where "-" prefix means descending and "+" or no prefix means ascending order.
+// Returns storage sort fields and an error for unknown or repeated fields.
+func ParseSort(input string, fields SortFields) ([]SortField, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(input, ",")
+	if len(parts) > maxSortFields {
+		return nil, fmt.Errorf("sort: at most %d fields are allowed", maxSortFields)
+	}
+
+	result := make([]SortField, 0, len(parts))
+	seen := make(map[string]bool, len(parts))
+	for _, part := range parts {
+		name := strings.TrimSpace(part)
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimLeft(name, "+-")
+
+		field, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("sort: unknown field %q, allowed fields are %s", name, fields.names())
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("sort: field %q is repeated", name)
+		}
+		seen[name] = true
+
+		result = append(result, SortField{Field: field, Desc: desc})
+	}
+
+	return result, nil
+}
+
+func (sf SortFields) names() string {
+	names := make([]string, 0, len(sf))
+	for name := range sf {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
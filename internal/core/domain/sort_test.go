@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	fields := SortFields{
+		"id":        "_id",
+		"score":     "student_rss.total_score",
+		"last_name": "student_rss.last_name",
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []SortField
+		wantErr bool
+	}{
+		{"empty", " ", nil, false},
+		{
+			"several fields",
+			"-score, last_name,+id",
+			[]SortField{{"student_rss.total_score", true}, {"student_rss.last_name", false}, {"_id", false}},
+			false,
+		},
+		{"unknown field", "score,password", nil, true},
+		{"storage path is not accepted", "student_rss.last_name", nil, true},
+		{"repeated field", "score,-score", nil, true},
+		{"empty field", "score,", nil, true},
+		{"too many fields", "id,id,id,id,id,id", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSort(tt.input, fields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSort() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
+)
+
+// StudentsStatsOptions restricts students included into statistics.
+type StudentsStatsOptions struct {
+	Source string
+	Filter query.Expr
+}
+
+type StudentsStats struct {
+	Total            int64               `json:"total"`
+	BySource         []CountItem         `json:"by_source"`
+	ByStatus         []CountItem         `json:"by_status"`
+	ByFile           []FileCount         `json:"by_file"`
+	ByLocation       []CountItem         `json:"by_location"`
+	ByMembershipType []CountItem         `json:"by_membership_type"`
+	Projects         []ProjectScoreStats `json:"projects"`
+	Attendance       AttendanceStats     `json:"attendance"`
+}
+
+// CountItem is a number of students having the value.
+type CountItem struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// FileCount is a number of students imported from the file and the last import time.
+type FileCount struct {
+	FileName   string    `json:"file_name"`
+	Count      int64     `json:"count"`
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+// ProjectScoreStats describes distribution of RSS project scores.
+// Percentiles are keyed by name like "p50".
+type ProjectScoreStats struct {
+	Project     string             `json:"project"`
+	Count       int64              `json:"count"`
+	Min         float64            `json:"min"`
+	Max         float64            `json:"max"`
+	Avg         float64            `json:"avg"`
+	Percentiles map[string]float64 `json:"percentiles"`
+}
+
+// AttendanceStats sums WAC events registrations and visits.
+// Rate is a share of attended events among registered ones.
+type AttendanceStats struct {
+	Students             int64   `json:"students"`
+	Registered           int64   `json:"registered"`
+	Attended             int64   `json:"attended"`
+	RegisteredNotVisited int64   `json:"registered_not_visited"`
+	Rate                 float64 `json:"rate"`
+}
@@ -8,3 +8,9 @@ type PutFileOptions struct {
 	Size        int64
 	ContentType string
 }
+
+// FileInfo describes a stored file.
+type FileInfo struct {
+	Size        int64
+	ContentType string
+}
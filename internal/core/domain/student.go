@@ -1,17 +1,53 @@
 package domain
 
+import (
+	"strings"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
+)
+
 const (
 	RSS = "RSS"
 	WAC = "WAC"
 )
 
 type StudentRecord struct {
-	Source     string `json:"source" bson:"source"` // RSS, WAC
-	Email      string `json:"email" mapstructure:"email" bson:"email,omitempty"`
-	Status     string `json:"status" bson:"status,omitempty"`
-	FileName   string `json:"file_name" bson:"file_name,omitempty"`
-	StudentRSS `mapstructure:",squash" bson:"student_rss,omitempty"`
-	StudentWAC `mapstructure:",squash" bson:"student_wac,omitempty"`
+	ID     string `json:"id" mapstructure:"-" bson:"_id,omitempty"`
+	Source string `json:"source" bson:"source"` // RSS, WAC
+	Email  string `json:"email" mapstructure:"email" bson:"email,omitempty"`
+	// EmailIndex is the blind index of encrypted email, it is set by the repository only.
+	EmailIndex string `json:"-" mapstructure:"-" bson:"email_index,omitempty"`
+	// Status is changed by the workflow transitions only, StatusChanges records them.
+	Status   string `json:"status" bson:"status,omitempty"`
+	FileName string `json:"file_name" bson:"file_name,omitempty"`
+	// ImportedFrom lists every file the record is imported from, FileName is the last one.
+	ImportedFrom  []string  `json:"imported_from,omitempty" mapstructure:"-" bson:"imported_from,omitempty"`
+	ImportedAt    time.Time `json:"imported_at" mapstructure:"-" bson:"imported_at,omitempty"`
+	StudentRSS    `mapstructure:",squash" bson:"student_rss,omitempty"`
+	StudentWAC    `mapstructure:",squash" bson:"student_wac,omitempty"`
+	MergedFrom    []MergedRecord `json:"merged_from,omitempty" mapstructure:"-" bson:"merged_from,omitempty"`
+	StatusChanges []StatusChange `json:"status_changes,omitempty" mapstructure:"-" bson:"status_changes,omitempty"`
+	// Tags, Notes and Annotations are set by staff independently of imported data and are kept on re-import.
+	Tags        []Tag                 `json:"tags,omitempty" mapstructure:"-" bson:"tags,omitempty"`
+	Notes       []Note                `json:"notes,omitempty" mapstructure:"-" bson:"notes,omitempty"`
+	Annotations map[string]Annotation `json:"annotations,omitempty" mapstructure:"-" bson:"annotations,omitempty"`
+	// Revision is incremented on every update, records saved before revisions were introduced have 0.
+	Revision int `json:"revision,omitempty" mapstructure:"-" bson:"revision,omitempty"`
+	// DeletedAt is set when the record is moved to trash, DeletedBy is the username of the user who did it.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" mapstructure:"-" bson:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty" mapstructure:"-" bson:"deleted_by,omitempty"`
+}
+
+// MergedRecord keeps provenance of a duplicate record merged into another one.
+type MergedRecord struct {
+	ID     string `json:"id" bson:"id"`
+	Source string `json:"source" bson:"source"`
+	Email  string `json:"email" bson:"email,omitempty"`
+	// EmailIndex is the blind index of encrypted email, it is set by the repository only.
+	EmailIndex string    `json:"-" bson:"email_index,omitempty"`
+	FileName   string    `json:"file_name" bson:"file_name,omitempty"`
+	MergedAt   time.Time `json:"merged_at" bson:"merged_at"`
 }
 
 type StudentWAC struct {
@@ -26,6 +62,8 @@ type StudentWAC struct {
 	AttendedEvents           int      `json:"attended_events" mapstructure:"attended_events" bson:"attended_events,omitempty"`
 	RegisteredNotVisited     int      `json:"registered_not_visited" mapstructure:"registered_not_visited" bson:"registered_not_visited,omitempty"`
 	Registered               int      `json:"registered" mapstructure:"registered" bson:"registered,omitempty"`
+	// JoinTime is JoinDate parsed on save.
+	JoinTime *time.Time `json:"join_time,omitempty" mapstructure:"-" bson:"join_time,omitempty"`
 }
 
 type Project struct {
@@ -33,6 +71,9 @@ type Project struct {
 	Score      int    `json:"score" mapstructure:"score" bson:"score,omitempty"`
 	FinishedAt string `json:"finished_at" mapstructure:"finished_at" bson:"finished_at,omitempty"`
 	Deadline   string `json:"deadline" mapstructure:"deadline" bson:"deadline,omitempty"`
+	// FinishedTime and DeadlineTime are FinishedAt and Deadline parsed on save.
+	FinishedTime *time.Time `json:"finished_time,omitempty" mapstructure:"-" bson:"finished_time,omitempty"`
+	DeadlineTime *time.Time `json:"deadline_time,omitempty" mapstructure:"-" bson:"deadline_time,omitempty"`
 }
 
 type StudentRSS struct {
@@ -41,12 +82,224 @@ type StudentRSS struct {
 	StatusItems     []string  `json:"status_items" mapstructure:"status_items" bson:"status_items,omitempty"`
 	ApplicationDate string    `json:"application_date" mapstructure:"application_date" bson:"application_date,omitempty"`
 	Projects        []Project `json:"projects" mapstructure:"projects" bson:"projects,omitempty"`
+	TotalScore      int       `json:"total_score" mapstructure:"-" bson:"total_score,omitempty"`
+	// ApplicationTime is ApplicationDate parsed on save.
+	ApplicationTime *time.Time `json:"application_time,omitempty" mapstructure:"-" bson:"application_time,omitempty"`
+}
+
+// StudentFilterFields whitelists fields available in students filter expressions.
+var StudentFilterFields = query.Fields{
+	"source":                                 query.String,
+	"email":                                  query.String,
+	"status":                                 query.String,
+	"file_name":                              query.String,
+	"imported_at":                            query.Date,
+	"tags.name":                              query.String,
+	"tags.added_by":                          query.String,
+	"tags.added_at":                          query.Date,
+	"notes.text":                             query.String,
+	"notes.author":                           query.String,
+	"notes.created_at":                       query.Date,
+	"student_rss.first_name":                 query.String,
+	"student_rss.last_name":                  query.String,
+	"student_rss.status_items":               query.String,
+	"student_rss.application_date":           query.String,
+	"student_rss.projects.name":              query.String,
+	"student_rss.projects.score":             query.Number,
+	"student_rss.projects.finished_at":       query.String,
+	"student_rss.projects.deadline":          query.String,
+	"student_rss.projects.finished_time":     query.Date,
+	"student_rss.projects.deadline_time":     query.Date,
+	"student_rss.application_time":           query.Date,
+	"student_rss.total_score":                query.Number,
+	"student_wac.join_date":                  query.String,
+	"student_wac.full_name":                  query.String,
+	"student_wac.location":                   query.String,
+	"student_wac.position":                   query.String,
+	"student_wac.company":                    query.String,
+	"student_wac.preffered_language":         query.String,
+	"student_wac.receives_community_updates": query.Bool,
+	"student_wac.membership_type":            query.String,
+	"student_wac.attended_events":            query.Number,
+	"student_wac.registered_not_visited":     query.Number,
+	"student_wac.registered":                 query.Number,
+	"student_wac.join_time":                  query.Date,
+}
+
+// StudentSortFields whitelists fields available for students sorting.
+var StudentSortFields = SortFields{
+	"id":               "_id",
+	"email":            "email",
+	"source":           "source",
+	"status":           "status",
+	"file_name":        "file_name",
+	"imported_at":      "imported_at",
+	"first_name":       "student_rss.first_name",
+	"last_name":        "student_rss.last_name",
+	"application_date": "student_rss.application_time",
+	"score":            "student_rss.total_score",
+	"full_name":        "student_wac.full_name",
+	"join_date":        "student_wac.join_time",
+	"location":         "student_wac.location",
+	"company":          "student_wac.company",
+	"attended_events":  "student_wac.attended_events",
 }
 
 type ListStudentsOptions struct {
+	// IDs selects students by ids, invalid ids match nothing.
+	IDs    []string
 	Email  string
 	Source string
-	Sort   map[string]int
-	Limit  int
-	Skip   int
+	Status string
+	// FileName selects students last imported from the file.
+	FileName string
+	// Tag selects students tagged with it.
+	Tag    string
+	Filter query.Expr
+	Sort   []SortField
+	// WithDeleted includes students moved to trash.
+	WithDeleted bool
+	PageOptions
+}
+
+// Normalize prepares student record for saving: normalizes email, sums projects score
+// and parses string dates into timestamps, dates which can not be parsed are left empty.
+func (s *StudentRecord) Normalize() {
+	s.Email = NormalizeEmail(s.Email)
+	s.TotalScore = s.ProjectsScore()
+	s.ApplicationTime = ParseDate(s.ApplicationDate)
+	s.JoinTime = ParseDate(s.JoinDate)
+	for i := range s.Projects {
+		p := &s.Projects[i]
+		p.FinishedTime = ParseDate(p.FinishedAt)
+		p.DeadlineTime = ParseDate(p.Deadline)
+	}
+}
+
+// NormalizeEmail returns email in the form used to match records of the same person.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// DisplayName returns student name from RSS fields or WAC full name.
+func (s *StudentRecord) DisplayName() string {
+	if name := strings.TrimSpace(s.FirstName + " " + s.LastName); name != "" {
+		return name
+	}
+
+	return strings.TrimSpace(s.StudentWAC.FullName)
+}
+
+// Merge fills empty fields of s with values of record other, combines lists, keeps the largest counters
+// and provenance of other.
+func (s *StudentRecord) Merge(other StudentRecord, mergedAt time.Time) {
+	if s.Email == "" {
+		s.Email = other.Email
+	}
+	if s.Status == "" {
+		s.Status = other.Status
+	}
+	if s.FileName == "" {
+		s.FileName = other.FileName
+	}
+
+	rss := &s.StudentRSS
+	mergeString(&rss.FirstName, other.FirstName)
+	mergeString(&rss.LastName, other.LastName)
+	mergeString(&rss.ApplicationDate, other.ApplicationDate)
+	rss.StatusItems = mergeStrings(rss.StatusItems, other.StatusItems)
+	for _, project := range other.Projects {
+		if !rss.HasProject(project.Name) {
+			rss.Projects = append(rss.Projects, project)
+		}
+	}
+
+	wac := &s.StudentWAC
+	mergeString(&wac.JoinDate, other.JoinDate)
+	mergeString(&wac.FullName, other.StudentWAC.FullName)
+	mergeString(&wac.Location, other.Location)
+	mergeString(&wac.Position, other.Position)
+	mergeString(&wac.Company, other.Company)
+	mergeString(&wac.MembershipType, other.MembershipType)
+	wac.PrefferedLanguage = mergeStrings(wac.PrefferedLanguage, other.PrefferedLanguage)
+	wac.ReceivesCommunityUpdates = wac.ReceivesCommunityUpdates || other.ReceivesCommunityUpdates
+	// counters of one person are read from overlapping exports, the largest is the most recent one
+	mergeCount(&wac.AttendedEvents, other.AttendedEvents)
+	mergeCount(&wac.RegisteredNotVisited, other.RegisteredNotVisited)
+	mergeCount(&wac.Registered, other.Registered)
+
+	for _, tag := range other.Tags {
+		if !s.HasTag(tag.Name) {
+			s.Tags = append(s.Tags, tag)
+		}
+	}
+	s.Notes = append(s.Notes, other.Notes...)
+	for key, annotation := range other.Annotations {
+		if _, ok := s.Annotations[key]; ok {
+			continue
+		}
+		if s.Annotations == nil {
+			s.Annotations = make(map[string]Annotation, len(other.Annotations))
+		}
+		s.Annotations[key] = annotation
+	}
+
+	s.MergedFrom = append(s.MergedFrom, MergedRecord{
+		ID:       other.ID,
+		Source:   other.Source,
+		Email:    other.Email,
+		FileName: other.FileName,
+		MergedAt: mergedAt,
+	})
+	s.MergedFrom = append(s.MergedFrom, other.MergedFrom...)
+}
+
+// HasProject reports whether student has project with name.
+func (s *StudentRSS) HasProject(name string) bool {
+	for _, p := range s.Projects {
+		if p.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProjectsScore sums scores of student projects.
+func (s *StudentRSS) ProjectsScore() int {
+	score := 0
+	for _, p := range s.Projects {
+		score += p.Score
+	}
+
+	return score
+}
+
+func mergeString(dst *string, value string) {
+	if *dst == "" {
+		*dst = value
+	}
+}
+
+func mergeCount(dst *int, value int) {
+	if value > *dst {
+		*dst = value
+	}
+}
+
+func mergeStrings(dst []string, values []string) []string {
+	for _, v := range values {
+		found := false
+		for _, d := range dst {
+			if d == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst = append(dst, v)
+		}
+	}
+
+	return dst
 }
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStudentRecordMerge(t *testing.T) {
+	wac := StudentWAC{
+		FullName:             "Obi-Wan Kenobi",
+		Location:             "Tatooine",
+		AttendedEvents:       3,
+		RegisteredNotVisited: 1,
+		Registered:           4,
+	}
+
+	tests := []struct {
+		name  string
+		first StudentWAC
+		other StudentWAC
+		want  StudentWAC
+	}{
+		{
+			name:  "identical imports",
+			first: wac,
+			other: wac,
+			want:  wac,
+		},
+		{
+			name:  "later import",
+			first: wac,
+			other: StudentWAC{AttendedEvents: 5, RegisteredNotVisited: 0, Registered: 5},
+			want: StudentWAC{
+				FullName:             "Obi-Wan Kenobi",
+				Location:             "Tatooine",
+				AttendedEvents:       5,
+				RegisteredNotVisited: 1,
+				Registered:           5,
+			},
+		},
+		{
+			name:  "empty primary",
+			first: StudentWAC{},
+			other: wac,
+			want:  wac,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := StudentRecord{ID: "1", Source: WAC, Email: "obi@jedi.rules", StudentWAC: tt.first}
+			other := StudentRecord{ID: "2", Source: WAC, Email: "obi@jedi.rules", StudentWAC: tt.other}
+
+			s.Merge(other, time.Now())
+			if !reflect.DeepEqual(s.StudentWAC, tt.want) {
+				t.Errorf("Merge() = %+v, want %+v", s.StudentWAC, tt.want)
+			}
+			if len(s.MergedFrom) != 1 || s.MergedFrom[0].ID != "2" {
+				t.Errorf("Merge() merged from = %+v, want record 2", s.MergedFrom)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
+)
+
+const (
+	MetricApplications = "applications"
+	MetricJoins        = "joins"
+	MetricCompletions  = "completions"
+
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+	IntervalYear  = "year"
+)
+
+const GroupByProject = "project"
+
+var (
+	timeSeriesMetrics   = []string{MetricApplications, MetricJoins, MetricCompletions}
+	timeSeriesIntervals = []string{IntervalDay, IntervalWeek, IntervalMonth, IntervalYear}
+	timeSeriesGroups    = []string{"source", "status", "location", "membership_type", GroupByProject}
+)
+
+// TimeSeriesOptions defines counted metric, time bucket interval, optional group by dimension and date range.
+// From is inclusive and To is exclusive, zero values are not restricted.
+type TimeSeriesOptions struct {
+	Metric   string
+	Interval string
+	GroupBy  string
+	From     time.Time
+	To       time.Time
+	Source   string
+	Filter   query.Expr
+}
+
+type TimeSeries struct {
+	Metric   string   `json:"metric"`
+	Interval string   `json:"interval"`
+	GroupBy  string   `json:"group_by,omitempty"`
+	Series   []Series `json:"series"`
+}
+
+// Series are counts of one group per time bucket ordered by time.
+type Series struct {
+	Group  string      `json:"group,omitempty"`
+	Points []TimePoint `json:"points"`
+}
+
+type TimePoint struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count"`
+}
+
+// Validate checks that metric, interval and group are supported and date range is not empty.
+// Project group is available for completions only.
+func (o TimeSeriesOptions) Validate() error {
+	if !contains(timeSeriesMetrics, o.Metric) {
+		return fmt.Errorf("%w: metric should be one of %v", ErrInvalidParams, timeSeriesMetrics)
+	}
+	if !contains(timeSeriesIntervals, o.Interval) {
+		return fmt.Errorf("%w: interval should be one of %v", ErrInvalidParams, timeSeriesIntervals)
+	}
+	if o.GroupBy != "" && !contains(timeSeriesGroups, o.GroupBy) {
+		return fmt.Errorf("%w: group_by should be one of %v", ErrInvalidParams, timeSeriesGroups)
+	}
+	if o.GroupBy == GroupByProject && o.Metric != MetricCompletions {
+		return fmt.Errorf("%w: group_by %s is available for %s only", ErrInvalidParams, GroupByProject, MetricCompletions)
+	}
+	if !o.From.IsZero() && !o.To.IsZero() && !o.From.Before(o.To) {
+		return fmt.Errorf("%w: from should be before to", ErrInvalidParams)
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
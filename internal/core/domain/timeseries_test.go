@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeSeriesOptionsValidate(t *testing.T) {
+	valid := TimeSeriesOptions{Metric: MetricCompletions, Interval: IntervalWeek, GroupBy: GroupByProject}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	invalid := []TimeSeriesOptions{
+		{Metric: "logins", Interval: IntervalWeek},
+		{Metric: MetricJoins, Interval: "hour"},
+		{Metric: MetricJoins, Interval: IntervalWeek, GroupBy: "email"},
+		{Metric: MetricJoins, Interval: IntervalWeek, GroupBy: GroupByProject},
+		{Metric: MetricJoins, Interval: IntervalWeek, From: time.Now(), To: time.Now().Add(-time.Hour)},
+	}
+	for _, o := range invalid {
+		if err := o.Validate(); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("Validate(%+v) error = %v, want %v", o, err, ErrInvalidParams)
+		}
+	}
+}
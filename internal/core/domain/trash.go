@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// Trash item types.
+const (
+	TrashStudent = "student"
+	TrashSchema  = "schema"
+)
+
+type TrashOptions struct {
+	// Type selects items of the type only, items of all types are listed when empty.
+	Type  string
+	Limit int
+}
+
+// TrashItem is a student or a schema moved to trash.
+type TrashItem struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Name is student email or schema name.
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+	DeletedBy string    `json:"deleted_by,omitempty"`
+	// PurgeAt is the time the item is deleted permanently after, items are kept forever when it is not set.
+	PurgeAt *time.Time `json:"purge_at,omitempty"`
+}
+
+// PurgeResult counts items deleted permanently.
+type PurgeResult struct {
+	Students int64 `json:"students"`
+	Schemas  int64 `json:"schemas"`
+}
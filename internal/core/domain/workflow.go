@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTransitionNotAllowed is returned when the workflow has no transition between student statuses.
+var ErrTransitionNotAllowed = errors.New("status transition is not allowed")
+
+// StatusChange records a student status transition.
+type StatusChange struct {
+	From      string    `json:"from,omitempty" bson:"from,omitempty"`
+	To        string    `json:"to" bson:"to"`
+	Reason    string    `json:"reason,omitempty" bson:"reason,omitempty"`
+	ChangedBy string    `json:"changed_by,omitempty" bson:"changed_by,omitempty"`
+	ChangedAt time.Time `json:"changed_at" bson:"changed_at"`
+	// Automatic is set when the status is changed by the workflow on import.
+	Automatic bool `json:"automatic,omitempty" bson:"automatic,omitempty"`
+}
+
+type StatusInput struct {
+	Status string `json:"status" validate:"required"`
+	Reason string `json:"reason" validate:"max=1000"`
+}
+
+// Workflow describes student statuses and transitions between them.
+type Workflow struct {
+	InitialStatus string                `json:"initial_status,omitempty"`
+	Statuses      []string              `json:"statuses"`
+	Transitions   map[string][]string   `json:"transitions"`
+	Automatic     []AutomaticTransition `json:"automatic"`
+}
+
+type AutomaticTransition struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Condition string `json:"condition,omitempty"`
+	Filter    string `json:"filter,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// WorkflowResult sums up automatic transitions made after import.
+type WorkflowResult struct {
+	// Initialized is the number of students given the initial status.
+	Initialized int `json:"initialized"`
+	// Transitioned is the number of status changes made by automatic transitions.
+	Transitioned int `json:"transitioned"`
+}
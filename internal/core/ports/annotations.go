@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+type AnnotationsService interface {
+	AddTag(ctx context.Context, id string, input domain.TagInput) (*domain.StudentRecord, error)
+	RemoveTag(ctx context.Context, id string, name string) (*domain.StudentRecord, error)
+	AddNote(ctx context.Context, id string, input domain.NoteInput) (*domain.StudentRecord, error)
+	UpdateNote(ctx context.Context, id string, noteID string, input domain.NoteInput) (*domain.StudentRecord, error)
+	DeleteNote(ctx context.Context, id string, noteID string) (*domain.StudentRecord, error)
+	SetAnnotation(ctx context.Context, id string, key string, input domain.AnnotationInput) (*domain.StudentRecord, error)
+	DeleteAnnotation(ctx context.Context, id string, key string) (*domain.StudentRecord, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+type DuplicatesService interface {
+	FindDuplicates(ctx context.Context, options domain.FindDuplicatesOptions) ([]domain.DuplicateCandidate, error)
+	MergeStudents(ctx context.Context, input domain.MergeStudentsInput) (*domain.StudentRecord, error)
+}
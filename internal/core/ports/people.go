@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+type PeopleService interface {
+	GetProfile(ctx context.Context, email string) (*domain.PersonProfile, error)
+}
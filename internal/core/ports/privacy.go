@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+type PrivacyService interface {
+	ExportPerson(ctx context.Context, email string) (*domain.PersonExport, error)
+	ExportPersonArchive(ctx context.Context, email string, w io.Writer) error
+	ErasePerson(ctx context.Context, email string, input domain.EraseInput) (*domain.Erasure, error)
+	ListErasures(ctx context.Context, limit int) ([]domain.Erasure, error)
+}
+
+type ErasuresStore interface {
+	Create(ctx context.Context, erasure domain.Erasure) (string, error)
+	List(ctx context.Context, limit int) ([]domain.Erasure, error)
+}
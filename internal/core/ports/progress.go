@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+type ProgressService interface {
+	Leaderboard(ctx context.Context, options domain.LeaderboardOptions) (*domain.Leaderboard, error)
+	LateSubmissions(ctx context.Context, options domain.ProgressOptions) ([]domain.LateSubmission, error)
+	ListProgress(ctx context.Context, options domain.ProgressOptions) ([]domain.StudentProgress, error)
+	GetStudentProgress(ctx context.Context, id string) (*domain.StudentProgress, error)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+type QualityService interface {
+	Report(ctx context.Context, options domain.QualityOptions) (*domain.QualityReport, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+type ReportsService interface {
+	EngagementReport(ctx context.Context) (*domain.EngagementReport, error)
+	ExportEngagementReport(ctx context.Context, w io.Writer) error
+}
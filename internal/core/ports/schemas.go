@@ -2,22 +2,26 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
 )
 
 type SchemaService interface {
 	NewSchema(ctx context.Context, input domain.NewSchemaInput) (*domain.Schema, error)
-	ListSchemas(ctx context.Context) ([]domain.Schema, error)
+	ListSchemas(ctx context.Context, options domain.ListSchemasOptions) ([]domain.Schema, *domain.PageInfo, error)
 	GetSchemaById(ctx context.Context, id string) (*domain.Schema, error)
-	UpdateSchema(ctx context.Context, id string, input domain.UpdateSchemaInput) (*domain.Schema, error)
+	UpdateSchema(ctx context.Context, id string, input domain.UpdateSchemaInput, revision *int) (*domain.Schema, error)
 	DeleteSchema(ctx context.Context, id string) error
 }
 
 type SchemaStore interface {
 	Create(ctx context.Context, input domain.Schema) (string, error)
-	FindAll(ctx context.Context) ([]domain.Schema, error)
+	FindAll(ctx context.Context, options domain.ListSchemasOptions) ([]domain.Schema, *domain.PageInfo, error)
 	GetById(ctx context.Context, id string) (*domain.Schema, error)
-	Update(ctx context.Context, id string, input domain.UpdateSchemaInput) error
+	Update(ctx context.Context, id string, input domain.UpdateSchemaInput, revision *int) error
 	Delete(ctx context.Context, id string) error
+	ListDeleted(ctx context.Context, limit int) ([]domain.Schema, error)
+	Undelete(ctx context.Context, id string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+type StatsService interface {
+	StudentsStats(ctx context.Context, options domain.StudentsStatsOptions) (*domain.StudentsStats, error)
+	TimeSeries(ctx context.Context, options domain.TimeSeriesOptions) (*domain.TimeSeries, error)
+}
+
+type StatsStore interface {
+	StudentsStats(ctx context.Context, options domain.StudentsStatsOptions) (*domain.StudentsStats, error)
+	TimeSeries(ctx context.Context, options domain.TimeSeriesOptions) (*domain.TimeSeries, error)
+}
@@ -11,5 +11,5 @@ import (
 type StorageService interface {
 	SetClient(cl *minio.Client)
 	PutFile(ctx context.Context, options domain.PutFileOptions) (stug string, err error)
-	GetFile(ctx context.Context, slug string) (io.Reader, int64, error)
+	GetFile(ctx context.Context, slug string) (io.Reader, *domain.FileInfo, error)
 }
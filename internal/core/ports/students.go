@@ -2,23 +2,49 @@ package ports
 
 import (
 	"context"
+	"io"
+	"time"
+
 	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
 )
 
 type StudentsService interface {
 	GetStudentById(ctx context.Context, id string) (*domain.StudentRecord, error)
-	ListStudents(ctx context.Context, options domain.ListStudentsOptions) ([]domain.StudentRecord, error)
-	UpdateStudent(ctx context.Context, id string, input domain.StudentRecord) (*domain.StudentRecord, error)
+	ListStudents(ctx context.Context, options domain.ListStudentsOptions) ([]domain.StudentRecord, *domain.PageInfo, error)
+	SearchStudents(ctx context.Context, options domain.SearchStudentsOptions) ([]domain.StudentSearchResult, error)
+	ExportStudents(ctx context.Context, options domain.ExportStudentsOptions, w io.Writer) error
+	UpdateStudent(ctx context.Context, id string, input domain.StudentRecord, revision *int) (*domain.StudentRecord, error)
+	PatchStudent(ctx context.Context, id string, patch domain.StudentPatch, revision *int) (*domain.StudentRecord, error)
 	DeleteStudent(ctx context.Context, id string) error
 	DeleteStudentByFileName(ctx context.Context, fileName string) error
+	BulkStudents(ctx context.Context, input domain.BulkStudentsInput) (*domain.BulkResult, error)
+	GetStudentHistory(ctx context.Context, id string) ([]domain.HistoryEntry, error)
+	RestoreStudent(ctx context.Context, id string, version int, revision *int) (*domain.StudentRecord, error)
 }
 
 type StudentsStore interface {
 	SaveRSS(ctx context.Context, fileName string, email string, student domain.StudentRSS) (string, error)
 	SaveWAC(ctx context.Context, fileName string, email string, student domain.StudentWAC) (string, error)
 	GetById(ctx context.Context, id string) (*domain.StudentRecord, error)
-	GetAll(ctx context.Context, options domain.ListStudentsOptions) ([]domain.StudentRecord, error)
-	Update(ctx context.Context, id string, input domain.StudentRecord) error
+	GetAll(ctx context.Context, options domain.ListStudentsOptions) ([]domain.StudentRecord, *domain.PageInfo, error)
+	Search(ctx context.Context, options domain.SearchStudentsOptions) ([]domain.StudentSearchResult, error)
+	Iterate(ctx context.Context, options domain.ListStudentsOptions, fn func(student *domain.StudentRecord) error) error
+	MaxProjects(ctx context.Context, options domain.ListStudentsOptions) (int, error)
+	Update(ctx context.Context, id string, input domain.StudentRecord, revision *int) error
+	Replace(ctx context.Context, id string, input domain.StudentRecord, revision *int) error
 	Delete(ctx context.Context, id string) error
+	Merge(ctx context.Context, primary domain.StudentRecord, sources []domain.StudentRecord) error
+	DuplicateBlocks(ctx context.Context, source string) ([][]domain.StudentRecord, error)
 	DeleteByFileName(ctx context.Context, fileName string) error
+	History(ctx context.Context, id string) ([]domain.HistoryEntry, error)
+	Restore(ctx context.Context, id string, version int, revision *int) (*domain.StudentRecord, error)
+	ListDeleted(ctx context.Context, limit int) ([]domain.StudentRecord, error)
+	Undelete(ctx context.Context, id string) (*domain.StudentRecord, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	BulkUpdate(ctx context.Context, options domain.BulkStudentsOptions) (*domain.BulkResult, error)
+	Annotate(ctx context.Context, id string, fn func(student *domain.StudentRecord) error) (*domain.StudentRecord, error)
+	ChangeStatus(ctx context.Context, student *domain.StudentRecord, change domain.StatusChange) (*domain.StudentRecord, error)
+	PersonRecords(ctx context.Context, email string, withDeleted bool) ([]domain.StudentRecord, error)
+	PersonHistory(ctx context.Context, email string, ids []string) ([]domain.HistoryEntry, error)
+	Erase(ctx context.Context, ids []string) (int64, int64, error)
 }
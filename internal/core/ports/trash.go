@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+type TrashService interface {
+	ListTrash(ctx context.Context, options domain.TrashOptions) ([]domain.TrashItem, error)
+	RestoreStudent(ctx context.Context, id string) (*domain.StudentRecord, error)
+	RestoreSchema(ctx context.Context, id string) (*domain.Schema, error)
+	Purge(ctx context.Context) (*domain.PurgeResult, error)
+	RunPurgeJob(ctx context.Context)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+type WorkflowService interface {
+	GetWorkflow() *domain.Workflow
+	ChangeStatus(ctx context.Context, id string, input domain.StatusInput, revision *int) (*domain.StudentRecord, error)
+	ApplyImport(ctx context.Context, fileName string) (*domain.WorkflowResult, error)
+	BulkTransition(ctx context.Context, options domain.BulkStudentsOptions) (*domain.BulkResult, error)
+}
@@ -0,0 +1,201 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BulkUpdate applies operation to students selected by ids or filter with a single unordered bulk write
+// and records changed students to their history. Students already in the resulting state are not written.
+// Unknown ids and students modified concurrently are reported as item errors.
+// Returns ErrBulkTooLarge when filter matches more than MaxBulkIDs students, nothing is written then.
+func (sr *StudentsRepo) BulkUpdate(ctx context.Context, bulk domain.BulkStudentsOptions) (*domain.BulkResult, error) {
+	if bulk.Operation.Type == domain.BulkTransition {
+		return nil, fmt.Errorf("%w: status is changed by workflow transitions only", domain.ErrInvalidParams)
+	}
+
+	result := &domain.BulkResult{Errors: []domain.BulkItemError{}}
+
+	filter := studentsFilter(domain.ListStudentsOptions{Filter: bulk.Filter})
+	if bulk.IDs != nil {
+		objectIds := bson.A{}
+		for _, id := range bulk.IDs {
+			objectId, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				result.Errors = append(result.Errors, domain.BulkItemError{ID: id, Error: "invalid id"})
+				continue
+			}
+			objectIds = append(objectIds, objectId)
+		}
+		filter["_id"] = bson.M{"$in": objectIds}
+	}
+
+	var before []domain.StudentRecord
+	cur, err := sr.col.Find(ctx, filter, options.Find().SetLimit(domain.MaxBulkIDs+1))
+	if err != nil {
+		return nil, err
+	}
+	if err := cur.All(ctx, &before); err != nil {
+		return nil, err
+	}
+	if len(before) > domain.MaxBulkIDs {
+		return nil, domain.ErrBulkTooLarge
+	}
+	if err := sr.crypt.decryptAll(before); err != nil {
+		return nil, err
+	}
+	result.Matched = int64(len(before))
+
+	if bulk.IDs != nil {
+		found := make(map[string]bool, len(before))
+		for _, s := range before {
+			found[s.ID] = true
+		}
+		for _, id := range bulk.IDs {
+			if _, err := primitive.ObjectIDFromHex(id); err == nil && !found[id] {
+				result.Errors = append(result.Errors, domain.BulkItemError{ID: id, Error: domain.ErrNotFound.Error()})
+			}
+		}
+	}
+
+	at := sr.now().UTC()
+	op := bulk.Operation
+	if op.Type == domain.BulkSetField {
+		if op.Value, err = sr.crypt.setValue(op.Field, op.Value); err != nil {
+			return nil, err
+		}
+	}
+	update := bulkUpdate(ctx, op, at)
+
+	// students are updated only if they are not modified after being read, so history gets exact changes
+	var changed []domain.StudentRecord
+	var models []mongo.WriteModel
+	for _, s := range before {
+		ok, err := bulkChanges(bulk.Operation, &s)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		objectId, _ := primitive.ObjectIDFromHex(s.ID)
+		changed = append(changed, s)
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(revisionFilter(objectId, s.Revision)).
+			SetUpdate(update))
+	}
+	if len(models) == 0 {
+		return result, nil
+	}
+
+	failed := make(map[int]bool)
+	res, err := sr.col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		var bwe mongo.BulkWriteException
+		if !errors.As(err, &bwe) {
+			return nil, err
+		}
+		for _, we := range bwe.WriteErrors {
+			failed[we.Index] = true
+			result.Errors = append(result.Errors, domain.BulkItemError{ID: changed[we.Index].ID, Error: we.Message})
+		}
+	}
+	if res != nil {
+		result.Modified = res.ModifiedCount
+	}
+
+	action := domain.HistoryUpdated
+	if bulk.Operation.Type == domain.BulkDelete {
+		action = domain.HistoryDeleted
+	}
+
+	written := bson.A{}
+	for i, s := range changed {
+		if !failed[i] {
+			objectId, _ := primitive.ObjectIDFromHex(s.ID)
+			written = append(written, objectId)
+		}
+	}
+
+	var updated []domain.StudentRecord
+	cur, err = sr.col.Find(ctx, bson.M{"_id": bson.M{"$in": written}})
+	if err != nil {
+		return nil, err
+	}
+	if err := cur.All(ctx, &updated); err != nil {
+		return nil, err
+	}
+	if err := sr.crypt.decryptAll(updated); err != nil {
+		return nil, err
+	}
+	afterById := make(map[string]*domain.StudentRecord, len(updated))
+	for i := range updated {
+		afterById[updated[i].ID] = &updated[i]
+	}
+
+	for i := range changed {
+		if failed[i] {
+			continue
+		}
+
+		s := &changed[i]
+		after, ok := afterById[s.ID]
+		// revision is incremented by the bulk write only, otherwise the student was modified concurrently
+		if !ok || after.Revision != s.Revision+1 {
+			result.Errors = append(result.Errors, domain.BulkItemError{ID: s.ID, Error: domain.ErrPreconditionFailed.Error()})
+			continue
+		}
+
+		sr.record(ctx, action, s, after, 0)
+	}
+
+	return result, nil
+}
+
+// bulkUpdate returns update document of the operation made at the time on behalf of ctx actor,
+// operation is expected to be validated.
+func bulkUpdate(ctx context.Context, op domain.BulkOperation, at time.Time) bson.M {
+	inc := bson.M{"revision": 1}
+
+	switch op.Type {
+	case domain.BulkSetField:
+		return bson.M{"$set": bson.M{op.Field: op.Value}, "$inc": inc}
+	case domain.BulkAddTag:
+		tag := domain.Tag{Name: op.Tag, AddedBy: domain.ActorFromContext(ctx).Username, AddedAt: at}
+		return bson.M{"$push": bson.M{"tags": tag}, "$inc": inc}
+	case domain.BulkRemoveTag:
+		return bson.M{"$pull": bson.M{"tags": bson.M{"name": op.Tag}}, "$inc": inc}
+	default:
+		return trashUpdate(ctx, at)
+	}
+}
+
+// bulkChanges reports whether the operation changes student.
+func bulkChanges(op domain.BulkOperation, student *domain.StudentRecord) (bool, error) {
+	switch op.Type {
+	case domain.BulkSetField:
+		flat, err := flattenRecord(student)
+		if err != nil {
+			return false, err
+		}
+		t, data, err := bson.MarshalValue(op.Value)
+		if err != nil {
+			return false, err
+		}
+		current, ok := flat.values[op.Field]
+		return !ok || !current.Equal(bson.RawValue{Type: t, Value: data}), nil
+	case domain.BulkAddTag, domain.BulkRemoveTag:
+		return student.HasTag(op.Tag) == (op.Type == domain.BulkRemoveTag), nil
+	default:
+		return true, nil
+	}
+}
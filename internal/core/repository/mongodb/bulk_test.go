@@ -0,0 +1,41 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+func TestBulkChanges(t *testing.T) {
+	student := &domain.StudentRecord{
+		Tags:       []domain.Tag{{Name: "scholarship"}},
+		StudentWAC: domain.StudentWAC{Location: "Tatooine", Registered: 3},
+	}
+
+	tests := []struct {
+		name string
+		op   domain.BulkOperation
+		want bool
+	}{
+		{"same string", domain.BulkOperation{Type: domain.BulkSetField, Field: "student_wac.location", Value: "Tatooine"}, false},
+		{"other string", domain.BulkOperation{Type: domain.BulkSetField, Field: "student_wac.location", Value: "Naboo"}, true},
+		{"same number", domain.BulkOperation{Type: domain.BulkSetField, Field: "student_wac.registered", Value: 3}, false},
+		{"missing field", domain.BulkOperation{Type: domain.BulkSetField, Field: "student_wac.company", Value: "Acme"}, true},
+		{"existing tag", domain.BulkOperation{Type: domain.BulkAddTag, Tag: "scholarship"}, false},
+		{"new tag", domain.BulkOperation{Type: domain.BulkAddTag, Tag: "mentor"}, true},
+		{"remove existing tag", domain.BulkOperation{Type: domain.BulkRemoveTag, Tag: "scholarship"}, true},
+		{"remove missing tag", domain.BulkOperation{Type: domain.BulkRemoveTag, Tag: "mentor"}, false},
+		{"delete", domain.BulkOperation{Type: domain.BulkDelete}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bulkChanges(tt.op, student)
+			if err != nil {
+				t.Fatalf("bulkChanges() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("bulkChanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package mongodb
+
+import (
+	"regexp"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/pkg/fieldcrypt"
+)
+
+const (
+	emailField      = "email"
+	emailIndexField = "email_index"
+)
+
+// mergedEmailPattern matches history change paths of merged records emails.
+var mergedEmailPattern = regexp.MustCompile(`^merged_from\.\d+\.email$`)
+
+// studentCrypt encrypts configured student fields before they are stored and decrypts them after they are read.
+// Email is looked up by its blind index when it is encrypted. Nil studentCrypt stores fields as they are.
+type studentCrypt struct {
+	cipher *fieldcrypt.Cipher
+	fields map[string]bool
+}
+
+// newStudentCrypt returns nil studentCrypt when there are no encryption keys. Keys are kept after fields are no longer
+// configured to be encrypted, so values encrypted before are still decrypted.
+func newStudentCrypt(cfg config.EncryptionConfig) (*studentCrypt, error) {
+	if len(cfg.KeyList()) == 0 {
+		return nil, nil
+	}
+
+	cipher, err := fieldcrypt.New(cfg.KeyList(), cfg.IndexKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sc := &studentCrypt{cipher: cipher, fields: make(map[string]bool, len(cfg.Fields))}
+	for _, field := range cfg.Fields {
+		sc.fields[field] = true
+	}
+
+	return sc, nil
+}
+
+// encryptableValues returns encryptable fields of student by their stored names.
+func encryptableValues(s *domain.StudentRecord) map[string]*string {
+	return map[string]*string{
+		"email":                  &s.Email,
+		"student_rss.first_name": &s.FirstName,
+		"student_rss.last_name":  &s.LastName,
+		"student_wac.full_name":  &s.StudentWAC.FullName,
+		"student_wac.location":   &s.Location,
+		"student_wac.company":    &s.Company,
+		"student_wac.position":   &s.Position,
+	}
+}
+
+// encrypt encrypts configured fields of student and sets email blind indexes.
+// Merged records are copied, so the slice shared with other copies of student is not changed.
+func (sc *studentCrypt) encrypt(s *domain.StudentRecord) error {
+	if sc == nil {
+		return nil
+	}
+
+	if sc.fields[emailField] {
+		s.EmailIndex = sc.emailIndex(s.Email)
+		s.MergedFrom = append([]domain.MergedRecord(nil), s.MergedFrom...)
+		for i := range s.MergedFrom {
+			m := &s.MergedFrom[i]
+			m.EmailIndex = sc.emailIndex(m.Email)
+			if err := sc.encryptString(&m.Email); err != nil {
+				return err
+			}
+		}
+	}
+
+	for field, value := range encryptableValues(s) {
+		if !sc.fields[field] {
+			continue
+		}
+		if err := sc.encryptString(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decrypt decrypts every encrypted field of student, including fields which are not configured any more,
+// and clears blind indexes. Values of fields which are not configured are kept as they are when they only look
+// encrypted, since they are stored as they were sent.
+func (sc *studentCrypt) decrypt(s *domain.StudentRecord) error {
+	if sc == nil {
+		return nil
+	}
+
+	s.EmailIndex = ""
+	s.MergedFrom = append([]domain.MergedRecord(nil), s.MergedFrom...)
+	for i := range s.MergedFrom {
+		s.MergedFrom[i].EmailIndex = ""
+		if err := sc.decryptString(&s.MergedFrom[i].Email, sc.fields[emailField]); err != nil {
+			return err
+		}
+	}
+
+	for field, value := range encryptableValues(s) {
+		if err := sc.decryptString(value, sc.fields[field]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decryptAll decrypts students read in a batch.
+func (sc *studentCrypt) decryptAll(students []domain.StudentRecord) error {
+	for i := range students {
+		if err := sc.decrypt(&students[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encryptEntry encrypts history entry snapshot and changed values of encrypted fields.
+func (sc *studentCrypt) encryptEntry(entry *domain.HistoryEntry) error {
+	if sc == nil {
+		return nil
+	}
+
+	if err := sc.encrypt(&entry.Snapshot); err != nil {
+		return err
+	}
+
+	entry.Changes = append([]domain.FieldChange(nil), entry.Changes...)
+	for i := range entry.Changes {
+		change := &entry.Changes[i]
+		if !sc.encrypted(change.Field) {
+			continue
+		}
+		var err error
+		if change.Old, err = sc.encryptValue(change.Old); err != nil {
+			return err
+		}
+		if change.New, err = sc.encryptValue(change.New); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decryptEntry decrypts history entry encrypted by encryptEntry.
+func (sc *studentCrypt) decryptEntry(entry *domain.HistoryEntry) error {
+	if sc == nil {
+		return nil
+	}
+
+	if err := sc.decrypt(&entry.Snapshot); err != nil {
+		return err
+	}
+
+	for i := range entry.Changes {
+		change := &entry.Changes[i]
+		var err error
+		if change.Old, err = sc.decryptValue(change.Old, sc.encrypted(change.Field)); err != nil {
+			return err
+		}
+		if change.New, err = sc.decryptValue(change.New, sc.encrypted(change.Field)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decryptEntries decrypts history entries read in a batch.
+func (sc *studentCrypt) decryptEntries(entries []domain.HistoryEntry) error {
+	for i := range entries {
+		if err := sc.decryptEntry(&entries[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encrypted reports whether stored field path is encrypted.
+func (sc *studentCrypt) encrypted(field string) bool {
+	if sc == nil {
+		return false
+	}
+
+	return sc.fields[field] || (sc.fields[emailField] && mergedEmailPattern.MatchString(field))
+}
+
+// setValue returns value to set to stored field path, string values of encrypted fields are encrypted.
+func (sc *studentCrypt) setValue(field string, value interface{}) (interface{}, error) {
+	if !sc.encrypted(field) {
+		return value, nil
+	}
+
+	return sc.encryptValue(value)
+}
+
+// emailLookup returns stored field name and value students are looked up by email with.
+func (sc *studentCrypt) emailLookup(email string) (string, string) {
+	if sc == nil || !sc.fields[emailField] {
+		return emailField, email
+	}
+
+	return emailIndexField, sc.emailIndex(email)
+}
+
+// current reports whether stored student has configured fields encrypted with the current key
+// and no other fields encrypted, so it does not have to be re-encrypted.
+func (sc *studentCrypt) current(s *domain.StudentRecord) bool {
+	check := func(field string, value string) bool {
+		if value == "" {
+			return true
+		}
+		if sc.fields[field] {
+			return sc.cipher.IsCurrent(value)
+		}
+		return !fieldcrypt.IsEncrypted(value)
+	}
+
+	for field, value := range encryptableValues(s) {
+		if !check(field, *value) {
+			return false
+		}
+	}
+	for _, m := range s.MergedFrom {
+		if !check(emailField, m.Email) || m.EmailIndex != sc.indexOf(m.Email) {
+			return false
+		}
+	}
+
+	return s.EmailIndex == sc.indexOf(s.Email)
+}
+
+// indexOf returns blind index of stored email, which is empty when email is not encrypted.
+func (sc *studentCrypt) indexOf(stored string) string {
+	if !sc.fields[emailField] || stored == "" {
+		return ""
+	}
+	email, err := sc.cipher.Decrypt(stored)
+	if err != nil {
+		return ""
+	}
+
+	return sc.emailIndex(email)
+}
+
+// emailIndex returns blind index of normalized email, the index of empty email is empty.
+func (sc *studentCrypt) emailIndex(email string) string {
+	if email = domain.NormalizeEmail(email); email == "" {
+		return ""
+	}
+
+	return sc.cipher.BlindIndex(email)
+}
+
+func (sc *studentCrypt) encryptString(value *string) error {
+	encrypted, err := sc.cipher.Encrypt(*value)
+	if err != nil {
+		return err
+	}
+	*value = encrypted
+
+	return nil
+}
+
+// decryptString decrypts value of a field, values of fields which are not configured to be encrypted
+// are kept as they are when they can not be decrypted.
+func (sc *studentCrypt) decryptString(value *string, configured bool) error {
+	decrypted, err := sc.cipher.Decrypt(*value)
+	if err != nil && !configured {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	*value = decrypted
+
+	return nil
+}
+
+// encryptValue encrypts string values, other values are returned as is.
+func (sc *studentCrypt) encryptValue(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	return sc.cipher.Encrypt(s)
+}
+
+// decryptValue decrypts encrypted string values the way decryptString does, other values are returned as is.
+func (sc *studentCrypt) decryptValue(value interface{}, configured bool) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	if err := sc.decryptString(&s, configured); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
@@ -0,0 +1,163 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/pkg/fieldcrypt"
+)
+
+const (
+	testEncryptionKey = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+	testIndexKey      = "ICEiIyQlJicoKSorLC0uLzAxMjM0NTY3ODk6Ozw9Pj8="
+)
+
+func newTestCrypt(t *testing.T, fields ...string) *studentCrypt {
+	sc, err := newStudentCrypt(config.EncryptionConfig{Fields: fields, Keys: testEncryptionKey, IndexKey: testIndexKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sc
+}
+
+func TestStudentCrypt(t *testing.T) {
+	sc := newTestCrypt(t, "email", "student_wac.location")
+
+	student := domain.StudentRecord{
+		Email:      "obi@jedi.rules",
+		MergedFrom: []domain.MergedRecord{{Email: "ben@tatooine.sand"}},
+		StudentWAC: domain.StudentWAC{Location: "Tatooine", Company: "Jedi Order"},
+	}
+	stored := student
+	if err := sc.encrypt(&stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !fieldcrypt.IsEncrypted(stored.Email) || !fieldcrypt.IsEncrypted(stored.Location) ||
+		!fieldcrypt.IsEncrypted(stored.MergedFrom[0].Email) {
+		t.Errorf("encrypt() left configured fields unencrypted: %+v", stored)
+	}
+	if stored.Company != "Jedi Order" {
+		t.Errorf("encrypt() changed company to %q, want it stored as is", stored.Company)
+	}
+	if student.MergedFrom[0].Email != "ben@tatooine.sand" {
+		t.Error("encrypt() changed merged records of the original student")
+	}
+
+	field, index := sc.emailLookup(" OBI@jedi.rules")
+	if field != emailIndexField || index != stored.EmailIndex || index == "" {
+		t.Errorf("emailLookup() = %q, %q, want email_index %q", field, index, stored.EmailIndex)
+	}
+	if _, mergedIndex := sc.emailLookup("ben@tatooine.sand"); mergedIndex != stored.MergedFrom[0].EmailIndex {
+		t.Errorf("merged record email index = %q, want %q", stored.MergedFrom[0].EmailIndex, mergedIndex)
+	}
+	if !sc.current(&stored) {
+		t.Error("current() = false for student encrypted with the current key")
+	}
+
+	if err := sc.decrypt(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(stored, student) {
+		t.Errorf("decrypt() = %+v, want %+v", stored, student)
+	}
+	if sc.current(&stored) {
+		t.Error("current() = true for student with configured fields not encrypted")
+	}
+}
+
+func TestStudentCryptDisabled(t *testing.T) {
+	var sc *studentCrypt
+
+	student := domain.StudentRecord{Email: "obi@jedi.rules"}
+	if err := sc.encrypt(&student); err != nil || student.Email != "obi@jedi.rules" || student.EmailIndex != "" {
+		t.Errorf("encrypt() = %+v, %v, want student as is", student, err)
+	}
+	if field, email := sc.emailLookup("obi@jedi.rules"); field != emailField || email != "obi@jedi.rules" {
+		t.Errorf("emailLookup() = %q, %q, want email as is", field, email)
+	}
+}
+
+func TestStudentCryptPrefixedPlaintext(t *testing.T) {
+	sc := newTestCrypt(t, "email")
+
+	student := domain.StudentRecord{
+		Email:      "enc:v1:obi@jedi.rules",
+		StudentWAC: domain.StudentWAC{Company: "enc:v1:Jedi Order"},
+	}
+	stored := student
+	if err := sc.encrypt(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.Email == student.Email || !sc.cipher.IsCurrent(stored.Email) {
+		t.Errorf("encrypt() stored email %q, want it encrypted", stored.Email)
+	}
+
+	if err := sc.decrypt(&stored); err != nil {
+		t.Fatalf("decrypt() error = %v", err)
+	}
+	if stored.Email != student.Email || stored.Company != student.Company {
+		t.Errorf("decrypt() = %q, %q, want %q, %q", stored.Email, stored.Company, student.Email, student.Company)
+	}
+}
+
+func TestStudentCryptEncrypted(t *testing.T) {
+	sc := newTestCrypt(t, "email", "student_rss.first_name")
+
+	tests := []struct {
+		field string
+		want  bool
+	}{
+		{"email", true},
+		{"merged_from.0.email", true},
+		{"merged_from.12.email", true},
+		{"merged_from.0.source", false},
+		{"student_rss.first_name", true},
+		{"student_rss.last_name", false},
+		{"email_index", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if got := sc.encrypted(tt.field); got != tt.want {
+				t.Errorf("encrypted(%q) = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStudentCryptEntry(t *testing.T) {
+	sc := newTestCrypt(t, "student_wac.location")
+
+	entry := domain.HistoryEntry{
+		Snapshot: domain.StudentRecord{StudentWAC: domain.StudentWAC{Location: "Naboo"}},
+		Changes: []domain.FieldChange{
+			{Field: "student_wac.location", Old: "Tatooine", New: "Naboo"},
+			{Field: "student_wac.registered", Old: int32(1), New: int32(2)},
+		},
+	}
+	stored := entry
+	if err := sc.encryptEntry(&stored); err != nil {
+		t.Fatal(err)
+	}
+
+	location := stored.Changes[0]
+	if !fieldcrypt.IsEncrypted(location.Old.(string)) || !fieldcrypt.IsEncrypted(location.New.(string)) {
+		t.Errorf("encryptEntry() change = %+v, want encrypted values", location)
+	}
+	if !reflect.DeepEqual(stored.Changes[1], entry.Changes[1]) {
+		t.Errorf("encryptEntry() change = %+v, want %+v", stored.Changes[1], entry.Changes[1])
+	}
+	if !sc.currentEntry(&stored) || sc.currentEntry(&entry) {
+		t.Error("currentEntry() should report encrypted entry only as current")
+	}
+
+	if err := sc.decryptEntry(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(stored, entry) {
+		t.Errorf("decryptEntry() = %+v, want %+v", stored, entry)
+	}
+}
@@ -0,0 +1,210 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const idField = "_id"
+
+type pageCollection interface {
+	Find(ctx context.Context, filter interface{},
+		opts ...*options.FindOptions) (cur *mongo.Cursor, err error)
+	CountDocuments(ctx context.Context, filter interface{},
+		opts ...*options.CountOptions) (int64, error)
+}
+
+// pageCursor points to the last document of a page by its sort fields values and id.
+type pageCursor struct {
+	Values bson.A      `bson:"v"`
+	ID     interface{} `bson:"id"`
+}
+
+type sortField struct {
+	name  string
+	order int
+}
+
+// findPage finds a page of documents matching filter ordered by sort.
+// Documents are ordered by id in addition, so the page cursor always points to a single position.
+// Returns decoded documents, page info and an error.
+func findPage[T any](ctx context.Context, col pageCollection, filter bson.M, sort []domain.SortField, page domain.PageOptions) ([]T, *domain.PageInfo, error) {
+	fields := sortFields(sort)
+	info := &domain.PageInfo{}
+
+	if page.WithTotal {
+		total, err := col.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		info.Total = &total
+	}
+
+	opts := options.Find().SetSort(sortToBson(fields))
+	if page.Limit > 0 {
+		opts.SetLimit(int64(page.Limit) + 1)
+	}
+
+	if page.Cursor != "" {
+		c, err := decodeCursor(page.Cursor)
+		if err != nil || len(c.Values) != len(fields)-1 {
+			return nil, nil, domain.ErrInvalidCursor
+		}
+		filter = bson.M{"$and": bson.A{filter, keysetFilter(fields, c)}}
+	} else if page.Skip > 0 {
+		opts.SetSkip(int64(page.Skip))
+	}
+
+	cur, err := col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var raws []bson.Raw
+	if err := cur.All(ctx, &raws); err != nil {
+		return nil, nil, err
+	}
+
+	if page.Limit > 0 && len(raws) > page.Limit {
+		raws = raws[:page.Limit]
+		info.HasMore = true
+		info.NextCursor, err = encodeCursor(cursorFromRaw(fields, raws[len(raws)-1]))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	items := make([]T, len(raws))
+	for i, raw := range raws {
+		if err := bson.Unmarshal(raw, &items[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return items, info, nil
+}
+
+// sortFields converts sort to storage order and appends id field unless it is sorted already.
+// Fields following id are dropped since id is unique.
+func sortFields(sort []domain.SortField) []sortField {
+	fields := make([]sortField, 0, len(sort)+1)
+	for _, sf := range sort {
+		order := 1
+		if sf.Desc {
+			order = -1
+		}
+		fields = append(fields, sortField{sf.Field, order})
+
+		if sf.Field == idField {
+			return fields
+		}
+	}
+
+	idOrder := -1
+	if len(fields) > 0 {
+		idOrder = fields[len(fields)-1].order
+	}
+
+	return append(fields, sortField{idField, idOrder})
+}
+
+func sortToBson(fields []sortField) bson.D {
+	d := make(bson.D, 0, len(fields))
+	for _, f := range fields {
+		d = append(d, bson.E{Key: f.name, Value: f.order})
+	}
+
+	return d
+}
+
+// keysetFilter matches documents placed after cursor c in fields order:
+// the first fields are equal to the cursor values and the next one is greater or less.
+// Null and missing values are sorted before any other value, so they are matched explicitly,
+// comparison operators never match them.
+func keysetFilter(fields []sortField, c *pageCursor) bson.M {
+	values := append(append(bson.A{}, c.Values...), c.ID)
+
+	or := bson.A{}
+	for i, f := range fields {
+		cond := bson.M{}
+		for j := 0; j < i; j++ {
+			if values[j] == nil {
+				cond[fields[j].name] = nil
+			} else {
+				cond[fields[j].name] = bson.M{"$eq": values[j]}
+			}
+		}
+
+		switch {
+		case f.order > 0 && values[i] == nil:
+			cond[f.name] = bson.M{"$ne": nil}
+		case f.order > 0:
+			cond[f.name] = bson.M{"$gt": values[i]}
+		case values[i] == nil:
+			// nothing is placed after null in descending order
+			continue
+		case f.name == idField:
+			cond[f.name] = bson.M{"$lt": values[i]}
+		default:
+			cond["$or"] = bson.A{
+				bson.M{f.name: bson.M{"$lt": values[i]}},
+				bson.M{f.name: nil},
+			}
+		}
+		or = append(or, cond)
+	}
+
+	if len(or) == 0 {
+		// the cursor points to the last document
+		return bson.M{idField: bson.M{"$exists": false}}
+	}
+
+	return bson.M{"$or": or}
+}
+
+func cursorFromRaw(fields []sortField, raw bson.Raw) pageCursor {
+	c := pageCursor{Values: bson.A{}}
+	for _, f := range fields {
+		var value interface{}
+		if rv, err := raw.LookupErr(strings.Split(f.name, ".")...); err == nil {
+			_ = rv.Unmarshal(&value)
+		}
+
+		if f.name == idField {
+			c.ID = value
+		} else {
+			c.Values = append(c.Values, value)
+		}
+	}
+
+	return c
+}
+
+func encodeCursor(c pageCursor) (string, error) {
+	data, err := bson.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(s string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var c pageCursor
+	if err := bson.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,234 @@
+package mongodb
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSortFields(t *testing.T) {
+	tests := []struct {
+		name string
+		sort []domain.SortField
+		want []sortField
+	}{
+		{"empty", nil, []sortField{{idField, -1}}},
+		{"id only", []domain.SortField{{Field: idField}}, []sortField{{idField, 1}}},
+		{"field", []domain.SortField{{Field: "email"}}, []sortField{{"email", 1}, {idField, 1}}},
+		{
+			"fields",
+			[]domain.SortField{{Field: "student_rss.total_score", Desc: true}, {Field: "email"}, {Field: "source", Desc: true}},
+			[]sortField{{"student_rss.total_score", -1}, {"email", 1}, {"source", -1}, {idField, -1}},
+		},
+		{
+			"fields after id",
+			[]domain.SortField{{Field: "email"}, {Field: idField, Desc: true}, {Field: "source"}},
+			[]sortField{{"email", 1}, {idField, -1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sortFields(tt.sort); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysetFilter(t *testing.T) {
+	id := primitive.NewObjectID()
+	fields := []sortField{{"email", 1}, {idField, -1}}
+
+	got := keysetFilter(fields, &pageCursor{Values: bson.A{"a@b.c"}, ID: id})
+	want := bson.M{"$or": bson.A{
+		bson.M{"email": bson.M{"$gt": "a@b.c"}},
+		bson.M{"email": bson.M{"$eq": "a@b.c"}, idField: bson.M{"$lt": id}},
+	}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("keysetFilter() = %v, want %v", got, want)
+	}
+
+	got = keysetFilter([]sortField{{"email", -1}, {idField, -1}}, &pageCursor{Values: bson.A{"a@b.c"}, ID: id})
+	want = bson.M{"$or": bson.A{
+		bson.M{"$or": bson.A{bson.M{"email": bson.M{"$lt": "a@b.c"}}, bson.M{"email": nil}}},
+		bson.M{"email": bson.M{"$eq": "a@b.c"}, idField: bson.M{"$lt": id}},
+	}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("keysetFilter() descending = %v, want %v", got, want)
+	}
+}
+
+func TestKeysetPagingMissingValues(t *testing.T) {
+	var docs []bson.M
+	for _, name := range []string{"Luke", "", "Leia", "", "Han", "Luke", ""} {
+		doc := bson.M{idField: primitive.NewObjectID()}
+		if name != "" {
+			doc["student_rss"] = bson.M{"first_name": name}
+		}
+		docs = append(docs, doc)
+	}
+
+	for _, desc := range []bool{false, true} {
+		fields := sortFields([]domain.SortField{{Field: "student_rss.first_name", Desc: desc}})
+		want := sortedDocs(docs, fields)
+
+		var got []bson.M
+		var cursor *pageCursor
+		for page := 0; page < len(docs); page++ {
+			var rest []bson.M
+			for _, doc := range want {
+				if cursor == nil || matchFilter(doc, keysetFilter(fields, cursor)) {
+					rest = append(rest, doc)
+				}
+			}
+			if len(rest) == 0 {
+				break
+			}
+			if len(rest) > 2 {
+				rest = rest[:2]
+			}
+			got = append(got, rest...)
+
+			raw, err := bson.Marshal(rest[len(rest)-1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			encoded, err := encodeCursor(cursorFromRaw(fields, raw))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cursor, err = decodeCursor(encoded); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("pages (desc %v) = %v, want %v", desc, got, want)
+		}
+	}
+}
+
+// sortedDocs orders docs by fields the way MongoDB does, null and missing values first.
+func sortedDocs(docs []bson.M, fields []sortField) []bson.M {
+	sorted := append([]bson.M(nil), docs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		for _, f := range fields {
+			if c := compareValues(lookupValue(sorted[i], f.name), lookupValue(sorted[j], f.name)); c != 0 {
+				return c*f.order < 0
+			}
+		}
+		return false
+	})
+
+	return sorted
+}
+
+// matchFilter evaluates filters built by keysetFilter.
+func matchFilter(doc bson.M, filter bson.M) bool {
+	for key, cond := range filter {
+		if key == "$or" {
+			matched := false
+			for _, sub := range cond.(bson.A) {
+				matched = matched || matchFilter(doc, sub.(bson.M))
+			}
+			if !matched {
+				return false
+			}
+			continue
+		}
+
+		value := lookupValue(doc, key)
+		if cond == nil {
+			if value != nil {
+				return false
+			}
+			continue
+		}
+		for op, operand := range cond.(bson.M) {
+			c := compareValues(value, operand)
+			ok := false
+			switch op {
+			case "$eq":
+				ok = c == 0
+			case "$ne":
+				ok = c != 0
+			case "$gt":
+				ok = value != nil && c > 0
+			case "$lt":
+				ok = value != nil && c < 0
+			}
+			if !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func lookupValue(doc bson.M, path string) interface{} {
+	var value interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(bson.M)
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+
+	return value
+}
+
+// compareValues compares strings and object ids, nil is less than any other value.
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	if id, ok := a.(primitive.ObjectID); ok {
+		other := b.(primitive.ObjectID)
+		return strings.Compare(id.Hex(), other.Hex())
+	}
+
+	return strings.Compare(a.(string), b.(string))
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	raw, err := bson.Marshal(bson.M{"_id": id, "email": "a@b.c", "student_wac": bson.M{"attended_events": int32(3)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := []sortField{{"email", 1}, {"student_rss.first_name", 1}, {"student_wac.attended_events", 1}, {idField, 1}}
+	encoded, err := encodeCursor(cursorFromRaw(fields, raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := decodeCursor(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &pageCursor{Values: bson.A{"a@b.c", nil, int32(3)}, ID: id}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeCursor() = %v, want %v", got, want)
+	}
+
+	if _, err := decodeCursor("not a cursor"); err == nil {
+		t.Error("decodeCursor() expected an error")
+	}
+}
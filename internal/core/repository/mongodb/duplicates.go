@@ -0,0 +1,151 @@
+package mongodb
+
+import (
+	"context"
+	"sort"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// blockingKeyLength is the number of leading runes of email local part and name tokens students are blocked by.
+const blockingKeyLength = 3
+
+// nameFields are stored student fields the display name is built of.
+var nameFields = []string{"student_rss.first_name", "student_rss.last_name", "student_wac.full_name"}
+
+type blockResult struct {
+	IDs []primitive.ObjectID `bson:"ids"`
+}
+
+// DuplicateBlocks groups students of source sharing an email local part or a name token prefix with a single
+// aggregation, so only students of groups with several members are read.
+// Encrypted emails are grouped by their blind index and encrypted names are not grouped.
+func (sr *StudentsRepo) DuplicateBlocks(ctx context.Context, source string) ([][]domain.StudentRecord, error) {
+	pipeline := bson.A{
+		bson.M{"$match": studentsFilter(domain.ListStudentsOptions{Source: source})},
+		bson.M{"$project": bson.M{"keys": bson.M{"$concatArrays": bson.A{sr.emailBlockingKeys(), sr.nameBlockingKeys()}}}},
+		bson.M{"$unwind": "$keys"},
+		bson.M{"$match": bson.M{"keys": bson.M{"$ne": nil}}},
+		bson.M{"$group": bson.M{"_id": "$keys", "ids": bson.M{"$addToSet": "$_id"}}},
+		bson.M{"$match": bson.M{"ids.1": bson.M{"$exists": true}}},
+	}
+
+	cur, err := sr.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []blockResult
+	if err := cur.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return [][]domain.StudentRecord{}, nil
+	}
+
+	ids := bson.A{}
+	seen := make(map[primitive.ObjectID]bool)
+	for _, r := range results {
+		for _, id := range r.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	cur, err = sr.col.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	var students []domain.StudentRecord
+	if err := cur.All(ctx, &students); err != nil {
+		return nil, err
+	}
+	if err := sr.crypt.decryptAll(students); err != nil {
+		return nil, err
+	}
+
+	byId := make(map[string]domain.StudentRecord, len(students))
+	for _, s := range students {
+		byId[s.ID] = s
+	}
+
+	blocks := make([][]domain.StudentRecord, 0, len(results))
+	for _, r := range results {
+		var block []domain.StudentRecord
+		for _, id := range r.IDs {
+			// students deleted after being grouped are skipped
+			if s, ok := byId[id.Hex()]; ok {
+				block = append(block, s)
+			}
+		}
+		sort.Slice(block, func(i, j int) bool {
+			return block[i].ID < block[j].ID
+		})
+		if len(block) > 1 {
+			blocks = append(blocks, block)
+		}
+	}
+
+	return blocks, nil
+}
+
+// emailBlockingKeys returns aggregation expression of the email local part prefix without dots and +tags,
+// encrypted email is keyed by its blind index.
+func (sr *StudentsRepo) emailBlockingKeys() bson.A {
+	if sr.crypt.encrypted(emailField) {
+		return bson.A{bson.M{"$cond": bson.A{
+			bson.M{"$gt": bson.A{"$" + emailIndexField, nil}},
+			bson.M{"$concat": bson.A{"i:", "$" + emailIndexField}},
+			nil,
+		}}}
+	}
+
+	email := bson.M{"$toLower": bson.M{"$trim": bson.M{"input": bson.M{"$ifNull": bson.A{"$email", ""}}}}}
+	local := bson.M{"$arrayElemAt": bson.A{bson.M{"$split": bson.A{email, "@"}}, 0}}
+	local = bson.M{"$arrayElemAt": bson.A{bson.M{"$split": bson.A{local, "+"}}, 0}}
+	local = bson.M{"$replaceAll": bson.M{"input": local, "find": ".", "replacement": ""}}
+
+	return bson.A{bson.M{"$let": bson.M{
+		"vars": bson.M{"local": local},
+		"in":   prefixKey("e:", "$$local"),
+	}}}
+}
+
+// nameBlockingKeys returns aggregation expression of display name tokens prefixes,
+// names are not keyed when any of their fields is encrypted.
+func (sr *StudentsRepo) nameBlockingKeys() interface{} {
+	for _, field := range nameFields {
+		if sr.crypt.encrypted(field) {
+			return bson.A{}
+		}
+	}
+
+	rss := bson.M{"$trim": bson.M{"input": bson.M{"$concat": bson.A{
+		bson.M{"$ifNull": bson.A{"$student_rss.first_name", ""}},
+		" ",
+		bson.M{"$ifNull": bson.A{"$student_rss.last_name", ""}},
+	}}}}
+	wac := bson.M{"$trim": bson.M{"input": bson.M{"$ifNull": bson.A{"$student_wac.full_name", ""}}}}
+	name := bson.M{"$cond": bson.A{bson.M{"$ne": bson.A{rss, ""}}, rss, wac}}
+
+	return bson.M{"$map": bson.M{
+		"input": bson.M{"$regexFindAll": bson.M{"input": bson.M{"$toLower": name}, "regex": `[\p{L}\p{N}]+`}},
+		"as":    "token",
+		"in":    prefixKey("n:", "$$token.match"),
+	}}
+}
+
+// prefixKey returns aggregation expression of prefix followed by leading runes of value,
+// values shorter than blockingKeyLength have no key.
+func prefixKey(prefix string, value string) bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$gte": bson.A{bson.M{"$strLenCP": value}, blockingKeyLength}},
+		bson.M{"$concat": bson.A{prefix, bson.M{"$substrCP": bson.A{value, 0, blockingKeyLength}}}},
+		nil,
+	}}
+}
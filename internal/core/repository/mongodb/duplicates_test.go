@@ -0,0 +1,104 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestStudentsRepo_DuplicateBlocks(t *testing.T) {
+	mt := getMockTest(t)
+	defer mt.Close()
+
+	anakin, skywalker, kenobi := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	student := func(id primitive.ObjectID, email string) bson.D {
+		return bson.D{{Key: "_id", Value: id}, {Key: "source", Value: domain.RSS}, {Key: "email", Value: email}}
+	}
+
+	mt.Run("success", func(mt *mtest.T) {
+		ns := fmt.Sprintf("%s.%s", testDbName, studentsCollection)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				bson.D{{Key: "ids", Value: bson.A{skywalker, anakin}}},
+				bson.D{{Key: "ids", Value: bson.A{anakin, kenobi}}},
+			),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				student(anakin, "anakin.skywalker@gmail.com"),
+				student(skywalker, "a.skywalker@jedi-academy.edu"),
+			),
+		)
+
+		repo, err := NewStudentsRepo(mt.DB, config.EncryptionConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		blocks, err := repo.DuplicateBlocks(context.Background(), domain.RSS)
+		if err != nil {
+			t.Fatalf("DuplicateBlocks() error = %v", err)
+		}
+
+		// kenobi is deleted after being grouped, so his block has a single student and is skipped
+		var got [][]string
+		for _, block := range blocks {
+			var ids []string
+			for _, s := range block {
+				ids = append(ids, s.ID)
+			}
+			got = append(got, ids)
+		}
+		first, second := anakin.Hex(), skywalker.Hex()
+		if second < first {
+			first, second = second, first
+		}
+		if want := [][]string{{first, second}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("DuplicateBlocks() = %v, want %v", got, want)
+		}
+	})
+
+	mt.Run("failure", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "some error"}))
+
+		repo, err := NewStudentsRepo(mt.DB, config.EncryptionConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.DuplicateBlocks(context.Background(), ""); err == nil {
+			t.Error("DuplicateBlocks() expected an error")
+		}
+	})
+}
+
+func TestBlockingKeys(t *testing.T) {
+	plain := newRepo(&collectionMock{}, &historyMock{})
+	if keys := plain.emailBlockingKeys(); reflect.DeepEqual(keys[0], emailIndexKey()) {
+		t.Error("emailBlockingKeys() keys plain email by blind index")
+	}
+	if keys, ok := plain.nameBlockingKeys().(bson.M); !ok || keys["$map"] == nil {
+		t.Errorf("nameBlockingKeys() = %v, want name tokens", keys)
+	}
+
+	encrypted := newRepo(&collectionMock{}, &historyMock{})
+	encrypted.crypt = newTestCrypt(t, emailField, "student_wac.full_name")
+	if keys := encrypted.emailBlockingKeys(); !reflect.DeepEqual(keys[0], emailIndexKey()) {
+		t.Errorf("emailBlockingKeys() = %v, want blind index", keys)
+	}
+	if keys := encrypted.nameBlockingKeys(); !reflect.DeepEqual(keys, bson.A{}) {
+		t.Errorf("nameBlockingKeys() = %v, want no keys of encrypted names", keys)
+	}
+}
+
+func emailIndexKey() bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$gt": bson.A{"$email_index", nil}},
+		bson.M{"$concat": bson.A{"i:", "$email_index"}},
+		nil,
+	}}
+}
@@ -0,0 +1,52 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const erasuresCollection = "erasures"
+
+var _ ports.ErasuresStore = (*ErasuresRepo)(nil)
+
+// ErasuresRepo keeps audit records of personal data erasures.
+type ErasuresRepo struct {
+	col *mongo.Collection
+}
+
+func NewErasuresRepo(db *mongo.Database) *ErasuresRepo {
+	return &ErasuresRepo{
+		col: db.Collection(erasuresCollection),
+	}
+}
+
+func (er *ErasuresRepo) Create(ctx context.Context, erasure domain.Erasure) (string, error) {
+	res, err := er.col.InsertOne(ctx, erasure)
+	if err != nil {
+		return "", err
+	}
+
+	return getIdFromObjectID(res.InsertedID), nil
+}
+
+// List returns up to limit erasures, the most recent first.
+func (er *ErasuresRepo) List(ctx context.Context, limit int) ([]domain.Erasure, error) {
+	cur, err := er.col.Find(ctx, bson.M{}, options.Find().
+		SetSort(bson.M{"at": -1}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+
+	erasures := []domain.Erasure{}
+	if err := cur.All(ctx, &erasures); err != nil {
+		return nil, err
+	}
+
+	return erasures, nil
+}
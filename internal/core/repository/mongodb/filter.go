@@ -0,0 +1,63 @@
+package mongodb
+
+import (
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
+)
+
+var comparisonOperators = map[query.Operator]string{
+	query.Ne:  "$ne",
+	query.Gt:  "$gt",
+	query.Gte: "$gte",
+	query.Lt:  "$lt",
+	query.Lte: "$lte",
+}
+
+// filterToBson converts parsed filter expression to mongo query.
+// Field names are whitelisted and values are typed by the query parser,
+// so user input never becomes a query operator.
+func filterToBson(expr query.Expr) bson.M {
+	switch e := expr.(type) {
+	case query.And:
+		return bson.M{"$and": exprsToBson(e.Exprs)}
+	case query.Or:
+		return bson.M{"$or": exprsToBson(e.Exprs)}
+	case query.Not:
+		return bson.M{"$nor": bson.A{filterToBson(e.Expr)}}
+	case query.Condition:
+		return conditionToBson(e)
+	default:
+		return bson.M{}
+	}
+}
+
+func exprsToBson(exprs []query.Expr) bson.A {
+	result := make(bson.A, 0, len(exprs))
+	for _, e := range exprs {
+		result = append(result, filterToBson(e))
+	}
+
+	return result
+}
+
+func conditionToBson(c query.Condition) bson.M {
+	switch c.Op {
+	case query.Eq:
+		return bson.M{c.Field: c.Values[0]}
+	case query.In:
+		return bson.M{c.Field: bson.M{"$in": c.Values}}
+	case query.Exists:
+		return bson.M{c.Field: bson.M{"$exists": true}}
+	case query.Contains:
+		pattern := regexp.QuoteMeta(c.Values[0].(string))
+		return bson.M{c.Field: primitive.Regex{Pattern: pattern, Options: "i"}}
+	case query.Regex:
+		return bson.M{c.Field: primitive.Regex{Pattern: c.Values[0].(string)}}
+	default:
+		return bson.M{c.Field: bson.M{comparisonOperators[c.Op]: c.Values[0]}}
+	}
+}
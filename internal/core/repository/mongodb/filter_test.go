@@ -0,0 +1,138 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFilterToBson(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter string
+		want   bson.M
+	}{
+		{
+			"comparison",
+			"student_rss.projects.score>=80",
+			bson.M{"student_rss.projects.score": bson.M{"$gte": 80.0}},
+		},
+		{
+			"logical operators",
+			"student_wac.attended_events>2 AND NOT (source = RSS OR email exists)",
+			bson.M{"$and": bson.A{
+				bson.M{"student_wac.attended_events": bson.M{"$gt": 2.0}},
+				bson.M{"$nor": bson.A{
+					bson.M{"$or": bson.A{
+						bson.M{"source": "RSS"},
+						bson.M{"email": bson.M{"$exists": true}},
+					}},
+				}},
+			}},
+		},
+		{
+			"in, contains and regex",
+			`source in (RSS, WAC) or student_wac.company contains "a.b" or email ~ "^obi"`,
+			bson.M{"$or": bson.A{
+				bson.M{"source": bson.M{"$in": []interface{}{"RSS", "WAC"}}},
+				bson.M{"student_wac.company": primitive.Regex{Pattern: `a\.b`, Options: "i"}},
+				bson.M{"email": primitive.Regex{Pattern: "^obi"}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := query.Parse(tt.filter, domain.StudentFilterFields)
+			if err != nil {
+				t.Fatalf("query.Parse() error = %v", err)
+			}
+
+			if got := filterToBson(expr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterToBson() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchRegexFilter(t *testing.T) {
+	options := domain.SearchStudentsOptions{Query: "ana sky.", Source: domain.RSS}
+	everything := func(string) bool { return true }
+
+	tests := []struct {
+		name      string
+		encrypted func(field string) bool
+		searched  func(field string) bool
+	}{
+		{"plain", newTestCrypt(t).encrypted, everything},
+		{"encrypted", newTestCrypt(t, "email", "student_wac.location").encrypted, func(field string) bool {
+			return field != "email" && field != "student_wac.location"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := searchRegexFilter(options, tt.encrypted)
+
+			words := bson.A{}
+			for _, pattern := range []string{"ana", `sky\.`} {
+				fields := bson.A{}
+				for _, field := range studentsTextFields {
+					if tt.searched(field.Key) {
+						fields = append(fields, bson.M{field.Key: primitive.Regex{Pattern: pattern, Options: "i"}})
+					}
+				}
+				words = append(words, bson.M{"$or": fields})
+			}
+			want := bson.M{"$and": words, "source": domain.RSS, "deleted_at": nil}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("searchRegexFilter() = %v, want %v", got, want)
+			}
+		})
+	}
+
+	t.Run("every field encrypted", func(t *testing.T) {
+		got := searchRegexFilter(options, everything)
+
+		nothing := bson.M{"$or": bson.A{bson.M{"_id": bson.M{"$exists": false}}}}
+		want := bson.M{"$and": bson.A{nothing, nothing}, "source": domain.RSS, "deleted_at": nil}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("searchRegexFilter() = %v, want %v", got, want)
+		}
+	})
+}
+
+func objectIdFromHex(t *testing.T, id string) primitive.ObjectID {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return objectId
+}
+
+func TestStudentsFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		options domain.ListStudentsOptions
+		want    bson.M
+	}{
+		{"not deleted", domain.ListStudentsOptions{Source: domain.WAC}, bson.M{"source": domain.WAC, "deleted_at": nil}},
+		{"with deleted", domain.ListStudentsOptions{Email: "a@b.c", WithDeleted: true}, bson.M{"email": "a@b.c"}},
+		{
+			"ids",
+			domain.ListStudentsOptions{IDs: []string{"63c9b4d0e4b0a1b2c3d4e5f6", "invalid"}},
+			bson.M{"_id": bson.M{"$in": bson.A{objectIdFromHex(t, "63c9b4d0e4b0a1b2c3d4e5f6")}}, "deleted_at": nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := studentsFilter(tt.options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("studentsFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const historyCollection = "students_history"
+
+// maxRecordAttempts limits inserts of a history entry whose version is taken.
+const maxRecordAttempts = 5
+
+type HistoryCollection interface {
+	InsertOne(ctx context.Context, document interface{},
+		opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
+	FindOne(ctx context.Context, filter interface{},
+		opts ...*options.FindOneOptions) *mongo.SingleResult
+	Find(ctx context.Context, filter interface{},
+		opts ...*options.FindOptions) (cur *mongo.Cursor, err error)
+	DeleteMany(ctx context.Context, filter interface{},
+		opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	ReplaceOne(ctx context.Context, filter interface{}, replacement interface{},
+		opts ...*options.ReplaceOptions) (*mongo.UpdateResult, error)
+}
+
+// History returns versions of student record in ascending order.
+func (sr *StudentsRepo) History(ctx context.Context, id string) ([]domain.HistoryEntry, error) {
+	cur, err := sr.history.Find(ctx, bson.M{"student_id": id}, options.Find().SetSort(bson.M{"version": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []domain.HistoryEntry{}
+	if err := cur.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	if err := sr.crypt.decryptEntries(entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Restore replaces student record with its snapshot of version, records in trash are restored from it
+// and purged records are inserted again with the same id.
+// When revision is not nil it must match the current student revision, purged students match no revision.
+// Returns restored student record and an error.
+func (sr *StudentsRepo) Restore(ctx context.Context, id string, version int, revision *int) (*domain.StudentRecord, error) {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry domain.HistoryEntry
+	if err := sr.history.FindOne(ctx, bson.M{"student_id": id, "version": version}).Decode(&entry); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	if err := sr.crypt.decryptEntry(&entry); err != nil {
+		return nil, err
+	}
+
+	before, err := sr.findOne(ctx, bson.M{"_id": objectId})
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if revision != nil && (before == nil || *revision != before.Revision) {
+		return nil, domain.ErrPreconditionFailed
+	}
+
+	student := entry.Snapshot
+	student.ID = ""
+	student.DeletedAt = nil
+	student.DeletedBy = ""
+
+	// purged record is inserted again with the revision following its history, otherwise it is replaced
+	// if it is not modified concurrently
+	filter := bson.M{"_id": objectId}
+	if before == nil {
+		latest, err := sr.latestVersion(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		student.Revision = latest + 1
+	} else {
+		filter = revisionFilter(objectId, before.Revision)
+		student.Revision = before.Revision + 1
+	}
+
+	stored := student
+	if err := sr.crypt.encrypt(&stored); err != nil {
+		return nil, err
+	}
+
+	res, err := sr.col.ReplaceOne(ctx, filter, stored, options.Replace().SetUpsert(before == nil))
+	if err != nil {
+		return nil, err
+	}
+	if before != nil && res.MatchedCount == 0 {
+		return nil, domain.ErrPreconditionFailed
+	}
+	student.ID = id
+
+	sr.record(ctx, domain.HistoryRestored, before, &student, version)
+
+	return &student, nil
+}
+
+// record writes history entry of student change, before is nil for created records and after is nil for deleted ones.
+// Updates changing nothing are not recorded.
+// The entry version is the revision of the recorded student, which is incremented by every change atomically.
+// Entries of records changed before revisions were introduced get the version following the latest one.
+// History is written after the change is stored, so a failed write is logged instead of being returned:
+// the change is applied and must not be retried by the caller, its history entry is missing then.
+func (sr *StudentsRepo) record(ctx context.Context, action string, before, after *domain.StudentRecord, restoredFrom int) {
+	if err := sr.writeEntry(ctx, action, before, after, restoredFrom); err != nil {
+		logger.Log.Errorf("student %s %s is not recorded to history: %v", entryStudent(before, after).ID, action, err)
+	}
+}
+
+func (sr *StudentsRepo) writeEntry(ctx context.Context, action string, before, after *domain.StudentRecord, restoredFrom int) error {
+	entry := domain.HistoryEntry{
+		Action:       action,
+		Actor:        domain.ActorFromContext(ctx),
+		At:           sr.now().UTC(),
+		RestoredFrom: restoredFrom,
+	}
+
+	student := entryStudent(before, after)
+	entry.StudentID = student.ID
+	entry.Snapshot = *student
+
+	changes, err := diffRecords(before, after)
+	if err != nil {
+		return err
+	}
+	if action == domain.HistoryUpdated && len(changes) == 0 {
+		return nil
+	}
+	entry.Changes = changes
+
+	if err := sr.crypt.encryptEntry(&entry); err != nil {
+		return err
+	}
+
+	// versions are unique per student, a version taken by an entry of a record changed before revisions
+	// were introduced is detected by the insert, and the entry is inserted again after the latest version
+	entry.Version = student.Revision
+	for attempt := 1; ; attempt++ {
+		if entry.Version == 0 {
+			latest, err := sr.latestVersion(ctx, entry.StudentID)
+			if err != nil {
+				return err
+			}
+			entry.Version = latest + 1
+		}
+
+		_, err = sr.history.InsertOne(ctx, entry)
+		if err == nil || !IsDuplicate(err) || attempt == maxRecordAttempts {
+			return err
+		}
+		entry.Version = 0
+	}
+}
+
+// entryStudent returns the student recorded to history, which is after unless the student is deleted.
+func entryStudent(before, after *domain.StudentRecord) *domain.StudentRecord {
+	if after != nil {
+		return after
+	}
+
+	return before
+}
+
+// latestVersion returns the latest history version of student, which is 0 when the student has no history.
+func (sr *StudentsRepo) latestVersion(ctx context.Context, id string) (int, error) {
+	var entry domain.HistoryEntry
+	err := sr.history.FindOne(ctx, bson.M{"student_id": id}, options.FindOne().
+		SetSort(bson.M{"version": -1}).
+		SetProjection(bson.M{"version": 1})).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+
+	return entry.Version, err
+}
+
+// diffRecords compares stored fields of records, nested documents and arrays are compared element by element.
+// Missing record is compared as an empty one.
+func diffRecords(before, after *domain.StudentRecord) ([]domain.FieldChange, error) {
+	old, err := flattenRecord(before)
+	if err != nil {
+		return nil, err
+	}
+	next, err := flattenRecord(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []domain.FieldChange{}
+	for _, field := range old.keys {
+		ov, nv := old.values[field], next.values[field]
+		if nv.Type != 0 && ov.Equal(nv) {
+			continue
+		}
+		changes = append(changes, domain.FieldChange{Field: field, Old: rawToValue(ov), New: rawToValue(nv)})
+	}
+	for _, field := range next.keys {
+		if _, ok := old.values[field]; !ok {
+			changes = append(changes, domain.FieldChange{Field: field, New: rawToValue(next.values[field])})
+		}
+	}
+
+	return changes, nil
+}
+
+type flatRecord struct {
+	keys   []string
+	values map[string]bson.RawValue
+}
+
+func flattenRecord(student *domain.StudentRecord) (*flatRecord, error) {
+	flat := &flatRecord{values: map[string]bson.RawValue{}}
+	if student == nil {
+		return flat, nil
+	}
+
+	s := *student
+	s.ID = ""
+	s.Revision = 0
+	raw, err := bson.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return flat, flat.add("", raw)
+}
+
+func (f *flatRecord) add(prefix string, doc bson.Raw) error {
+	elements, err := doc.Elements()
+	if err != nil {
+		return err
+	}
+
+	// array elements are keyed by their indexes
+	for _, e := range elements {
+		key := e.Key()
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		value := e.Value()
+		switch value.Type {
+		case bsontype.EmbeddedDocument, bsontype.Array:
+			if err := f.add(key, value.Value); err != nil {
+				return err
+			}
+		default:
+			f.keys = append(f.keys, key)
+			f.values[key] = value
+		}
+	}
+
+	return nil
+}
+
+func rawToValue(rv bson.RawValue) interface{} {
+	if rv.Type == 0 {
+		return nil
+	}
+
+	var value interface{}
+	if err := rv.Unmarshal(&value); err != nil {
+		return rv.String()
+	}
+
+	return value
+}
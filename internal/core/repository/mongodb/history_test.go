@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+func TestDiffRecords(t *testing.T) {
+	before := &domain.StudentRecord{
+		ID:     "1",
+		Source: domain.RSS,
+		Email:  "luke@jedi.rules",
+		StudentRSS: domain.StudentRSS{
+			FirstName: "Luke",
+			Projects:  []domain.Project{{Name: "Songbird", Score: 50}},
+		},
+	}
+	after := &domain.StudentRecord{
+		ID:     "1",
+		Source: domain.RSS,
+		Email:  "luke@jedi.rules",
+		Status: "active",
+		StudentRSS: domain.StudentRSS{
+			Projects: []domain.Project{{Name: "Songbird", Score: 100}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		before *domain.StudentRecord
+		after  *domain.StudentRecord
+		want   []domain.FieldChange
+	}{
+		{
+			name:   "updated",
+			before: before,
+			after:  after,
+			want: []domain.FieldChange{
+				{Field: "student_rss.first_name", Old: "Luke"},
+				{Field: "student_rss.projects.0.score", Old: int32(50), New: int32(100)},
+				{Field: "status", New: "active"},
+			},
+		},
+		{
+			name:   "unchanged",
+			before: before,
+			after:  before,
+			want:   []domain.FieldChange{},
+		},
+		{
+			name:   "deleted",
+			before: &domain.StudentRecord{ID: "1", Source: domain.WAC},
+			want:   []domain.FieldChange{{Field: "source", Old: domain.WAC}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := diffRecords(tt.before, tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffRecords() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		entries  []domain.HistoryEntry
+		revision int
+		want     []int
+	}{
+		{"revision", []domain.HistoryEntry{{StudentID: "1", Version: 1}, {StudentID: "1", Version: 2}}, 3, []int{1, 2, 3}},
+		{"after erased entries", []domain.HistoryEntry{{StudentID: "1", Version: 4}}, 6, []int{4, 6}},
+		{"taken by record without revisions", []domain.HistoryEntry{{StudentID: "1", Version: 1}, {StudentID: "1", Version: 2}}, 1, []int{1, 2, 3}},
+		{"record without revisions", []domain.HistoryEntry{{StudentID: "1", Version: 1}}, 0, []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := &historyMock{entries: tt.entries}
+			sr := newRepo(&collectionMock{}, history)
+
+			before := &domain.StudentRecord{ID: "1", Email: "obi@jedi.rules"}
+			after := &domain.StudentRecord{ID: "1", Email: "ben@tatooine.sand", Revision: tt.revision}
+			sr.record(context.Background(), domain.HistoryUpdated, before, after, 0)
+
+			var versions []int
+			for _, entry := range history.entries {
+				versions = append(versions, entry.Version)
+			}
+			if !reflect.DeepEqual(versions, tt.want) {
+				t.Errorf("record() versions = %v, want %v", versions, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordFailure(t *testing.T) {
+	mock := &collectionMock{}
+	sr := newRepo(mock, &historyMock{err: errors.New("history is not available")})
+	sr.now = func() time.Time { return importedAt }
+
+	// the student is stored before history is written, so the import is not failed and repeated
+	if _, err := sr.SaveRSS(context.Background(), "rss.xlsx", "", domain.StudentRSS{}); err != nil {
+		t.Errorf("SaveRSS() error = %v, want the change applied without history", err)
+	}
+	if mock.data == nil {
+		t.Error("SaveRSS() did not store the student")
+	}
+}
@@ -0,0 +1,76 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const studentsTextIndex = "students_text"
+
+// studentsTextFields are fields of the students text index with their relevance weights.
+var studentsTextFields = bson.D{
+	{Key: "student_rss.first_name", Value: 10},
+	{Key: "student_rss.last_name", Value: 10},
+	{Key: "student_wac.full_name", Value: 10},
+	{Key: "email", Value: 5},
+	{Key: "student_wac.company", Value: 3},
+	{Key: "student_wac.location", Value: 3},
+	{Key: "student_rss.projects.name", Value: 1},
+}
+
+// CreateIndexes creates indexes required by the repositories. Existing indexes are kept as is.
+func CreateIndexes(ctx context.Context, db *mongo.Database) error {
+	keys := bson.D{}
+	for _, field := range studentsTextFields {
+		keys = append(keys, bson.E{Key: field.Key, Value: "text"})
+	}
+
+	if _, err := db.Collection(studentsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: keys,
+		Options: options.Index().
+			SetName(studentsTextIndex).
+			SetWeights(studentsTextFields).
+			SetDefaultLanguage("none"),
+	}); err != nil {
+		return err
+	}
+
+	if _, err := db.Collection(historyCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "student_id", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return err
+	}
+
+	// person records are looked up by emails of records merged into them as well
+	if _, err := db.Collection(studentsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "merged_from.email", Value: 1}},
+	}); err != nil {
+		return err
+	}
+
+	// encrypted emails are looked up by their blind indexes, students stored without them are left out of the indexes
+	for _, field := range []string{emailIndexField, "merged_from." + emailIndexField} {
+		if _, err := db.Collection(studentsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: field, Value: 1}},
+			Options: options.Index().SetSparse(true),
+		}); err != nil {
+			return err
+		}
+	}
+
+	// trash is listed and purged by deletion time, documents not in trash are left out of the indexes
+	for _, collection := range []string{studentsCollection, schemasCollection} {
+		if _, err := db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "deleted_at", Value: -1}},
+			Options: options.Index().SetSparse(true),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Migrate converts documents stored in outdated formats to the current ones. Migrated documents are left as is,
+// so it is run on every start.
+func Migrate(ctx context.Context, db *mongo.Database) error {
+	if err := migrateTags(ctx, db.Collection(studentsCollection), "tags"); err != nil {
+		return err
+	}
+
+	return migrateTags(ctx, db.Collection(historyCollection), "snapshot.tags")
+}
+
+// migrateTags converts tags stored as plain names by bulk operations before tags had authors and times
+// to tag documents with the name. Revisions are kept, the tags are the same for clients.
+func migrateTags(ctx context.Context, col *mongo.Collection, field string) error {
+	_, err := col.UpdateMany(ctx, bson.M{field: bson.M{"$type": "string"}}, bson.A{
+		bson.M{"$set": bson.M{field: bson.M{"$map": bson.M{
+			"input": "$" + field,
+			"as":    "tag",
+			"in": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{bson.M{"$type": "$$tag"}, "string"}},
+				bson.M{"name": "$$tag"},
+				"$$tag",
+			}},
+		}}}},
+	})
+
+	return err
+}
@@ -0,0 +1,54 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMigrate(t *testing.T) {
+	mt := getMockTest(t)
+	defer mt.Close()
+
+	mt.Run("success", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+		)
+
+		if err := Migrate(context.Background(), mt.DB); err != nil {
+			t.Fatalf("Migrate() error = %v", err)
+		}
+
+		events := mt.GetAllStartedEvents()
+		if len(events) != 2 {
+			t.Fatalf("Migrate() sent %d commands, want 2", len(events))
+		}
+		for i, want := range []struct{ collection, field string }{
+			{studentsCollection, "tags"},
+			{historyCollection, "snapshot.tags"},
+		} {
+			command := events[i].Command
+			if got := command.Lookup("update").StringValue(); got != want.collection {
+				t.Errorf("Migrate() updates %s, want %s", got, want.collection)
+			}
+			update := command.Lookup("updates", "0")
+			if _, err := update.Document().LookupErr("q", want.field, "$type"); err != nil {
+				t.Errorf("Migrate() filter has no %s type: %v", want.field, err)
+			}
+			if _, err := update.Document().LookupErr("u", "0", "$set", want.field, "$map"); err != nil {
+				t.Errorf("Migrate() update does not map %s: %v", want.field, err)
+			}
+		}
+	})
+
+	mt.Run("failure", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "some error"}))
+
+		if err := Migrate(context.Background(), mt.DB); err == nil {
+			t.Error("Migrate() expected an error")
+		}
+	})
+}
@@ -1,20 +1,57 @@
 package mongodb
 
 import (
+	"context"
 	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
 	"github.com/abdukhashimov/student_aggregator/internal/core/repository"
 )
 
-func NewRepositories(db *mongo.Database) *repository.Repositories {
+func NewRepositories(db *mongo.Database, cfg *config.Config) (*repository.Repositories, error) {
+	students, err := NewStudentsRepo(db, cfg.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
 	return &repository.Repositories{
 		Users:    NewUsersRepo(db),
 		Schemas:  NewSchemaRepo(db),
-		Students: NewStudentsRepo(db),
+		Students: students,
+		Stats:    NewStatsRepo(db, cfg.Encryption),
+		Erasures: NewErasuresRepo(db),
+	}, nil
+}
+
+// revisionFilter matches document id stored with revision,
+// documents stored before revisions were introduced have no revision field and match revision 0.
+func revisionFilter(id primitive.ObjectID, revision int) bson.M {
+	if revision == 0 {
+		return bson.M{"_id": id, "revision": bson.M{"$in": bson.A{nil, 0}}}
 	}
+
+	return bson.M{"_id": id, "revision": revision}
+}
+
+// trashUpdate moves document to trash at the time on behalf of ctx actor.
+func trashUpdate(ctx context.Context, at time.Time) bson.M {
+	return bson.M{
+		"$set": bson.M{"deleted_at": at, "deleted_by": domain.ActorFromContext(ctx).Username},
+		"$inc": bson.M{"revision": 1},
+	}
+}
+
+// untrashUpdate restores document from trash.
+var untrashUpdate = bson.M{
+	"$unset": bson.M{"deleted_at": "", "deleted_by": ""},
+	"$inc":   bson.M{"revision": 1},
 }
 
 func IsDuplicate(err error) bool {
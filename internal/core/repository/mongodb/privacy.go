@@ -0,0 +1,88 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PersonRecords returns student records with email, or the ones email records were merged into,
+// records in trash are included when withDeleted is true.
+func (sr *StudentsRepo) PersonRecords(ctx context.Context, email string, withDeleted bool) ([]domain.StudentRecord, error) {
+	field, value := sr.crypt.emailLookup(email)
+	filter := bson.M{"$or": bson.A{
+		bson.M{field: value},
+		bson.M{"merged_from." + field: value},
+	}}
+	if !withDeleted {
+		filter["deleted_at"] = nil
+	}
+
+	cur, err := sr.col.Find(ctx, filter, options.Find().SetSort(bson.M{"imported_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	records := []domain.StudentRecord{}
+	if err := cur.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	if err := sr.crypt.decryptAll(records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// PersonHistory returns history entries of students with ids and of students whose snapshots have email,
+// or merged records with email, ordered by student and version.
+// Snapshots are looked up by email, so history of purged students is found too.
+func (sr *StudentsRepo) PersonHistory(ctx context.Context, email string, ids []string) ([]domain.HistoryEntry, error) {
+	field, value := sr.crypt.emailLookup(email)
+	cur, err := sr.history.Find(ctx, bson.M{"$or": bson.A{
+		bson.M{"student_id": bson.M{"$in": ids}},
+		bson.M{"snapshot." + field: value},
+		bson.M{"snapshot.merged_from." + field: value},
+	}}, options.Find().SetSort(bson.D{{Key: "student_id", Value: 1}, {Key: "version", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []domain.HistoryEntry{}
+	if err := cur.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	if err := sr.crypt.decryptEntries(entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Erase removes students with ids and their history for good, deleted students are not recorded to history.
+// Returns the numbers of removed students and history entries and an error.
+func (sr *StudentsRepo) Erase(ctx context.Context, ids []string) (int64, int64, error) {
+	objectIds := bson.A{}
+	for _, id := range ids {
+		if objectId, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIds = append(objectIds, objectId)
+		}
+	}
+
+	students, err := sr.col.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": objectIds}})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	history, err := sr.history.DeleteMany(ctx, bson.M{"student_id": bson.M{"$in": ids}})
+	if err != nil {
+		return students.DeletedCount, 0, err
+	}
+
+	return students.DeletedCount, history.DeletedCount, nil
+}
@@ -0,0 +1,122 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/pkg/fieldcrypt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrEncryptionDisabled is returned by RotateKeys when no encryption keys are configured.
+var ErrEncryptionDisabled = errors.New("encryption keys are not configured")
+
+// RotateKeys re-encrypts students and their history which are encrypted with previous keys, or have fields encrypted
+// which are no longer configured to be, or the other way round. Records in trash are re-encrypted as well.
+// Students are replaced without changing their revision and the change is not recorded to history,
+// students modified concurrently are already stored with the current key and are skipped.
+// Returns the numbers of re-encrypted students and history entries and an error.
+func (sr *StudentsRepo) RotateKeys(ctx context.Context) (*domain.KeyRotationResult, error) {
+	if sr.crypt == nil {
+		return nil, ErrEncryptionDisabled
+	}
+
+	result := &domain.KeyRotationResult{}
+
+	cur, err := sr.col.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var student domain.StudentRecord
+		if err := cur.Decode(&student); err != nil {
+			return nil, err
+		}
+		if sr.crypt.current(&student) {
+			continue
+		}
+
+		objectId, err := primitive.ObjectIDFromHex(student.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := sr.crypt.decrypt(&student); err != nil {
+			return nil, err
+		}
+		if err := sr.crypt.encrypt(&student); err != nil {
+			return nil, err
+		}
+		student.ID = ""
+
+		res, err := sr.col.ReplaceOne(ctx, revisionFilter(objectId, student.Revision), student)
+		if err != nil {
+			return nil, err
+		}
+		result.Students += res.ModifiedCount
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	historyCur, err := sr.history.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer historyCur.Close(ctx)
+
+	for historyCur.Next(ctx) {
+		var entry domain.HistoryEntry
+		if err := historyCur.Decode(&entry); err != nil {
+			return nil, err
+		}
+		if sr.crypt.currentEntry(&entry) {
+			continue
+		}
+
+		objectId, err := primitive.ObjectIDFromHex(entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := sr.crypt.decryptEntry(&entry); err != nil {
+			return nil, err
+		}
+		if err := sr.crypt.encryptEntry(&entry); err != nil {
+			return nil, err
+		}
+		entry.ID = ""
+
+		res, err := sr.history.ReplaceOne(ctx, bson.M{"_id": objectId}, entry)
+		if err != nil {
+			return nil, err
+		}
+		result.HistoryEntries += res.ModifiedCount
+	}
+
+	return result, historyCur.Err()
+}
+
+// currentEntry reports whether history entry snapshot and changes are encrypted the way they are stored now.
+func (sc *studentCrypt) currentEntry(entry *domain.HistoryEntry) bool {
+	if !sc.current(&entry.Snapshot) {
+		return false
+	}
+
+	for _, change := range entry.Changes {
+		for _, value := range []interface{}{change.Old, change.New} {
+			s, ok := value.(string)
+			if !ok || s == "" {
+				continue
+			}
+			if sc.encrypted(change.Field) != fieldcrypt.IsEncrypted(s) ||
+				(fieldcrypt.IsEncrypted(s) && !sc.cipher.IsCurrent(s)) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
 	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
@@ -29,6 +30,7 @@ func NewSchemaRepo(db *mongo.Database) *SchemaRepo {
 }
 
 func (sr *SchemaRepo) Create(ctx context.Context, schema domain.Schema) (string, error) {
+	schema.Revision = 1
 
 	res, err := sr.db.InsertOne(ctx, schema)
 
@@ -46,6 +48,7 @@ func (sr *SchemaRepo) Create(ctx context.Context, schema domain.Schema) (string,
 	return stringId, nil
 }
 
+// GetById returns schema which is not moved to trash.
 func (sr *SchemaRepo) GetById(ctx context.Context, id string) (*domain.Schema, error) {
 	objectId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -54,7 +57,8 @@ func (sr *SchemaRepo) GetById(ctx context.Context, id string) (*domain.Schema, e
 
 	var schema domain.Schema
 	if err := sr.db.FindOne(ctx, bson.M{
-		"_id": objectId,
+		"_id":        objectId,
+		"deleted_at": nil,
 	}).Decode(&schema); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, domain.ErrNotFound
@@ -66,14 +70,36 @@ func (sr *SchemaRepo) GetById(ctx context.Context, id string) (*domain.Schema, e
 	return &schema, nil
 }
 
-func (sr *SchemaRepo) Update(ctx context.Context, id string, input domain.UpdateSchemaInput) error {
+// Update sets non-nil fields of input and increments schema revision.
+// The schema is updated only if it has revision, or any revision when it is nil,
+// otherwise ErrPreconditionFailed is returned.
+func (sr *SchemaRepo) Update(ctx context.Context, id string, input domain.UpdateSchemaInput, revision *int) error {
 	objectId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	_, err = sr.db.UpdateOne(ctx,
-		bson.M{"_id": objectId}, bson.M{"$set": input})
+	filter := bson.M{"_id": objectId}
+	if revision != nil {
+		filter = revisionFilter(objectId, *revision)
+	}
+	filter["deleted_at"] = nil
+
+	res, err := sr.db.UpdateOne(ctx, filter, bson.M{
+		"$set": input,
+		"$inc": bson.M{"revision": 1},
+	})
+
+	if err == nil && revision != nil && res.MatchedCount == 0 {
+		count, err := sr.db.CountDocuments(ctx, bson.M{"_id": objectId, "deleted_at": nil})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return domain.ErrNotFound
+		}
+		return domain.ErrPreconditionFailed
+	}
 
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
@@ -89,29 +115,74 @@ func (sr *SchemaRepo) Update(ctx context.Context, id string, input domain.Update
 	return nil
 }
 
-func (sr *SchemaRepo) FindAll(ctx context.Context) ([]domain.Schema, error) {
-	var schemas []domain.Schema
+func (sr *SchemaRepo) FindAll(ctx context.Context, options domain.ListSchemasOptions) ([]domain.Schema, *domain.PageInfo, error) {
+	sort := options.Sort
+	if len(sort) == 0 {
+		sort = []domain.SortField{{Field: idField}}
+	}
 
-	cur, err := sr.db.Find(ctx, bson.M{})
+	filter := bson.M{}
+	if !options.WithDeleted {
+		filter["deleted_at"] = nil
+	}
+
+	return findPage[domain.Schema](ctx, sr.db, filter, sort, options.PageOptions)
+}
+
+// Delete moves schema to trash. Schemas in trash keep their slugs, so their names can not be reused until purged.
+func (sr *SchemaRepo) Delete(ctx context.Context, id string) error {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	res, err := sr.db.UpdateOne(ctx, bson.M{"_id": objectId, "deleted_at": nil}, trashUpdate(ctx, time.Now().UTC()))
+	if res != nil && res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+
+	return err
+}
+
+// ListDeleted returns up to limit schemas moved to trash, the most recently deleted first.
+func (sr *SchemaRepo) ListDeleted(ctx context.Context, limit int) ([]domain.Schema, error) {
+	opts := getPaginationOpts(limit, 0).SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: 1}})
+
+	cur, err := sr.db.Find(ctx, bson.M{"deleted_at": bson.M{"$ne": nil}}, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	err = cur.All(ctx, &schemas)
+	schemas := []domain.Schema{}
+	if err := cur.All(ctx, &schemas); err != nil {
+		return nil, err
+	}
 
-	return schemas, err
+	return schemas, nil
 }
 
-func (sr *SchemaRepo) Delete(ctx context.Context, id string) error {
+// Undelete restores schema from trash.
+func (sr *SchemaRepo) Undelete(ctx context.Context, id string) error {
 	objectId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	res, err := sr.db.DeleteOne(ctx, bson.M{"_id": objectId})
-	if res != nil && res.DeletedCount == 0 {
+	res, err := sr.db.UpdateOne(ctx, bson.M{"_id": objectId, "deleted_at": bson.M{"$ne": nil}}, untrashUpdate)
+	if res != nil && res.MatchedCount == 0 {
 		return domain.ErrNotFound
 	}
 
 	return err
 }
+
+// Purge deletes schemas moved to trash before the time permanently.
+// Returns the number of deleted schemas and an error.
+func (sr *SchemaRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := sr.db.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
@@ -217,7 +217,7 @@ var schemasTestCaseGroup = []SchemasTestCaseGroup{
 			if !ok {
 				return errors.New("invalid input type")
 			}
-			err := repo.Update(ctx, tc.inputID, in)
+			err := repo.Update(ctx, tc.inputID, in, nil)
 
 			err, _ = checkError(tc, err)
 
@@ -285,7 +285,7 @@ var schemasTestCaseGroup = []SchemasTestCaseGroup{
 	{
 		name: "FindAll",
 		executeMethod: func(ctx context.Context, repo *SchemaRepo, tc *SchemasTestCase) error {
-			schemasList, err := repo.FindAll(ctx)
+			schemasList, _, err := repo.FindAll(ctx, domain.ListSchemasOptions{})
 
 			err, skip := checkError(tc, err)
 			if err != nil {
@@ -0,0 +1,278 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var _ ports.StatsStore = (*StatsRepo)(nil)
+
+const locationField = "student_wac.location"
+
+// scorePercentiles are percentiles calculated for project scores.
+var scorePercentiles = []float64{25, 50, 75, 90}
+
+type AggregateCollection interface {
+	Aggregate(ctx context.Context, pipeline interface{},
+		opts ...*options.AggregateOptions) (*mongo.Cursor, error)
+}
+
+type StatsRepo struct {
+	students AggregateCollection
+	// encryption lists encrypted fields, which are not grouped by.
+	encryption config.EncryptionConfig
+}
+
+func NewStatsRepo(db *mongo.Database, cfg config.EncryptionConfig) *StatsRepo {
+	return &StatsRepo{
+		students:   db.Collection(studentsCollection),
+		encryption: cfg,
+	}
+}
+
+type countResult struct {
+	Value string `bson:"_id"`
+	Count int64  `bson:"count"`
+}
+
+type fileResult struct {
+	FileName   string    `bson:"_id"`
+	Count      int64     `bson:"count"`
+	ImportedAt time.Time `bson:"imported_at"`
+}
+
+// scoreCount is the number of scores of a project equal to the score.
+type scoreCount struct {
+	Score float64 `bson:"score"`
+	Count int64   `bson:"count"`
+}
+
+type projectScoreResult struct {
+	Key struct {
+		Project string  `bson:"project"`
+		Score   float64 `bson:"score"`
+	} `bson:"_id"`
+	Count int64 `bson:"count"`
+}
+
+type attendanceResult struct {
+	Students             int64 `bson:"students"`
+	Registered           int64 `bson:"registered"`
+	Attended             int64 `bson:"attended"`
+	RegisteredNotVisited int64 `bson:"registered_not_visited"`
+}
+
+type studentsStatsResult struct {
+	Total            []struct{ Count int64 } `bson:"total"`
+	BySource         []countResult           `bson:"by_source"`
+	ByStatus         []countResult           `bson:"by_status"`
+	ByFile           []fileResult            `bson:"by_file"`
+	ByLocation       []countResult           `bson:"by_location"`
+	ByMembershipType []countResult           `bson:"by_membership_type"`
+	Attendance       []attendanceResult      `bson:"attendance"`
+}
+
+// StudentsStats calculates students statistics with a single aggregation of faceted pipelines,
+// project scores are aggregated separately by projectsStats. Students are not counted by encrypted location.
+func (sr *StatsRepo) StudentsStats(ctx context.Context, options domain.StudentsStatsOptions) (*domain.StudentsStats, error) {
+	match := bson.M{"$match": studentsFilter(domain.ListStudentsOptions{
+		Source: options.Source,
+		Filter: options.Filter,
+	})}
+
+	facets := bson.M{
+		"total":              bson.A{bson.M{"$count": "count"}},
+		"by_source":          countByPipeline("$source"),
+		"by_status":          countByPipeline("$status"),
+		"by_membership_type": countByPipeline("$student_wac.membership_type"),
+		"by_file": bson.A{
+			bson.M{"$group": bson.M{
+				"_id":         "$file_name",
+				"count":       bson.M{"$sum": 1},
+				"imported_at": bson.M{"$max": "$imported_at"},
+			}},
+			bson.M{"$sort": bson.D{{Key: "imported_at", Value: -1}, {Key: "_id", Value: 1}}},
+		},
+		"attendance": bson.A{
+			bson.M{"$match": bson.M{"source": domain.WAC}},
+			bson.M{"$group": bson.M{
+				"_id":                    nil,
+				"students":               bson.M{"$sum": 1},
+				"registered":             bson.M{"$sum": "$student_wac.registered"},
+				"attended":               bson.M{"$sum": "$student_wac.attended_events"},
+				"registered_not_visited": bson.M{"$sum": "$student_wac.registered_not_visited"},
+			}},
+		},
+	}
+	if !sr.encryption.Encrypted(locationField) {
+		facets["by_location"] = countByPipeline("$" + locationField)
+	}
+
+	cur, err := sr.students.Aggregate(ctx, bson.A{match, bson.M{"$facet": facets}})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []studentsStatsResult
+	if err := cur.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	stats := &domain.StudentsStats{
+		BySource:         []domain.CountItem{},
+		ByStatus:         []domain.CountItem{},
+		ByFile:           []domain.FileCount{},
+		ByLocation:       []domain.CountItem{},
+		ByMembershipType: []domain.CountItem{},
+		Projects:         []domain.ProjectScoreStats{},
+	}
+	if len(results) == 0 {
+		return stats, nil
+	}
+
+	if stats.Projects, err = sr.projectsStats(ctx, match); err != nil {
+		return nil, err
+	}
+
+	result := results[0]
+	if len(result.Total) > 0 {
+		stats.Total = result.Total[0].Count
+	}
+	stats.BySource = toCountItems(result.BySource)
+	stats.ByStatus = toCountItems(result.ByStatus)
+	stats.ByLocation = toCountItems(result.ByLocation)
+	stats.ByMembershipType = toCountItems(result.ByMembershipType)
+
+	for _, f := range result.ByFile {
+		stats.ByFile = append(stats.ByFile, domain.FileCount(f))
+	}
+
+	if len(result.Attendance) > 0 {
+		a := result.Attendance[0]
+		stats.Attendance = domain.AttendanceStats{
+			Students:             a.Students,
+			Registered:           a.Registered,
+			Attended:             a.Attended,
+			RegisteredNotVisited: a.RegisteredNotVisited,
+		}
+		if a.Registered > 0 {
+			stats.Attendance.Rate = float64(a.Attended) / float64(a.Registered)
+		}
+	}
+
+	return stats, nil
+}
+
+// projectsStats calculates project score statistics of students matched by match stage.
+// Scores are counted by project and value and read through a cursor sorted by them, so neither the
+// aggregation nor the result holds every score.
+func (sr *StatsRepo) projectsStats(ctx context.Context, match bson.M) ([]domain.ProjectScoreStats, error) {
+	cur, err := sr.students.Aggregate(ctx, bson.A{
+		match,
+		bson.M{"$unwind": "$student_rss.projects"},
+		bson.M{"$group": bson.M{
+			"_id": bson.M{
+				"project": "$student_rss.projects.name",
+				"score":   bson.M{"$ifNull": bson.A{"$student_rss.projects.score", 0}},
+			},
+			"count": bson.M{"$sum": 1},
+		}},
+		bson.M{"$sort": bson.D{{Key: "_id.project", Value: 1}, {Key: "_id.score", Value: 1}}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	projects := []domain.ProjectScoreStats{}
+	var scores []scoreCount
+	var sum float64
+	flush := func() {
+		if len(scores) == 0 {
+			return
+		}
+		p := &projects[len(projects)-1]
+		p.Min = scores[0].Score
+		p.Max = scores[len(scores)-1].Score
+		p.Avg = sum / float64(p.Count)
+		for _, pct := range scorePercentiles {
+			p.Percentiles[fmt.Sprintf("p%v", pct)] = percentile(scores, p.Count, pct)
+		}
+		scores, sum = nil, 0
+	}
+
+	for cur.Next(ctx) {
+		var r projectScoreResult
+		if err := cur.Decode(&r); err != nil {
+			return nil, err
+		}
+		if len(projects) == 0 || projects[len(projects)-1].Project != r.Key.Project {
+			flush()
+			projects = append(projects, domain.ProjectScoreStats{
+				Project:     r.Key.Project,
+				Percentiles: make(map[string]float64, len(scorePercentiles)),
+			})
+		}
+
+		projects[len(projects)-1].Count += r.Count
+		scores = append(scores, scoreCount{Score: r.Key.Score, Count: r.Count})
+		sum += r.Key.Score * float64(r.Count)
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return projects, nil
+}
+
+func countByPipeline(field string) bson.A {
+	return bson.A{
+		bson.M{"$group": bson.M{"_id": field, "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+}
+
+func toCountItems(results []countResult) []domain.CountItem {
+	items := make([]domain.CountItem, 0, len(results))
+	for _, r := range results {
+		items = append(items, domain.CountItem(r))
+	}
+
+	return items
+}
+
+// percentile calculates p-th percentile of n values counted in ascending sorted scores
+// with linear interpolation between closest ranks.
+func percentile(sorted []scoreCount, n int64, p float64) float64 {
+	if n == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(n-1)
+	lower := valueAt(sorted, int64(math.Floor(rank)))
+	upper := valueAt(sorted, int64(math.Ceil(rank)))
+
+	return lower + (upper-lower)*(rank-math.Floor(rank))
+}
+
+// valueAt returns i-th value counted in sorted scores.
+func valueAt(sorted []scoreCount, i int64) float64 {
+	for _, s := range sorted {
+		if i < s.Count {
+			return s.Score
+		}
+		i -= s.Count
+	}
+
+	return sorted[len(sorted)-1].Score
+}
@@ -0,0 +1,216 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		values []scoreCount
+		p      float64
+		want   float64
+	}{
+		{nil, 50, 0},
+		{[]scoreCount{{7, 1}}, 90, 7},
+		{[]scoreCount{{10, 1}, {20, 1}, {30, 1}, {40, 1}}, 50, 25},
+		{[]scoreCount{{10, 1}, {20, 1}, {30, 1}, {40, 1}}, 90, 37},
+		{[]scoreCount{{10, 1}, {20, 1}, {30, 1}, {40, 1}, {50, 1}}, 25, 20},
+		{[]scoreCount{{10, 2}, {20, 1}, {40, 1}}, 50, 15},
+		{[]scoreCount{{10, 3}, {20, 2}}, 75, 20},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("p%v of %v", tt.p, tt.values), func(t *testing.T) {
+			var n int64
+			for _, v := range tt.values {
+				n += v.Count
+			}
+			if got := percentile(tt.values, n, tt.p); got != tt.want {
+				t.Errorf("percentile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsRepo_StudentsStats(t *testing.T) {
+	mt := getMockTest(t)
+	defer mt.Close()
+
+	importedAt := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	mt.Run("success", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, fmt.Sprintf("%s.%s", testDbName, studentsCollection), mtest.FirstBatch, bson.D{
+			{Key: "total", Value: bson.A{bson.D{{Key: "count", Value: 3}}}},
+			{Key: "by_source", Value: bson.A{
+				bson.D{{Key: "_id", Value: domain.RSS}, {Key: "count", Value: 2}},
+				bson.D{{Key: "_id", Value: domain.WAC}, {Key: "count", Value: 1}},
+			}},
+			{Key: "by_status", Value: bson.A{bson.D{{Key: "_id", Value: nil}, {Key: "count", Value: 3}}}},
+			{Key: "by_file", Value: bson.A{
+				bson.D{{Key: "_id", Value: "rss.xlsx"}, {Key: "count", Value: 2}, {Key: "imported_at", Value: importedAt}},
+			}},
+			{Key: "by_location", Value: bson.A{}},
+			{Key: "by_membership_type", Value: bson.A{}},
+			{Key: "attendance", Value: bson.A{
+				bson.D{{Key: "students", Value: 1}, {Key: "registered", Value: 4}, {Key: "attended", Value: 3},
+					{Key: "registered_not_visited", Value: 1}},
+			}},
+		}), mtest.CreateCursorResponse(0, fmt.Sprintf("%s.%s", testDbName, studentsCollection), mtest.FirstBatch,
+			projectScore("Droid", 50, 1),
+			projectScore("Lightsaber", 10, 1),
+			projectScore("Lightsaber", 20, 1),
+			projectScore("Lightsaber", 30, 1),
+			projectScore("Lightsaber", 40, 1),
+		))
+
+		repo := NewStatsRepo(mt.DB, config.EncryptionConfig{})
+		got, err := repo.StudentsStats(context.Background(), domain.StudentsStatsOptions{})
+		if err != nil {
+			t.Fatalf("StudentsStats() error = %v", err)
+		}
+
+		want := &domain.StudentsStats{
+			Total:            3,
+			BySource:         []domain.CountItem{{Value: domain.RSS, Count: 2}, {Value: domain.WAC, Count: 1}},
+			ByStatus:         []domain.CountItem{{Value: "", Count: 3}},
+			ByFile:           []domain.FileCount{{FileName: "rss.xlsx", Count: 2, ImportedAt: importedAt}},
+			ByLocation:       []domain.CountItem{},
+			ByMembershipType: []domain.CountItem{},
+			Projects: []domain.ProjectScoreStats{{
+				Project:     "Droid",
+				Count:       1,
+				Min:         50,
+				Max:         50,
+				Avg:         50,
+				Percentiles: map[string]float64{"p25": 50, "p50": 50, "p75": 50, "p90": 50},
+			}, {
+				Project: "Lightsaber",
+				Count:   4,
+				Min:     10,
+				Max:     40,
+				Avg:     25,
+				Percentiles: map[string]float64{
+					"p25": 17.5,
+					"p50": 25,
+					"p75": 32.5,
+					"p90": 37,
+				},
+			}},
+			Attendance: domain.AttendanceStats{
+				Students:             1,
+				Registered:           4,
+				Attended:             3,
+				RegisteredNotVisited: 1,
+				Rate:                 0.75,
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("StudentsStats() = %+v, want %+v", got, want)
+		}
+	})
+
+	mt.Run("encrypted location", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, fmt.Sprintf("%s.%s", testDbName, studentsCollection), mtest.FirstBatch,
+			bson.D{{Key: "total", Value: bson.A{bson.D{{Key: "count", Value: 1}}}}},
+		), mtest.CreateCursorResponse(0, fmt.Sprintf("%s.%s", testDbName, studentsCollection), mtest.FirstBatch))
+
+		repo := NewStatsRepo(mt.DB, config.EncryptionConfig{Fields: []string{"student_wac.location"}})
+		got, err := repo.StudentsStats(context.Background(), domain.StudentsStatsOptions{})
+		if err != nil {
+			t.Fatalf("StudentsStats() error = %v", err)
+		}
+		if got.Total != 1 || len(got.ByLocation) != 0 {
+			t.Errorf("StudentsStats() = %+v, want 1 student and no locations", got)
+		}
+
+		facets := mt.GetAllStartedEvents()[0].Command.Lookup("pipeline", "1", "$facet").Document()
+		if _, err := facets.LookupErr("by_location"); err == nil {
+			t.Error("StudentsStats() groups students by encrypted location")
+		}
+		if _, err := facets.LookupErr("by_status"); err != nil {
+			t.Errorf("StudentsStats() has no by_status facet: %v", err)
+		}
+	})
+
+	mt.Run("failure", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "some error"}))
+
+		repo := NewStatsRepo(mt.DB, config.EncryptionConfig{})
+		if _, err := repo.StudentsStats(context.Background(), domain.StudentsStatsOptions{}); err == nil {
+			t.Error("StudentsStats() expected an error")
+		}
+	})
+}
+
+func TestStatsRepo_TimeSeries(t *testing.T) {
+	mt := getMockTest(t)
+	defer mt.Close()
+
+	october := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	november := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	point := func(start time.Time, group string, count int) bson.D {
+		return bson.D{
+			{Key: "_id", Value: bson.D{{Key: "start", Value: start}, {Key: "group", Value: group}}},
+			{Key: "count", Value: count},
+		}
+	}
+
+	mt.Run("success", func(mt *mtest.T) {
+		ns := fmt.Sprintf("%s.%s", testDbName, studentsCollection)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(1, ns, mtest.FirstBatch, point(october, "Force", 2), point(november, "Force", 1)),
+			mtest.CreateCursorResponse(0, ns, mtest.NextBatch, point(october, "Lightsaber", 3)),
+		)
+
+		repo := NewStatsRepo(mt.DB, config.EncryptionConfig{})
+		got, err := repo.TimeSeries(context.Background(), domain.TimeSeriesOptions{
+			Metric:   domain.MetricCompletions,
+			Interval: domain.IntervalMonth,
+			GroupBy:  domain.GroupByProject,
+		})
+		if err != nil {
+			t.Fatalf("TimeSeries() error = %v", err)
+		}
+
+		want := &domain.TimeSeries{
+			Metric:   domain.MetricCompletions,
+			Interval: domain.IntervalMonth,
+			GroupBy:  domain.GroupByProject,
+			Series: []domain.Series{
+				{Group: "Force", Points: []domain.TimePoint{{Start: october, Count: 2}, {Start: november, Count: 1}}},
+				{Group: "Lightsaber", Points: []domain.TimePoint{{Start: october, Count: 3}}},
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("TimeSeries() = %+v, want %+v", got, want)
+		}
+	})
+
+	mt.Run("encrypted group", func(mt *mtest.T) {
+		repo := NewStatsRepo(mt.DB, config.EncryptionConfig{Fields: []string{"student_wac.location"}})
+		_, err := repo.TimeSeries(context.Background(), domain.TimeSeriesOptions{
+			Metric:   domain.MetricJoins,
+			Interval: domain.IntervalMonth,
+			GroupBy:  "location",
+		})
+		if !errors.Is(err, domain.ErrInvalidParams) {
+			t.Errorf("TimeSeries() error = %v, want %v", err, domain.ErrInvalidParams)
+		}
+	})
+}
+
+func projectScore(project string, score float64, count int) bson.D {
+	return bson.D{
+		{Key: "_id", Value: bson.D{{Key: "project", Value: project}, {Key: "score", Value: score}}},
+		{Key: "count", Value: count},
+	}
+}
@@ -3,6 +3,12 @@ package mongodb
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
 	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -23,18 +29,46 @@ type StudentCollection interface {
 		opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
 	DeleteOne(ctx context.Context, filter interface{},
 		opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	CountDocuments(ctx context.Context, filter interface{},
+		opts ...*options.CountOptions) (int64, error)
+	ReplaceOne(ctx context.Context, filter interface{}, replacement interface{},
+		opts ...*options.ReplaceOptions) (*mongo.UpdateResult, error)
+	DeleteMany(ctx context.Context, filter interface{},
+		opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	BulkWrite(ctx context.Context, models []mongo.WriteModel,
+		opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error)
+	UpdateMany(ctx context.Context, filter interface{}, update interface{},
+		opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+	Aggregate(ctx context.Context, pipeline interface{},
+		opts ...*options.AggregateOptions) (*mongo.Cursor, error)
 }
 
 type StudentsRepo struct {
-	col StudentCollection
+	col     StudentCollection
+	history HistoryCollection
+	// crypt encrypts student fields configured by encryption config, it is nil when encryption is disabled.
+	crypt *studentCrypt
+	now   func() time.Time
 }
 
-func NewStudentsRepo(db *mongo.Database) *StudentsRepo {
-	return newRepo(db.Collection(studentsCollection))
+func NewStudentsRepo(db *mongo.Database, cfg config.EncryptionConfig) (*StudentsRepo, error) {
+	crypt, err := newStudentCrypt(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := newRepo(db.Collection(studentsCollection), db.Collection(historyCollection))
+	sr.crypt = crypt
+
+	return sr, nil
 }
 
-func newRepo(col StudentCollection) *StudentsRepo {
-	return &StudentsRepo{col}
+func newRepo(col StudentCollection, history HistoryCollection) *StudentsRepo {
+	return &StudentsRepo{
+		col:     col,
+		history: history,
+		now:     time.Now,
+	}
 }
 
 func (sr *StudentsRepo) SaveRSS(ctx context.Context, fileName string, email string, student domain.StudentRSS) (string, error) {
@@ -42,6 +76,7 @@ func (sr *StudentsRepo) SaveRSS(ctx context.Context, fileName string, email stri
 		Source:     domain.RSS,
 		Email:      email,
 		FileName:   fileName,
+		ImportedAt: sr.now().UTC(),
 		StudentRSS: student,
 	}
 	return sr.save(ctx, s)
@@ -52,21 +87,25 @@ func (sr *StudentsRepo) SaveWAC(ctx context.Context, fileName string, email stri
 		Source:     domain.WAC,
 		Email:      email,
 		FileName:   fileName,
+		ImportedAt: sr.now().UTC(),
 		StudentWAC: student,
 	}
 	return sr.save(ctx, s)
 }
 
+// GetById returns student record which is not moved to trash.
 func (sr *StudentsRepo) GetById(ctx context.Context, id string) (*domain.StudentRecord, error) {
 	objectId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
 	}
 
+	return sr.findOne(ctx, bson.M{"_id": objectId, "deleted_at": nil})
+}
+
+func (sr *StudentsRepo) findOne(ctx context.Context, filter bson.M, opts ...*options.FindOneOptions) (*domain.StudentRecord, error) {
 	var student domain.StudentRecord
-	if err := sr.col.FindOne(ctx, bson.M{
-		"_id": objectId,
-	}).Decode(&student); err != nil {
+	if err := sr.col.FindOne(ctx, filter, opts...).Decode(&student); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, domain.ErrNotFound
 		}
@@ -74,79 +113,704 @@ func (sr *StudentsRepo) GetById(ctx context.Context, id string) (*domain.Student
 		return nil, err
 	}
 
+	if err := sr.crypt.decrypt(&student); err != nil {
+		return nil, err
+	}
+
 	return &student, nil
 }
 
+// save inserts imported student record, or re-imports it when there is a record of the same source and email
+// imported from another file, so rows of one file with the same email are saved as separate records.
+// Re-import of a record modified concurrently is retried with the record read again.
 func (sr *StudentsRepo) save(ctx context.Context, student domain.StudentRecord) (string, error) {
-	res, err := sr.col.InsertOne(ctx, student)
+	student.Normalize()
+	if student.FileName != "" {
+		student.ImportedFrom = []string{student.FileName}
+	}
+
+	if student.Email != "" {
+		emailField, email := sr.crypt.emailLookup(student.Email)
+		filter := bson.M{
+			"source":        student.Source,
+			emailField:      email,
+			"file_name":     bson.M{"$ne": student.FileName},
+			"imported_from": bson.M{"$ne": student.FileName},
+			"deleted_at":    nil,
+		}
+
+		for attempt := 1; ; attempt++ {
+			existing, err := sr.findOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "imported_at", Value: -1}}))
+			if errors.Is(err, domain.ErrNotFound) {
+				break
+			}
+			if err != nil {
+				return "", err
+			}
+
+			err = sr.reimport(ctx, existing, student)
+			if errors.Is(err, domain.ErrPreconditionFailed) && attempt < maxRecordAttempts {
+				continue
+			}
+
+			return existing.ID, err
+		}
+	}
+
+	student.Revision = 1
+	stored := student
+	if err := sr.crypt.encrypt(&stored); err != nil {
+		return "", err
+	}
+
+	res, err := sr.col.InsertOne(ctx, stored)
 	if err != nil {
 		return "", err
 	}
 
-	oid := getIdFromObjectID(res.InsertedID)
+	student.ID = getIdFromObjectID(res.InsertedID)
+	sr.record(ctx, domain.HistoryCreated, nil, &student, 0)
+
+	return student.ID, nil
+}
+
+// reimport sets imported data of the source to the existing record, so staff tags, notes and annotations,
+// status and data merged from other records are kept. The file is added to the files the record is imported from,
+// records saved before they were tracked keep their previous file as well.
+// The change is recorded to the student history.
+func (sr *StudentsRepo) reimport(ctx context.Context, existing *domain.StudentRecord, student domain.StudentRecord) error {
+	objectId, err := primitive.ObjectIDFromHex(existing.ID)
+	if err != nil {
+		return err
+	}
+
+	importedFrom := existing.ImportedFrom
+	if len(importedFrom) == 0 && existing.FileName != "" {
+		importedFrom = []string{existing.FileName}
+	}
+
+	after := *existing
+	after.FileName = student.FileName
+	after.ImportedFrom = append(append([]string(nil), importedFrom...), student.ImportedFrom...)
+	after.ImportedAt = student.ImportedAt
+	after.Revision++
+
+	stored := student
+	if err := sr.crypt.encrypt(&stored); err != nil {
+		return err
+	}
+
+	set := bson.M{"file_name": student.FileName, "imported_at": student.ImportedAt}
+	if len(after.ImportedFrom) > 0 {
+		set["imported_from"] = after.ImportedFrom
+	}
+	if student.Source == domain.WAC {
+		after.StudentWAC = student.StudentWAC
+		set["student_wac"] = stored.StudentWAC
+	} else {
+		after.StudentRSS = student.StudentRSS
+		set["student_rss"] = stored.StudentRSS
+	}
+
+	res, err := sr.col.UpdateOne(ctx, revisionFilter(objectId, existing.Revision), bson.M{
+		"$set": set,
+		"$inc": bson.M{"revision": 1},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrPreconditionFailed
+	}
+
+	sr.record(ctx, domain.HistoryUpdated, existing, &after, 0)
+
+	return nil
+}
+
+func (sr *StudentsRepo) GetAll(ctx context.Context, options domain.ListStudentsOptions) ([]domain.StudentRecord, *domain.PageInfo, error) {
+	students, info, err := findPage[domain.StudentRecord](ctx, sr.col, sr.studentsFilter(options), options.Sort, options.PageOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := sr.crypt.decryptAll(students); err != nil {
+		return nil, nil, err
+	}
+
+	return students, info, nil
+}
+
+// MaxProjects returns the largest number of projects of students matching options.
+func (sr *StudentsRepo) MaxProjects(ctx context.Context, options domain.ListStudentsOptions) (int, error) {
+	cur, err := sr.col.Aggregate(ctx, bson.A{
+		bson.M{"$match": sr.studentsFilter(options)},
+		bson.M{"$group": bson.M{
+			"_id":      nil,
+			"projects": bson.M{"$max": bson.M{"$size": bson.M{"$ifNull": bson.A{"$student_rss.projects", bson.A{}}}}},
+		}},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var results []struct {
+		Projects int `bson:"projects"`
+	}
+	if err := cur.All(ctx, &results); err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+
+	return results[0].Projects, nil
+}
+
+// Iterate calls fn for every student matching options one by one without loading all of them into memory.
+// Page options are ignored. Iteration stops on the first error returned by fn.
+func (sr *StudentsRepo) Iterate(ctx context.Context, listOptions domain.ListStudentsOptions, fn func(student *domain.StudentRecord) error) error {
+	opts := options.Find().SetSort(sortToBson(sortFields(listOptions.Sort)))
+
+	cur, err := sr.col.Find(ctx, sr.studentsFilter(listOptions), opts)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var student domain.StudentRecord
+		if err := cur.Decode(&student); err != nil {
+			return err
+		}
+		if err := sr.crypt.decrypt(&student); err != nil {
+			return err
+		}
+		if err := fn(&student); err != nil {
+			return err
+		}
+	}
 
-	return oid, nil
+	return cur.Err()
 }
 
-func (sr *StudentsRepo) GetAll(ctx context.Context, options domain.ListStudentsOptions) ([]domain.StudentRecord, error) {
-	var students []domain.StudentRecord
-	opts := getPaginationOpts(options.Limit, options.Skip)
-	opts.SetSort(options.Sort)
+// studentsFilter matches students by options, encrypted email is matched by its blind index.
+func (sr *StudentsRepo) studentsFilter(options domain.ListStudentsOptions) bson.M {
+	filter := studentsFilter(options)
+	if options.Email != "" {
+		delete(filter, emailField)
+		field, email := sr.crypt.emailLookup(options.Email)
+		filter[field] = email
+	}
+
+	return filter
+}
 
+func studentsFilter(options domain.ListStudentsOptions) bson.M {
 	filter := bson.M{}
+	if options.IDs != nil {
+		objectIds := bson.A{}
+		for _, id := range options.IDs {
+			if objectId, err := primitive.ObjectIDFromHex(id); err == nil {
+				objectIds = append(objectIds, objectId)
+			}
+		}
+		filter["_id"] = bson.M{"$in": objectIds}
+	}
 	if options.Email != "" {
 		filter["email"] = options.Email
 	}
 	if options.Source != "" {
 		filter["source"] = options.Source
 	}
+	if options.Status != "" {
+		filter["status"] = options.Status
+	}
+	if options.FileName != "" {
+		filter["file_name"] = options.FileName
+	}
+	if options.Tag != "" {
+		filter["tags.name"] = options.Tag
+	}
+	if options.Filter != nil {
+		filter["$and"] = bson.A{filterToBson(options.Filter)}
+	}
+	if !options.WithDeleted {
+		filter["deleted_at"] = nil
+	}
+
+	return filter
+}
+
+// Search finds students by text index ordered by relevance.
+// Partial words are not matched by text index, so regex search is used when nothing is found.
+// Encrypted fields are not matched.
+func (sr *StudentsRepo) Search(ctx context.Context, options domain.SearchStudentsOptions) ([]domain.StudentSearchResult, error) {
+	filter := bson.M{"$text": bson.M{"$search": options.Query}, "deleted_at": nil}
+	if options.Source != "" {
+		filter["source"] = options.Source
+	}
+
+	score := bson.M{"$meta": "textScore"}
+	opts := getPaginationOpts(options.Limit, 0).
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}})
+
+	results, err := sr.find(ctx, filter, opts)
+	if err != nil || len(results) > 0 {
+		return results, err
+	}
+
+	return sr.find(ctx, searchRegexFilter(options, sr.crypt.encrypted), getPaginationOpts(options.Limit, 0))
+}
+
+func (sr *StudentsRepo) find(ctx context.Context, filter interface{}, opts *options.FindOptions) ([]domain.StudentSearchResult, error) {
+	var results []domain.StudentSearchResult
 
 	cur, err := sr.col.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	err = cur.All(ctx, &students)
+	if err := cur.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		if err := sr.crypt.decrypt(&results[i].Student); err != nil {
+			return nil, err
+		}
+	}
 
-	return students, err
+	return results, nil
 }
 
-func (sr *StudentsRepo) Update(ctx context.Context, id string, input domain.StudentRecord) error {
-	objectId, err := primitive.ObjectIDFromHex(id)
+// searchRegexFilter matches students having every query word as a part of any text index field,
+// encrypted fields are not matched.
+func searchRegexFilter(options domain.SearchStudentsOptions, encrypted func(field string) bool) bson.M {
+	words := bson.A{}
+	for _, word := range strings.Fields(options.Query) {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(word), Options: "i"}
+		fields := bson.A{}
+		for _, field := range studentsTextFields {
+			if !encrypted(field.Key) {
+				fields = append(fields, bson.M{field.Key: pattern})
+			}
+		}
+		if len(fields) == 0 {
+			// every text field is encrypted, so the word matches nothing
+			fields = append(fields, bson.M{"_id": bson.M{"$exists": false}})
+		}
+		words = append(words, bson.M{"$or": fields})
+	}
+
+	filter := bson.M{"$and": words, "deleted_at": nil}
+	if options.Source != "" {
+		filter["source"] = options.Source
+	}
+
+	return filter
+}
+
+// Update sets non-empty fields of input and records changed fields to the student history.
+// The record is updated only if it has revision, or any revision when it is nil,
+// otherwise ErrPreconditionFailed is returned.
+func (sr *StudentsRepo) Update(ctx context.Context, id string, input domain.StudentRecord, revision *int) error {
+	before, after, err := sr.update(ctx, id, input, revision)
 	if err != nil {
 		return err
 	}
 
-	_, err = sr.col.UpdateOne(ctx,
-		bson.M{"_id": objectId}, bson.M{"$set": input})
+	sr.record(ctx, domain.HistoryUpdated, before, after, 0)
+
+	return nil
+}
+
+// update writes input to student record without recording it to history.
+// Returns student record before and after the update and an error.
+func (sr *StudentsRepo) update(ctx context.Context, id string, input domain.StudentRecord,
+	revision *int) (*domain.StudentRecord, *domain.StudentRecord, error) {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil, err
+	}
 
+	before, err := sr.GetById(ctx, id)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return domain.ErrNotFound
-		}
+		return nil, nil, err
+	}
+	if revision != nil && *revision != before.Revision {
+		return nil, nil, domain.ErrPreconditionFailed
+	}
+
+	input.ID = ""
+	input.ImportedFrom = nil
+	input.Revision = 0
+	input.DeletedAt = nil
+	input.DeletedBy = ""
+	input.Normalize()
+	if err := sr.crypt.encrypt(&input); err != nil {
+		return nil, nil, err
+	}
+
+	res, err := sr.col.UpdateOne(ctx, revisionFilter(objectId, before.Revision), bson.M{
+		"$set": input,
+		"$inc": bson.M{"revision": 1},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.MatchedCount == 0 {
+		return nil, nil, domain.ErrPreconditionFailed
+	}
+
+	after, err := sr.GetById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return before, after, nil
+}
+
+// Replace replaces student record with input as a whole, so empty fields are removed,
+// and records changed fields to the student history. Revision is checked the same way as by Update.
+func (sr *StudentsRepo) Replace(ctx context.Context, id string, input domain.StudentRecord, revision *int) error {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	before, err := sr.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if revision != nil && *revision != before.Revision {
+		return domain.ErrPreconditionFailed
+	}
 
+	input.ID = ""
+	input.Revision = before.Revision + 1
+	input.Normalize()
+
+	stored := input
+	if err := sr.crypt.encrypt(&stored); err != nil {
 		return err
 	}
 
+	res, err := sr.col.ReplaceOne(ctx, revisionFilter(objectId, before.Revision), stored)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrPreconditionFailed
+	}
+
+	input.ID = id
+
+	sr.record(ctx, domain.HistoryUpdated, before, &input, 0)
+
 	return nil
 }
 
+// Annotate changes tags, notes and annotations of student with fn and records the change to the student history.
+// fn gets a copy of the student, so it may change them in place. Other fields changed by fn are not stored,
+// and nothing is stored when fn changes nothing.
+// Returns annotated student record and an error, ErrPreconditionFailed when the student is modified concurrently.
+func (sr *StudentsRepo) Annotate(ctx context.Context, id string, fn func(student *domain.StudentRecord) error) (*domain.StudentRecord, error) {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := sr.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	after := *before
+	after.Tags = append([]domain.Tag(nil), before.Tags...)
+	after.Notes = append([]domain.Note(nil), before.Notes...)
+	after.Annotations = make(map[string]domain.Annotation, len(before.Annotations))
+	for key, annotation := range before.Annotations {
+		after.Annotations[key] = annotation
+	}
+	if err := fn(&after); err != nil {
+		return nil, err
+	}
+
+	changes, err := diffRecords(before, &after)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return before, nil
+	}
+
+	set, unset := bson.M{}, bson.M{}
+	if len(after.Tags) > 0 {
+		set["tags"] = after.Tags
+	} else {
+		after.Tags = nil
+		unset["tags"] = ""
+	}
+	if len(after.Notes) > 0 {
+		set["notes"] = after.Notes
+	} else {
+		after.Notes = nil
+		unset["notes"] = ""
+	}
+	if len(after.Annotations) > 0 {
+		set["annotations"] = after.Annotations
+	} else {
+		after.Annotations = nil
+		unset["annotations"] = ""
+	}
+
+	update := bson.M{"$inc": bson.M{"revision": 1}}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	res, err := sr.col.UpdateOne(ctx, revisionFilter(objectId, before.Revision), update)
+	if err != nil {
+		return nil, err
+	}
+	if res.MatchedCount == 0 {
+		return nil, domain.ErrPreconditionFailed
+	}
+	after.Revision++
+
+	sr.record(ctx, domain.HistoryUpdated, before, &after, 0)
+
+	return &after, nil
+}
+
+// ChangeStatus sets status of student to change.To, appends change to the student status changes
+// and records it to the student history. The student is expected to be read before, it is changed
+// only if it has the same revision, otherwise ErrPreconditionFailed is returned.
+func (sr *StudentsRepo) ChangeStatus(ctx context.Context, student *domain.StudentRecord, change domain.StatusChange) (*domain.StudentRecord, error) {
+	objectId, err := primitive.ObjectIDFromHex(student.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := sr.col.UpdateOne(ctx, revisionFilter(objectId, student.Revision), bson.M{
+		"$set":  bson.M{"status": change.To},
+		"$push": bson.M{"status_changes": change},
+		"$inc":  bson.M{"revision": 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.MatchedCount == 0 {
+		return nil, domain.ErrPreconditionFailed
+	}
+
+	after := *student
+	after.Status = change.To
+	after.StatusChanges = append(append([]domain.StatusChange(nil), student.StatusChanges...), change)
+	after.Revision++
+
+	sr.record(ctx, domain.HistoryUpdated, student, &after, 0)
+
+	return &after, nil
+}
+
+// Delete moves student record to trash and records it to the student history.
 func (sr *StudentsRepo) Delete(ctx context.Context, id string) error {
 	objectId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	res, err := sr.col.DeleteOne(ctx, bson.M{"_id": objectId})
-	if res != nil && res.DeletedCount == 0 {
+	before, err := sr.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	at := sr.now().UTC()
+	res, err := sr.col.UpdateOne(ctx, bson.M{"_id": objectId, "deleted_at": nil}, trashUpdate(ctx, at))
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
 		return domain.ErrNotFound
 	}
 
+	after := *before
+	after.DeletedAt = &at
+	after.DeletedBy = domain.ActorFromContext(ctx).Username
+	after.Revision++
+
+	sr.record(ctx, domain.HistoryDeleted, before, &after, 0)
+
+	return nil
+}
+
+// Merge moves sources to trash with a single update and then writes merged primary record,
+// sources are taken back from trash if primary record is not written.
+// Primary and sources are changed only if they have revisions they were read with.
+func (sr *StudentsRepo) Merge(ctx context.Context, primary domain.StudentRecord, sources []domain.StudentRecord) error {
+	// mongo keeps dates with milliseconds, trashed sources are found by deletion time to be taken back
+	at := sr.now().UTC().Truncate(time.Millisecond)
+
+	ids := bson.A{}
+	filters := bson.A{}
+	for _, source := range sources {
+		objectId, err := primitive.ObjectIDFromHex(source.ID)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, objectId)
+
+		filter := revisionFilter(objectId, source.Revision)
+		filter["deleted_at"] = nil
+		filters = append(filters, filter)
+	}
+
+	res, err := sr.col.UpdateMany(ctx, bson.M{"$or": filters}, trashUpdate(ctx, at))
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount != int64(len(sources)) {
+		return sr.undoMerge(ctx, ids, at, domain.ErrPreconditionFailed)
+	}
+
+	before, after, err := sr.update(ctx, primary.ID, primary, &primary.Revision)
+	if err != nil {
+		return sr.undoMerge(ctx, ids, at, err)
+	}
+
+	sr.record(ctx, domain.HistoryUpdated, before, after, 0)
+
+	for i := range sources {
+		deleted := sources[i]
+		deleted.DeletedAt = &at
+		deleted.DeletedBy = domain.ActorFromContext(ctx).Username
+		deleted.Revision++
+		sr.record(ctx, domain.HistoryDeleted, &sources[i], &deleted, 0)
+	}
+
+	return nil
+}
+
+// undoMerge takes sources moved to trash at the time by failed merge back from trash.
+// Returns err of the merge.
+func (sr *StudentsRepo) undoMerge(ctx context.Context, ids bson.A, at time.Time, err error) error {
+	filter := bson.M{"_id": bson.M{"$in": ids}, "deleted_at": at}
+	if _, undoErr := sr.col.UpdateMany(ctx, filter, untrashUpdate); undoErr != nil {
+		return fmt.Errorf("%w, sources are left in trash: %v", err, undoErr)
+	}
+
 	return err
 }
 
+// DeleteByFileName moves every student record imported from the file to trash with a single update
+// and records each of them to the student history.
 func (sr *StudentsRepo) DeleteByFileName(ctx context.Context, fileName string) error {
-	_, err := sr.col.DeleteOne(ctx, bson.M{"file_name": fileName})
+	// mongo keeps dates with milliseconds, trashed records are found by deletion time to be recorded
+	at := sr.now().UTC().Truncate(time.Millisecond)
+	fileFilter := bson.A{bson.M{"file_name": fileName}, bson.M{"imported_from": fileName}}
 
-	return err
+	res, err := sr.col.UpdateMany(ctx, bson.M{"$or": fileFilter, "deleted_at": nil}, trashUpdate(ctx, at))
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount == 0 {
+		return nil
+	}
+
+	cur, err := sr.col.Find(ctx, bson.M{
+		"$or":        fileFilter,
+		"deleted_at": at,
+		"deleted_by": domain.ActorFromContext(ctx).Username,
+	})
+	if err != nil {
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var after domain.StudentRecord
+		if err := cur.Decode(&after); err != nil {
+			return err
+		}
+		if err := sr.crypt.decrypt(&after); err != nil {
+			return err
+		}
+
+		before := after
+		before.DeletedAt = nil
+		before.DeletedBy = ""
+		before.Revision--
+		sr.record(ctx, domain.HistoryDeleted, &before, &after, 0)
+	}
+
+	return cur.Err()
+}
+
+// ListDeleted returns up to limit student records moved to trash, the most recently deleted first.
+func (sr *StudentsRepo) ListDeleted(ctx context.Context, limit int) ([]domain.StudentRecord, error) {
+	opts := getPaginationOpts(limit, 0).SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: 1}})
+
+	cur, err := sr.col.Find(ctx, bson.M{"deleted_at": bson.M{"$ne": nil}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	students := []domain.StudentRecord{}
+	if err := cur.All(ctx, &students); err != nil {
+		return nil, err
+	}
+
+	if err := sr.crypt.decryptAll(students); err != nil {
+		return nil, err
+	}
+
+	return students, nil
+}
+
+// Undelete restores student record from trash and records it to the student history.
+// Returns restored student record and an error.
+func (sr *StudentsRepo) Undelete(ctx context.Context, id string) (*domain.StudentRecord, error) {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := sr.findOne(ctx, bson.M{"_id": objectId, "deleted_at": bson.M{"$ne": nil}})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := sr.col.UpdateOne(ctx, revisionFilter(objectId, before.Revision), untrashUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if res.MatchedCount == 0 {
+		return nil, domain.ErrPreconditionFailed
+	}
+
+	after, err := sr.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sr.record(ctx, domain.HistoryUndeleted, before, after, 0)
+
+	return after, nil
+}
+
+// Purge deletes student records moved to trash before the time permanently, their history is kept.
+// Returns the number of deleted records and an error.
+func (sr *StudentsRepo) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := sr.col.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
 }
@@ -2,17 +2,26 @@ package mongodb
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
 	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"reflect"
-	"testing"
 )
 
 type key string
 
 var k = key("withErr")
 
+var importedAt = time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+
 type collectionMock struct {
 	data interface{}
 }
@@ -46,6 +55,87 @@ func (m *collectionMock) DeleteOne(ctx context.Context, filter interface{},
 	panic("implement me")
 }
 
+func (m *collectionMock) CountDocuments(ctx context.Context, filter interface{},
+	opts ...*options.CountOptions) (int64, error) {
+	panic("implement me")
+}
+
+func (m *collectionMock) ReplaceOne(ctx context.Context, filter interface{}, replacement interface{},
+	opts ...*options.ReplaceOptions) (*mongo.UpdateResult, error) {
+	panic("implement me")
+}
+
+func (m *collectionMock) DeleteMany(ctx context.Context, filter interface{},
+	opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	panic("implement me")
+}
+
+func (m *collectionMock) BulkWrite(ctx context.Context, models []mongo.WriteModel,
+	opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+	panic("implement me")
+}
+
+func (m *collectionMock) UpdateMany(ctx context.Context, filter interface{}, update interface{},
+	opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	panic("implement me")
+}
+
+func (m *collectionMock) Aggregate(ctx context.Context, pipeline interface{},
+	opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	panic("implement me")
+}
+
+// historyMock keeps history entries with versions unique per student, inserts fail with err when it is set.
+type historyMock struct {
+	entries []domain.HistoryEntry
+	err     error
+}
+
+func (m *historyMock) InsertOne(ctx context.Context, document interface{},
+	_ ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	entry := document.(domain.HistoryEntry)
+	for _, e := range m.entries {
+		if e.StudentID == entry.StudentID && e.Version == entry.Version {
+			return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "duplicate key"}}}
+		}
+	}
+	m.entries = append(m.entries, entry)
+	return &mongo.InsertOneResult{}, nil
+}
+
+// FindOne returns the latest entry, filter and options are expected to select it.
+func (m *historyMock) FindOne(ctx context.Context, filter interface{},
+	opts ...*options.FindOneOptions) *mongo.SingleResult {
+	var latest *domain.HistoryEntry
+	for i := range m.entries {
+		if latest == nil || m.entries[i].Version > latest.Version {
+			latest = &m.entries[i]
+		}
+	}
+	if latest == nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil)
+	}
+	return mongo.NewSingleResultFromDocument(latest, nil, nil)
+}
+
+func (m *historyMock) Find(ctx context.Context, filter interface{},
+	opts ...*options.FindOptions) (cur *mongo.Cursor, err error) {
+	panic("implement me")
+}
+
+func (m *historyMock) DeleteMany(ctx context.Context, filter interface{},
+	opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	panic("implement me")
+}
+
+func (m *historyMock) ReplaceOne(ctx context.Context, filter interface{}, replacement interface{},
+	opts ...*options.ReplaceOptions) (*mongo.UpdateResult, error) {
+	panic("implement me")
+}
+
 func TestStudentsRepo_SaveRSS(t *testing.T) {
 	type args struct {
 		ctx     context.Context
@@ -65,7 +155,7 @@ func TestStudentsRepo_SaveRSS(t *testing.T) {
 				context.WithValue(context.Background(), k, false),
 				domain.StudentRSS{},
 			},
-			domain.StudentRecord{Source: domain.RSS},
+			domain.StudentRecord{Source: domain.RSS, ImportedAt: importedAt, Revision: 1},
 			false,
 		}, {
 			"with error",
@@ -80,19 +170,28 @@ func TestStudentsRepo_SaveRSS(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := collectionMock{}
-			repo := newRepo(&mock)
+			history := historyMock{}
+			repo := newRepo(&mock, &history)
+			repo.now = func() time.Time { return importedAt }
 
 			_, err := repo.SaveRSS(tt.args.ctx, "", "", tt.args.student)
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("SaveRss() error expected but got %v", err)
 				}
+				if len(history.entries) != 0 {
+					t.Errorf("SaveRSS() history = %v, want no entries", history.entries)
+				}
 				return
 			}
 
 			if !reflect.DeepEqual(mock.data, tt.want) {
 				t.Errorf("SaveRSS() got = %v, want %v", mock.data, tt.want)
 			}
+
+			if len(history.entries) != 1 || history.entries[0].Action != domain.HistoryCreated || history.entries[0].Version != 1 {
+				t.Errorf("SaveRSS() history = %v, want a single created entry", history.entries)
+			}
 		})
 	}
 }
@@ -115,7 +214,7 @@ func TestStudentsRepo_SaveWAC(t *testing.T) {
 				context.WithValue(context.Background(), k, false),
 				domain.StudentWAC{},
 			},
-			domain.StudentRecord{Source: domain.WAC},
+			domain.StudentRecord{Source: domain.WAC, ImportedAt: importedAt, Revision: 1},
 			false,
 		}, {
 			"with error",
@@ -130,7 +229,8 @@ func TestStudentsRepo_SaveWAC(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := collectionMock{}
-			repo := newRepo(&mock)
+			repo := newRepo(&mock, &historyMock{})
+			repo.now = func() time.Time { return importedAt }
 
 			_, err := repo.SaveWAC(tt.args.ctx, "", "", tt.args.student)
 			if tt.wantErr {
@@ -146,3 +246,152 @@ func TestStudentsRepo_SaveWAC(t *testing.T) {
 		})
 	}
 }
+
+func TestStudentsRepo_SaveReimport(t *testing.T) {
+	mt := getMockTest(t)
+	defer mt.Close()
+
+	id := primitive.NewObjectID()
+	ns := fmt.Sprintf("%s.%s", testDbName, studentsCollection)
+	existing := func(revision int) bson.D {
+		return bson.D{
+			{Key: "_id", Value: id},
+			{Key: "source", Value: domain.RSS},
+			{Key: "email", Value: "obi@jedi.rules"},
+			{Key: "file_name", Value: "rss-1.xlsx"},
+			{Key: "revision", Value: revision},
+		}
+	}
+
+	mt.Run("retried on concurrent change", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, existing(1)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, existing(2)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		repo, err := NewStudentsRepo(mt.DB, config.EncryptionConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := repo.SaveRSS(context.Background(), "rss-2.xlsx", "obi@jedi.rules", domain.StudentRSS{FirstName: "Obi-Wan"})
+		if err != nil {
+			t.Fatalf("SaveRSS() error = %v", err)
+		}
+		if got != id.Hex() {
+			t.Errorf("SaveRSS() = %v, want existing record %v", got, id.Hex())
+		}
+
+		var updates []bson.Raw
+		for _, e := range mt.GetAllStartedEvents() {
+			if e.CommandName == "update" {
+				updates = append(updates, e.Command.Lookup("updates").Array().Index(0).Value().Document())
+			}
+		}
+		if len(updates) != 2 {
+			t.Fatalf("SaveRSS() sent %d updates, want 2", len(updates))
+		}
+		if revision := updates[1].Lookup("q", "revision").Int32(); revision != 2 {
+			t.Errorf("SaveRSS() retried with revision %d, want 2", revision)
+		}
+		files := updates[1].Lookup("u", "$set", "imported_from").Array()
+		if first, last := files.Index(0).Value().StringValue(), files.Index(1).Value().StringValue(); first != "rss-1.xlsx" || last != "rss-2.xlsx" {
+			t.Errorf("SaveRSS() imported_from = %v, want both files", files)
+		}
+	})
+
+	mt.Run("same file row inserted", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+
+		repo, err := NewStudentsRepo(mt.DB, config.EncryptionConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := repo.SaveRSS(context.Background(), "rss-1.xlsx", "obi@jedi.rules", domain.StudentRSS{}); err != nil {
+			t.Fatalf("SaveRSS() error = %v", err)
+		}
+
+		filter := mt.GetStartedEvent().Command.Lookup("filter")
+		if file := filter.Document().Lookup("imported_from", "$ne").StringValue(); file != "rss-1.xlsx" {
+			t.Errorf("SaveRSS() looked up records imported from %q, want records of other files", file)
+		}
+		if insert := mt.GetStartedEvent(); insert == nil || insert.CommandName != "insert" {
+			t.Errorf("SaveRSS() did not insert the row as a new record")
+		}
+	})
+}
+
+func TestStudentsRepo_DeleteByFileName(t *testing.T) {
+	mt := getMockTest(t)
+	defer mt.Close()
+
+	ns := fmt.Sprintf("%s.%s", testDbName, studentsCollection)
+	deletedAt := importedAt.Add(time.Hour)
+	trashed := func(email string) bson.D {
+		return bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "source", Value: domain.RSS},
+			{Key: "email", Value: email},
+			{Key: "file_name", Value: "rss.xlsx"},
+			{Key: "revision", Value: 2},
+			{Key: "deleted_at", Value: deletedAt},
+			{Key: "deleted_by", Value: "yoda"},
+		}
+	}
+
+	mt.Run("every record trashed", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, trashed("obi@jedi.rules"), trashed("luke@jedi.rules")),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+
+		repo, err := NewStudentsRepo(mt.DB, config.EncryptionConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo.now = func() time.Time { return deletedAt }
+
+		ctx := domain.ContextWithActor(context.Background(), domain.Actor{Username: "yoda"})
+		if err := repo.DeleteByFileName(ctx, "rss.xlsx"); err != nil {
+			t.Fatalf("DeleteByFileName() error = %v", err)
+		}
+
+		var updates, inserts int
+		for _, e := range mt.GetAllStartedEvents() {
+			switch e.CommandName {
+			case "update":
+				updates++
+				if multi, _ := e.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("multi").BooleanOK(); !multi {
+					t.Error("DeleteByFileName() updated a single record, want every record of the file")
+				}
+			case "insert":
+				inserts++
+			}
+		}
+		if updates != 1 || inserts != 2 {
+			t.Errorf("DeleteByFileName() sent %d updates and %d history inserts, want 1 and 2", updates, inserts)
+		}
+	})
+
+	mt.Run("no records", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}))
+
+		repo, err := NewStudentsRepo(mt.DB, config.EncryptionConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.DeleteByFileName(context.Background(), "rss.xlsx"); err != nil {
+			t.Errorf("DeleteByFileName() error = %v", err)
+		}
+	})
+}
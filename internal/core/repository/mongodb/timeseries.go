@@ -0,0 +1,109 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// metricDateFields are timestamps counted by time series metrics.
+var metricDateFields = map[string]string{
+	domain.MetricApplications: "student_rss.application_time",
+	domain.MetricJoins:        "student_wac.join_time",
+	domain.MetricCompletions:  "student_rss.projects.finished_time",
+}
+
+// timeSeriesGroupFields are fields of time series group by dimensions.
+var timeSeriesGroupFields = map[string]string{
+	"source":              "source",
+	"status":              "status",
+	"location":            locationField,
+	"membership_type":     "student_wac.membership_type",
+	domain.GroupByProject: "student_rss.projects.name",
+}
+
+type timePointResult struct {
+	ID struct {
+		Start time.Time `bson:"start"`
+		Group string    `bson:"group"`
+	} `bson:"_id"`
+	Count int64 `bson:"count"`
+}
+
+// TimeSeries counts metric timestamps truncated to interval buckets per group.
+// Options are expected to be validated, grouping by encrypted field returns ErrInvalidParams.
+func (sr *StatsRepo) TimeSeries(ctx context.Context, options domain.TimeSeriesOptions) (*domain.TimeSeries, error) {
+	if options.GroupBy != "" && sr.encryption.Encrypted(timeSeriesGroupFields[options.GroupBy]) {
+		return nil, fmt.Errorf("%w: group_by %s is encrypted", domain.ErrInvalidParams, options.GroupBy)
+	}
+
+	field := metricDateFields[options.Metric]
+
+	dateRange := bson.M{"$exists": true}
+	if !options.From.IsZero() {
+		dateRange["$gte"] = options.From
+	}
+	if !options.To.IsZero() {
+		dateRange["$lt"] = options.To
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": studentsFilter(domain.ListStudentsOptions{
+			Source: options.Source,
+			Filter: options.Filter,
+		})},
+		bson.M{"$match": bson.M{field: dateRange}},
+	}
+	if options.Metric == domain.MetricCompletions {
+		pipeline = append(pipeline,
+			bson.M{"$unwind": "$student_rss.projects"},
+			bson.M{"$match": bson.M{field: dateRange}},
+		)
+	}
+
+	id := bson.M{"start": bson.M{"$dateTrunc": bson.M{
+		"date":        "$" + field,
+		"unit":        options.Interval,
+		"startOfWeek": "monday",
+	}}}
+	if options.GroupBy != "" {
+		id["group"] = "$" + timeSeriesGroupFields[options.GroupBy]
+	}
+
+	pipeline = append(pipeline,
+		bson.M{"$group": bson.M{"_id": id, "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "_id.group", Value: 1}, {Key: "_id.start", Value: 1}}},
+	)
+
+	cur, err := sr.students.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []timePointResult
+	if err := cur.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	ts := &domain.TimeSeries{
+		Metric:   options.Metric,
+		Interval: options.Interval,
+		GroupBy:  options.GroupBy,
+		Series:   []domain.Series{},
+	}
+	for i, r := range results {
+		if i == 0 || r.ID.Group != results[i-1].ID.Group {
+			ts.Series = append(ts.Series, domain.Series{Group: r.ID.Group})
+		}
+		series := &ts.Series[len(ts.Series)-1]
+		series.Points = append(series.Points, domain.TimePoint{
+			Start: r.ID.Start.UTC(),
+			Count: r.Count,
+		})
+	}
+
+	return ts, nil
+}
@@ -8,4 +8,6 @@ type Repositories struct {
 	Users    ports.UsersStore
 	Schemas  ports.SchemaStore
 	Students ports.StudentsStore
+	Stats    ports.StatsStore
+	Erasures ports.ErasuresStore
 }
@@ -14,13 +14,15 @@ type AggregatorService struct {
 	studentsRepo ports.StudentsStore
 	schemasRepo  ports.SchemaStore
 	storage      ports.StorageService
+	workflow     ports.WorkflowService
 }
 
-func NewAggregatorService(studentsRepo ports.StudentsStore, schemasRepo ports.SchemaStore, storage ports.StorageService) *AggregatorService {
+func NewAggregatorService(studentsRepo ports.StudentsStore, schemasRepo ports.SchemaStore, storage ports.StorageService, workflow ports.WorkflowService) *AggregatorService {
 	return &AggregatorService{
 		studentsRepo: studentsRepo,
 		schemasRepo:  schemasRepo,
 		storage:      storage,
+		workflow:     workflow,
 	}
 }
 
@@ -47,6 +49,11 @@ func (aggS *AggregatorService) ParseFile(ctx context.Context, fileName string, s
 		return err
 	}
 
+	actor := domain.ActorFromContext(ctx)
+	actor.Via = domain.ViaImport
+	actor.FileName = fileName
+	ctx = domain.ContextWithActor(ctx, actor)
+
 	students := new([]domain.StudentRecord)
 	err = parser.ParseXLSXFile(students, r, schema.ConvertToParserSchema())
 	if err != nil {
@@ -75,5 +82,7 @@ func (aggS *AggregatorService) ParseFile(ctx context.Context, fileName string, s
 		}
 	}
 
-	return nil
+	_, err = aggS.workflow.ApplyImport(ctx, fileName)
+
+	return err
 }
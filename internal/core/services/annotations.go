@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+)
+
+var _ ports.AnnotationsService = (*AnnotationsService)(nil)
+
+// AnnotationsService manages staff tags, notes and annotations of students. Changes are authored by ctx actor.
+type AnnotationsService struct {
+	repo ports.StudentsStore
+	cfg  *config.Config
+	now  func() time.Time
+}
+
+func NewAnnotationsService(repo ports.StudentsStore, cfg *config.Config) *AnnotationsService {
+	return &AnnotationsService{
+		repo: repo,
+		cfg:  cfg,
+		now:  time.Now,
+	}
+}
+
+// AddTag tags student, adding a tag student already has changes nothing.
+func (as *AnnotationsService) AddTag(ctx context.Context, id string, input domain.TagInput) (*domain.StudentRecord, error) {
+	name := domain.NormalizeTag(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: tag name should not be empty", domain.ErrInvalidParams)
+	}
+
+	return as.repo.Annotate(ctx, id, func(student *domain.StudentRecord) error {
+		if !student.HasTag(name) {
+			student.Tags = append(student.Tags, domain.Tag{
+				Name:    name,
+				AddedBy: domain.ActorFromContext(ctx).Username,
+				AddedAt: as.now().UTC(),
+			})
+		}
+		return nil
+	})
+}
+
+func (as *AnnotationsService) RemoveTag(ctx context.Context, id string, name string) (*domain.StudentRecord, error) {
+	name = domain.NormalizeTag(name)
+
+	return as.repo.Annotate(ctx, id, func(student *domain.StudentRecord) error {
+		for i, tag := range student.Tags {
+			if tag.Name == name {
+				student.Tags = append(student.Tags[:i], student.Tags[i+1:]...)
+				return nil
+			}
+		}
+		return domain.ErrNotFound
+	})
+}
+
+func (as *AnnotationsService) AddNote(ctx context.Context, id string, input domain.NoteInput) (*domain.StudentRecord, error) {
+	noteID, err := newNoteID()
+	if err != nil {
+		return nil, err
+	}
+
+	return as.repo.Annotate(ctx, id, func(student *domain.StudentRecord) error {
+		student.Notes = append(student.Notes, domain.Note{
+			ID:        noteID,
+			Text:      input.Text,
+			Author:    domain.ActorFromContext(ctx).Username,
+			CreatedAt: as.now().UTC(),
+		})
+		return nil
+	})
+}
+
+// UpdateNote replaces note text keeping its author.
+func (as *AnnotationsService) UpdateNote(ctx context.Context, id string, noteID string, input domain.NoteInput) (*domain.StudentRecord, error) {
+	return as.repo.Annotate(ctx, id, func(student *domain.StudentRecord) error {
+		for i := range student.Notes {
+			note := &student.Notes[i]
+			if note.ID != noteID {
+				continue
+			}
+			if note.Text != input.Text {
+				updatedAt := as.now().UTC()
+				note.Text = input.Text
+				note.UpdatedAt = &updatedAt
+			}
+			return nil
+		}
+		return domain.ErrNotFound
+	})
+}
+
+func (as *AnnotationsService) DeleteNote(ctx context.Context, id string, noteID string) (*domain.StudentRecord, error) {
+	return as.repo.Annotate(ctx, id, func(student *domain.StudentRecord) error {
+		for i, note := range student.Notes {
+			if note.ID == noteID {
+				student.Notes = append(student.Notes[:i], student.Notes[i+1:]...)
+				return nil
+			}
+		}
+		return domain.ErrNotFound
+	})
+}
+
+// SetAnnotation sets annotation value by key, setting the same value changes nothing.
+func (as *AnnotationsService) SetAnnotation(ctx context.Context, id string, key string, input domain.AnnotationInput) (*domain.StudentRecord, error) {
+	if err := domain.ValidateAnnotationKey(key); err != nil {
+		return nil, err
+	}
+
+	return as.repo.Annotate(ctx, id, func(student *domain.StudentRecord) error {
+		if current, ok := student.Annotations[key]; !ok || current.Value != input.Value {
+			student.Annotations[key] = domain.Annotation{
+				Value: input.Value,
+				SetBy: domain.ActorFromContext(ctx).Username,
+				SetAt: as.now().UTC(),
+			}
+		}
+		return nil
+	})
+}
+
+func (as *AnnotationsService) DeleteAnnotation(ctx context.Context, id string, key string) (*domain.StudentRecord, error) {
+	return as.repo.Annotate(ctx, id, func(student *domain.StudentRecord) error {
+		if _, ok := student.Annotations[key]; !ok {
+			return domain.ErrNotFound
+		}
+		delete(student.Annotations, key)
+		return nil
+	})
+}
+
+// newNoteID returns a random id unique among student notes.
+func newNoteID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+)
+
+// annotatedStore applies Annotate changes to a copy of student.
+type annotatedStore struct {
+	ports.StudentsStore
+	student domain.StudentRecord
+}
+
+func (as *annotatedStore) Annotate(_ context.Context, _ string, fn func(student *domain.StudentRecord) error) (*domain.StudentRecord, error) {
+	student := as.student
+	student.Tags = append([]domain.Tag(nil), as.student.Tags...)
+	student.Notes = append([]domain.Note(nil), as.student.Notes...)
+	student.Annotations = map[string]domain.Annotation{}
+	for key, annotation := range as.student.Annotations {
+		student.Annotations[key] = annotation
+	}
+
+	if err := fn(&student); err != nil {
+		return nil, err
+	}
+
+	return &student, nil
+}
+
+func TestAnnotationsService(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(-time.Hour)
+	ctx := domain.ContextWithActor(context.Background(), domain.Actor{Username: "yoda"})
+
+	student := domain.StudentRecord{
+		Tags:        []domain.Tag{{Name: "scholarship", AddedBy: "mace", AddedAt: earlier}},
+		Notes:       []domain.Note{{ID: "n1", Text: "strong candidate", Author: "mace", CreatedAt: earlier}},
+		Annotations: map[string]domain.Annotation{"interview_score": {Value: "8", SetBy: "mace", SetAt: earlier}},
+	}
+
+	tests := []struct {
+		name    string
+		apply   func(as *AnnotationsService) (*domain.StudentRecord, error)
+		check   func(s *domain.StudentRecord) interface{}
+		want    interface{}
+		wantErr error
+	}{
+		{
+			"add tag",
+			func(as *AnnotationsService) (*domain.StudentRecord, error) {
+				return as.AddTag(ctx, "1", domain.TagInput{Name: " top  talent "})
+			},
+			func(s *domain.StudentRecord) interface{} { return s.Tags },
+			[]domain.Tag{
+				{Name: "scholarship", AddedBy: "mace", AddedAt: earlier},
+				{Name: "top talent", AddedBy: "yoda", AddedAt: now},
+			},
+			nil,
+		},
+		{
+			"add existing tag keeps it",
+			func(as *AnnotationsService) (*domain.StudentRecord, error) {
+				return as.AddTag(ctx, "1", domain.TagInput{Name: "scholarship"})
+			},
+			func(s *domain.StudentRecord) interface{} { return s.Tags },
+			[]domain.Tag{{Name: "scholarship", AddedBy: "mace", AddedAt: earlier}},
+			nil,
+		},
+		{
+			"remove unknown tag",
+			func(as *AnnotationsService) (*domain.StudentRecord, error) {
+				return as.RemoveTag(ctx, "1", "alumni")
+			},
+			nil, nil, domain.ErrNotFound,
+		},
+		{
+			"update note keeps author",
+			func(as *AnnotationsService) (*domain.StudentRecord, error) {
+				return as.UpdateNote(ctx, "1", "n1", domain.NoteInput{Text: "hired"})
+			},
+			func(s *domain.StudentRecord) interface{} { return s.Notes },
+			[]domain.Note{{ID: "n1", Text: "hired", Author: "mace", CreatedAt: earlier, UpdatedAt: &now}},
+			nil,
+		},
+		{
+			"delete unknown note",
+			func(as *AnnotationsService) (*domain.StudentRecord, error) {
+				return as.DeleteNote(ctx, "1", "n2")
+			},
+			nil, nil, domain.ErrNotFound,
+		},
+		{
+			"set annotation",
+			func(as *AnnotationsService) (*domain.StudentRecord, error) {
+				return as.SetAnnotation(ctx, "1", "interview_score", domain.AnnotationInput{Value: "9"})
+			},
+			func(s *domain.StudentRecord) interface{} { return s.Annotations },
+			map[string]domain.Annotation{"interview_score": {Value: "9", SetBy: "yoda", SetAt: now}},
+			nil,
+		},
+		{
+			"invalid annotation key",
+			func(as *AnnotationsService) (*domain.StudentRecord, error) {
+				return as.SetAnnotation(ctx, "1", "interview.score", domain.AnnotationInput{Value: "9"})
+			},
+			nil, nil, domain.ErrInvalidParams,
+		},
+		{
+			"delete annotation",
+			func(as *AnnotationsService) (*domain.StudentRecord, error) {
+				return as.DeleteAnnotation(ctx, "1", "interview_score")
+			},
+			func(s *domain.StudentRecord) interface{} { return s.Annotations },
+			map[string]domain.Annotation{},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			as := NewAnnotationsService(&annotatedStore{student: student}, nil)
+			as.now = func() time.Time { return now }
+
+			got, err := tt.apply(as)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := tt.check(got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"github.com/abdukhashimov/student_aggregator/pkg/similarity"
+)
+
+const (
+	defaultDuplicatesThreshold = 0.75
+
+	nameWeight       = 0.5
+	emailWeight      = 0.3
+	attributesWeight = 0.2
+)
+
+var _ ports.DuplicatesService = (*DuplicatesService)(nil)
+
+type DuplicatesService struct {
+	repo ports.StudentsStore
+	cfg  *config.Config
+}
+
+func NewDuplicatesService(repo ports.StudentsStore, cfg *config.Config) *DuplicatesService {
+	return &DuplicatesService{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// FindDuplicates scores pairs of students which probably are the same person registered with different emails.
+// Only students sharing a name or email prefix are compared, they are grouped by the store.
+// Records of different sources with the same email belong to one person and are not reported.
+func (ds *DuplicatesService) FindDuplicates(ctx context.Context, options domain.FindDuplicatesOptions) ([]domain.DuplicateCandidate, error) {
+	blocks, err := ds.repo.DuplicateBlocks(ctx, options.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := options.Threshold
+	if threshold <= 0 {
+		threshold = defaultDuplicatesThreshold
+	}
+
+	candidates := []domain.DuplicateCandidate{}
+	for _, pair := range candidatePairs(blocks) {
+		first, second := pair[0], pair[1]
+		score, reasons := duplicateScore(first, second)
+		if score < threshold {
+			continue
+		}
+
+		candidates = append(candidates, domain.DuplicateCandidate{
+			First:   *first,
+			Second:  *second,
+			Score:   score,
+			Reasons: reasons,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	if options.Limit > 0 && len(candidates) > options.Limit {
+		candidates = candidates[:options.Limit]
+	}
+
+	return candidates, nil
+}
+
+// MergeStudents merges duplicate records into the primary one and moves them to trash.
+// Merged records are listed in the primary record provenance.
+func (ds *DuplicatesService) MergeStudents(ctx context.Context, input domain.MergeStudentsInput) (*domain.StudentRecord, error) {
+	primary, err := ds.repo.GetById(ctx, input.PrimaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var duplicates []domain.StudentRecord
+	seen := make(map[string]bool, len(input.DuplicateIDs))
+	for _, id := range input.DuplicateIDs {
+		if id == primary.ID {
+			return nil, domain.ErrSelfMerge
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		duplicate, err := ds.repo.GetById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		duplicates = append(duplicates, *duplicate)
+	}
+
+	mergedAt := time.Now().UTC()
+	for _, duplicate := range duplicates {
+		primary.Merge(duplicate, mergedAt)
+	}
+
+	if err := ds.repo.Merge(ctx, *primary, duplicates); err != nil {
+		return nil, err
+	}
+
+	return ds.repo.GetById(ctx, primary.ID)
+}
+
+// candidatePairs returns pairs of students of the same block ordered by their ids,
+// students sharing several blocks are paired once.
+func candidatePairs(blocks [][]domain.StudentRecord) [][2]*domain.StudentRecord {
+	seen := make(map[[2]string]bool)
+	var pairs [][2]*domain.StudentRecord
+	for _, block := range blocks {
+		for i := 0; i < len(block); i++ {
+			for j := i + 1; j < len(block); j++ {
+				first, second := &block[i], &block[j]
+				if second.ID < first.ID {
+					first, second = second, first
+				}
+
+				key := [2]string{first.ID, second.ID}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				pairs = append(pairs, [2]*domain.StudentRecord{first, second})
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0].ID == pairs[j][0].ID {
+			return pairs[i][1].ID < pairs[j][1].ID
+		}
+		return pairs[i][0].ID < pairs[j][0].ID
+	})
+
+	return pairs
+}
+
+// duplicateScore estimates probability of students a and b being the same person
+// by name similarity, email local part similarity and shared attributes.
+// Returns score in range [0, 1] and human readable reasons.
+func duplicateScore(a, b *domain.StudentRecord) (float64, []string) {
+	emailA, emailB := domain.NormalizeEmail(a.Email), domain.NormalizeEmail(b.Email)
+	if emailA != "" && emailA == emailB {
+		if a.Source != b.Source {
+			return 0, nil
+		}
+		return 1, []string{"identical email"}
+	}
+
+	var score, weights float64
+	var reasons []string
+
+	if nameA, nameB := a.DisplayName(), b.DisplayName(); nameA != "" && nameB != "" {
+		ratio := similarity.TokenSortRatio(nameA, nameB)
+		score += nameWeight * ratio
+		weights += nameWeight
+		reasons = append(reasons, fmt.Sprintf("name similarity %.2f", ratio))
+	}
+
+	if localA, localB := emailLocalPart(emailA), emailLocalPart(emailB); localA != "" && localB != "" {
+		ratio := similarity.Ratio(localA, localB)
+		score += emailWeight * ratio
+		weights += emailWeight
+		reasons = append(reasons, fmt.Sprintf("email similarity %.2f", ratio))
+	}
+
+	if shared, compared := sharedAttributes(a, b); len(compared) > 0 {
+		score += attributesWeight * float64(len(shared)) / float64(len(compared))
+		weights += attributesWeight
+		if len(shared) > 0 {
+			reasons = append(reasons, "shared "+strings.Join(shared, ", "))
+		}
+	}
+
+	if weights == 0 {
+		return 0, nil
+	}
+
+	return score / weights, reasons
+}
+
+// sharedAttributes compares attributes filled in both students a and b.
+// Returns names of equal attributes and names of compared attributes.
+func sharedAttributes(a, b *domain.StudentRecord) ([]string, []string) {
+	attributes := []struct {
+		name   string
+		first  string
+		second string
+	}{
+		{"location", a.Location, b.Location},
+		{"company", a.Company, b.Company},
+		{"position", a.Position, b.Position},
+		{"application date", a.ApplicationDate, b.ApplicationDate},
+		{"join date", a.JoinDate, b.JoinDate},
+	}
+
+	var shared, compared []string
+	for _, attr := range attributes {
+		first, second := strings.TrimSpace(attr.first), strings.TrimSpace(attr.second)
+		if first == "" || second == "" {
+			continue
+		}
+		compared = append(compared, attr.name)
+		if strings.EqualFold(first, second) {
+			shared = append(shared, attr.name)
+		}
+	}
+
+	return shared, compared
+}
+
+// emailLocalPart returns email part before @ without dots and +tags.
+func emailLocalPart(email string) string {
+	local, _, _ := strings.Cut(domain.NormalizeEmail(email), "@")
+	local, _, _ = strings.Cut(local, "+")
+
+	return strings.ReplaceAll(local, ".", "")
+}
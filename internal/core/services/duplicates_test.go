@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+)
+
+var duplicatesStudents = []domain.StudentRecord{
+	{
+		Source:     domain.RSS,
+		Email:      "anakin.skywalker@gmail.com",
+		StudentRSS: domain.StudentRSS{FirstName: "Anakin", LastName: "Skywalker"},
+	},
+	{
+		Source:     domain.RSS,
+		Email:      "a.skywalker@jedi-academy.edu",
+		StudentRSS: domain.StudentRSS{FirstName: "Anakin", LastName: "Skywalkr"},
+	},
+	{
+		Source:     domain.WAC,
+		Email:      "Anakin.Skywalker@gmail.com",
+		StudentWAC: domain.StudentWAC{FullName: "Anakin Skywalker"},
+	},
+	{
+		Source:     domain.RSS,
+		Email:      "obi@jedi.rules",
+		StudentRSS: domain.StudentRSS{FirstName: "Obi-Wan", LastName: "Kenobi"},
+	},
+}
+
+func TestDuplicateScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    int
+		minimal float64
+		maximal float64
+	}{
+		{"similar names", 0, 1, defaultDuplicatesThreshold, 1},
+		{"same person in different sources", 0, 2, 0, 0},
+		{"different people", 0, 3, 0, defaultDuplicatesThreshold},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, _ := duplicateScore(&duplicatesStudents[tt.a], &duplicatesStudents[tt.b])
+			if score < tt.minimal || score > tt.maximal {
+				t.Errorf("duplicateScore() = %v, want from %v to %v", score, tt.minimal, tt.maximal)
+			}
+		})
+	}
+}
+
+func TestCandidatePairs(t *testing.T) {
+	anakin, skywalker, kenobi := duplicatesStudents[0], duplicatesStudents[1], duplicatesStudents[3]
+	anakin.ID, skywalker.ID, kenobi.ID = "1", "2", "3"
+
+	blocks := [][]domain.StudentRecord{
+		{skywalker, anakin},
+		{anakin, skywalker, kenobi},
+	}
+
+	want := [][2]string{{"1", "2"}, {"1", "3"}, {"2", "3"}}
+	var got [][2]string
+	for _, pair := range candidatePairs(blocks) {
+		got = append(got, [2]string{pair[0].ID, pair[1].ID})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("candidatePairs() = %v, want %v", got, want)
+	}
+}
+
+type mergedStore struct {
+	ports.StudentsStore
+	students map[string]domain.StudentRecord
+	err      error
+	merged   *domain.StudentRecord
+	sources  []string
+}
+
+func (ms *mergedStore) GetById(_ context.Context, id string) (*domain.StudentRecord, error) {
+	if ms.merged != nil && ms.merged.ID == id {
+		return ms.merged, nil
+	}
+	student, ok := ms.students[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	return &student, nil
+}
+
+func (ms *mergedStore) Merge(_ context.Context, primary domain.StudentRecord, sources []domain.StudentRecord) error {
+	if ms.err != nil {
+		return ms.err
+	}
+	ms.merged = &primary
+	for _, source := range sources {
+		ms.sources = append(ms.sources, source.ID)
+	}
+
+	return nil
+}
+
+func TestMergeStudents(t *testing.T) {
+	students := map[string]domain.StudentRecord{
+		"1": {ID: "1", Source: domain.RSS, Email: "anakin.skywalker@gmail.com"},
+		"2": {ID: "2", Source: domain.RSS, Email: "a.skywalker@jedi-academy.edu"},
+		"3": {ID: "3", Source: domain.WAC, Email: "Anakin.Skywalker@gmail.com"},
+	}
+
+	tests := []struct {
+		name        string
+		input       domain.MergeStudentsInput
+		err         error
+		wantSources []string
+		wantErr     error
+	}{
+		{
+			name:        "repeated duplicates",
+			input:       domain.MergeStudentsInput{PrimaryID: "1", DuplicateIDs: []string{"2", "3", "2"}},
+			wantSources: []string{"2", "3"},
+		},
+		{
+			name:    "self merge",
+			input:   domain.MergeStudentsInput{PrimaryID: "1", DuplicateIDs: []string{"2", "1"}},
+			wantErr: domain.ErrSelfMerge,
+		},
+		{
+			name:    "unknown duplicate",
+			input:   domain.MergeStudentsInput{PrimaryID: "1", DuplicateIDs: []string{"4"}},
+			wantErr: domain.ErrNotFound,
+		},
+		{
+			name:    "concurrent change",
+			input:   domain.MergeStudentsInput{PrimaryID: "1", DuplicateIDs: []string{"2"}},
+			err:     domain.ErrPreconditionFailed,
+			wantErr: domain.ErrPreconditionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mergedStore{students: students, err: tt.err}
+			ds := NewDuplicatesService(store, nil)
+
+			got, err := ds.MergeStudents(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MergeStudents() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(store.sources, tt.wantSources) {
+				t.Errorf("MergeStudents() sources = %v, want %v", store.sources, tt.wantSources)
+			}
+			if len(got.MergedFrom) != len(tt.wantSources) {
+				t.Errorf("MergeStudents() merged from %v records, want %v", len(got.MergedFrom), len(tt.wantSources))
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/pkg/export"
+	"github.com/abdukhashimov/student_aggregator/pkg/parser"
+)
+
+const projectsColumn = "projects"
+
+type studentColumn struct {
+	name  string
+	value func(s *domain.StudentRecord) interface{}
+}
+
+// studentColumns are exported student columns in default order.
+// Projects are exported as repeated column groups and are not listed here.
+var studentColumns = []studentColumn{
+	{"id", func(s *domain.StudentRecord) interface{} { return s.ID }},
+	{"source", func(s *domain.StudentRecord) interface{} { return s.Source }},
+	{"email", func(s *domain.StudentRecord) interface{} { return s.Email }},
+	{"status", func(s *domain.StudentRecord) interface{} { return s.Status }},
+	{"file_name", func(s *domain.StudentRecord) interface{} { return s.FileName }},
+	{"imported_at", func(s *domain.StudentRecord) interface{} { return formatTime(s.ImportedAt) }},
+	{"first_name", func(s *domain.StudentRecord) interface{} { return s.FirstName }},
+	{"last_name", func(s *domain.StudentRecord) interface{} { return s.LastName }},
+	{"status_items", func(s *domain.StudentRecord) interface{} { return strings.Join(s.StatusItems, ", ") }},
+	{"application_date", func(s *domain.StudentRecord) interface{} { return s.ApplicationDate }},
+	{"total_score", func(s *domain.StudentRecord) interface{} { return s.TotalScore }},
+	{"full_name", func(s *domain.StudentRecord) interface{} { return s.StudentWAC.FullName }},
+	{"join_date", func(s *domain.StudentRecord) interface{} { return s.JoinDate }},
+	{"location", func(s *domain.StudentRecord) interface{} { return s.Location }},
+	{"position", func(s *domain.StudentRecord) interface{} { return s.Position }},
+	{"company", func(s *domain.StudentRecord) interface{} { return s.Company }},
+	{"preffered_language", func(s *domain.StudentRecord) interface{} { return strings.Join(s.PrefferedLanguage, ", ") }},
+	{"receives_community_updates", func(s *domain.StudentRecord) interface{} { return s.ReceivesCommunityUpdates }},
+	{"membership_type", func(s *domain.StudentRecord) interface{} { return s.MembershipType }},
+	{"attended_events", func(s *domain.StudentRecord) interface{} { return s.AttendedEvents }},
+	{"registered_not_visited", func(s *domain.StudentRecord) interface{} { return s.RegisteredNotVisited }},
+	{"registered", func(s *domain.StudentRecord) interface{} { return s.Registered }},
+}
+
+var projectColumns = []string{"name", "score", "finished_at", "deadline"}
+
+// studentsTable converts students into rows of selected columns.
+type studentsTable struct {
+	columns      []studentColumn
+	withProjects bool
+	projects     int
+}
+
+// newStudentsTable selects columns by names, all columns are selected when names are empty.
+// Returns studentsTable pointer and an error for unknown column names.
+func newStudentsTable(names []string) (*studentsTable, error) {
+	if len(names) == 0 {
+		return &studentsTable{columns: studentColumns, withProjects: true}, nil
+	}
+
+	byName := make(map[string]studentColumn, len(studentColumns))
+	for _, c := range studentColumns {
+		byName[c.name] = c
+	}
+
+	t := &studentsTable{}
+	for _, name := range names {
+		if name == projectsColumn {
+			t.withProjects = true
+			continue
+		}
+		c, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", domain.ErrUnknownColumn, name)
+		}
+		t.columns = append(t.columns, c)
+	}
+
+	return t, nil
+}
+
+func (t *studentsTable) header() []interface{} {
+	row := make([]interface{}, 0, len(t.columns)+t.projects*len(projectColumns))
+	for _, c := range t.columns {
+		row = append(row, c.name)
+	}
+	for i := 1; i <= t.projects; i++ {
+		for _, pc := range projectColumns {
+			row = append(row, fmt.Sprintf("project_%d_%s", i, pc))
+		}
+	}
+
+	return row
+}
+
+func (t *studentsTable) row(s *domain.StudentRecord) []interface{} {
+	row := make([]interface{}, 0, len(t.columns)+t.projects*len(projectColumns))
+	for _, c := range t.columns {
+		row = append(row, c.value(s))
+	}
+	for i := 0; i < t.projects; i++ {
+		if i >= len(s.Projects) {
+			row = append(row, nil, nil, nil, nil)
+			continue
+		}
+		p := s.Projects[i]
+		row = append(row, p.Name, p.Score, p.FinishedAt, p.Deadline)
+	}
+
+	return row
+}
+
+// exportWithSchema writes students in the layout of the schema, so the file can be imported again with it.
+// Only students of the schema source are exported unless source is set explicitly.
+func (s *StudentsService) exportWithSchema(ctx context.Context, options domain.ExportStudentsOptions, w io.Writer) error {
+	schema, err := s.schemasRepo.GetById(ctx, options.SchemaID)
+	if err != nil {
+		return err
+	}
+
+	if options.Source == "" {
+		options.Source = schema.Name
+	}
+
+	ew, err := export.NewWriter(options.Format, w)
+	if err != nil {
+		return err
+	}
+
+	fw, err := parser.NewFileWriter(ew, schema.ConvertToParserSchema())
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Iterate(ctx, options.ListStudentsOptions, func(student *domain.StudentRecord) error {
+		return fw.Write(student)
+	}); err != nil {
+		return err
+	}
+
+	return fw.Close()
+}
+
+// ExportStudents writes students matching options to w as CSV or XLSX table.
+// Projects are flattened into project_N_* columns repeated up to the largest number of student projects.
+func (s *StudentsService) ExportStudents(ctx context.Context, options domain.ExportStudentsOptions, w io.Writer) error {
+	if options.SchemaID != "" {
+		return s.exportWithSchema(ctx, options, w)
+	}
+
+	table, err := newStudentsTable(options.Columns)
+	if err != nil {
+		return err
+	}
+
+	if table.withProjects {
+		if table.projects, err = s.repo.MaxProjects(ctx, options.ListStudentsOptions); err != nil {
+			return err
+		}
+	}
+
+	ew, err := export.NewWriter(options.Format, w)
+	if err != nil {
+		return err
+	}
+
+	if err := ew.Write(table.header()); err != nil {
+		return err
+	}
+
+	if err := s.repo.Iterate(ctx, options.ListStudentsOptions, func(student *domain.StudentRecord) error {
+		return ew.Write(table.row(student))
+	}); err != nil {
+		return err
+	}
+
+	return ew.Close()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"github.com/abdukhashimov/student_aggregator/pkg/export"
+)
+
+func TestStudentsTable(t *testing.T) {
+	table, err := newStudentsTable([]string{"email", "projects", "total_score"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.projects = 2
+
+	wantHeader := []interface{}{
+		"email", "total_score",
+		"project_1_name", "project_1_score", "project_1_finished_at", "project_1_deadline",
+		"project_2_name", "project_2_score", "project_2_finished_at", "project_2_deadline",
+	}
+	if got := table.header(); !reflect.DeepEqual(got, wantHeader) {
+		t.Errorf("header() = %v, want %v", got, wantHeader)
+	}
+
+	student := domain.StudentRecord{
+		Email: "obi@jedi.rules",
+		StudentRSS: domain.StudentRSS{
+			Projects:   []domain.Project{{Name: "Lightsaber", Score: 90, FinishedAt: "2022-10-01", Deadline: "2022-10-02"}},
+			TotalScore: 90,
+		},
+	}
+	wantRow := []interface{}{
+		"obi@jedi.rules", 90,
+		"Lightsaber", 90, "2022-10-01", "2022-10-02",
+		nil, nil, nil, nil,
+	}
+	if got := table.row(&student); !reflect.DeepEqual(got, wantRow) {
+		t.Errorf("row() = %v, want %v", got, wantRow)
+	}
+}
+
+func TestNewStudentsTable(t *testing.T) {
+	table, err := newStudentsTable(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.columns) != len(studentColumns) || !table.withProjects {
+		t.Error("all columns should be selected by default")
+	}
+
+	if _, err := newStudentsTable([]string{"email", "password"}); !errors.Is(err, domain.ErrUnknownColumn) {
+		t.Errorf("newStudentsTable() error = %v, want %v", err, domain.ErrUnknownColumn)
+	}
+}
+
+// exportedStore iterates students and counts the iterations.
+type exportedStore struct {
+	ports.StudentsStore
+	students   []domain.StudentRecord
+	iterations int
+}
+
+func (es *exportedStore) Iterate(_ context.Context, _ domain.ListStudentsOptions, fn func(student *domain.StudentRecord) error) error {
+	es.iterations++
+	for i := range es.students {
+		if err := fn(&es.students[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (es *exportedStore) MaxProjects(_ context.Context, _ domain.ListStudentsOptions) (int, error) {
+	projects := 0
+	for _, s := range es.students {
+		if len(s.Projects) > projects {
+			projects = len(s.Projects)
+		}
+	}
+
+	return projects, nil
+}
+
+func TestExportStudents(t *testing.T) {
+	store := &exportedStore{students: []domain.StudentRecord{
+		{Email: "obi@jedi.rules", StudentRSS: domain.StudentRSS{Projects: []domain.Project{{Name: "Lightsaber"}, {Name: "Droid"}}}},
+		{Email: "luke@jedi.rules"},
+	}}
+	s := &StudentsService{repo: store}
+
+	var buf bytes.Buffer
+	err := s.ExportStudents(context.Background(), domain.ExportStudentsOptions{
+		Format:  export.CSV,
+		Columns: []string{"email", "projects"},
+	}, &buf)
+	if err != nil {
+		t.Fatalf("ExportStudents() error = %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || len(rows[0]) != 1+2*len(projectColumns) {
+		t.Errorf("ExportStudents() rows = %q, want header and 2 students with 2 projects columns groups", rows)
+	}
+	if store.iterations != 1 {
+		t.Errorf("ExportStudents() read students %d times, want once", store.iterations)
+	}
+}
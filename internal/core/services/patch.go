@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/pkg/jsonpatch"
+)
+
+// PatchStudent applies JSON Merge Patch or JSON Patch to student JSON representation and replaces the record
+// with the validated result. Provenance fields are read-only, derived scores and dates are recalculated.
+// The record is patched if it has revision, or regardless of revision when it is nil,
+// but it is never replaced if it is modified after being read for patching.
+func (s *StudentsService) PatchStudent(ctx context.Context, id string, patch domain.StudentPatch, revision *int) (*domain.StudentRecord, error) {
+	student, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if revision != nil && *revision != student.Revision {
+		return nil, domain.ErrPreconditionFailed
+	}
+
+	patched, err := patchStudent(student, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Replace(ctx, id, *patched, &student.Revision); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetById(ctx, id)
+}
+
+type readOnlyField struct {
+	name          string
+	before, after interface{}
+}
+
+// managedFields returns fields of student changed by workflow, tags, notes and annotations endpoints only.
+func managedFields(student, changed *domain.StudentRecord) []readOnlyField {
+	return []readOnlyField{
+		{"status", student.Status, changed.Status},
+		{"status_changes", student.StatusChanges, changed.StatusChanges},
+		{"tags", student.Tags, changed.Tags},
+		{"notes", student.Notes, changed.Notes},
+		{"annotations", student.Annotations, changed.Annotations},
+	}
+}
+
+// checkReadOnly returns an error wrapping ErrInvalidParams when any of the fields is changed.
+func checkReadOnly(fields []readOnlyField) error {
+	for _, field := range fields {
+		// values are compared in JSON representation, so times are compared regardless of their locations
+		before, _ := json.Marshal(field.before)
+		after, _ := json.Marshal(field.after)
+		if !bytes.Equal(before, after) {
+			return fmt.Errorf("%w: %s is read-only", domain.ErrInvalidParams, field.name)
+		}
+	}
+
+	return nil
+}
+
+// patchStudent applies patch to student and validates the result.
+// Returns patched copy of student and an error wrapping ErrInvalidPatch or ErrInvalidParams.
+func patchStudent(student *domain.StudentRecord, patch domain.StudentPatch) (*domain.StudentRecord, error) {
+	doc, err := json.Marshal(student)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []byte
+	switch patch.Type {
+	case domain.MergePatchType:
+		result, err = jsonpatch.MergePatch(doc, patch.Data)
+	case domain.JSONPatchType:
+		var p jsonpatch.Patch
+		if p, err = jsonpatch.DecodePatch(patch.Data); err == nil {
+			result, err = p.Apply(doc)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported patch type %q", domain.ErrInvalidPatch, patch.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPatch, err)
+	}
+
+	var patched domain.StudentRecord
+	d := json.NewDecoder(bytes.NewReader(result))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&patched); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPatch, err)
+	}
+
+	readOnly := []readOnlyField{
+		{"id", student.ID, patched.ID},
+		{"source", student.Source, patched.Source},
+		{"file_name", student.FileName, patched.FileName},
+		{"imported_from", student.ImportedFrom, patched.ImportedFrom},
+		{"imported_at", student.ImportedAt, patched.ImportedAt},
+		{"merged_from", student.MergedFrom, patched.MergedFrom},
+		{"revision", student.Revision, patched.Revision},
+		{"deleted_at", student.DeletedAt, patched.DeletedAt},
+		{"deleted_by", student.DeletedBy, patched.DeletedBy},
+	}
+	if err := checkReadOnly(append(readOnly, managedFields(student, &patched)...)); err != nil {
+		return nil, err
+	}
+
+	if err := patched.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &patched, nil
+}
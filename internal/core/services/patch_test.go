@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+func TestPatchStudent(t *testing.T) {
+	student := &domain.StudentRecord{
+		ID:         "1",
+		Source:     domain.RSS,
+		Email:      "luke@jedi.rules",
+		FileName:   "rss.xlsx",
+		ImportedAt: time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC),
+		StudentRSS: domain.StudentRSS{
+			FirstName:   "Luke",
+			LastName:    "Skywalker",
+			StatusItems: []string{"active"},
+			Projects: []domain.Project{
+				{Name: "Songbird", Score: 50},
+				{Name: "Gem Puzzle", Score: 70},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		patch   domain.StudentPatch
+		check   func(t *testing.T, got *domain.StudentRecord)
+		wantErr error
+	}{
+		{
+			name:  "mergeClearsField",
+			patch: domain.StudentPatch{Type: domain.MergePatchType, Data: []byte(`{"last_name":null,"application_date":"2022-10-01"}`)},
+			check: func(t *testing.T, got *domain.StudentRecord) {
+				if got.LastName != "" || got.ApplicationDate != "2022-10-01" || got.FirstName != "Luke" {
+					t.Errorf("patchStudent() = %+v, want cleared last name and new application date", got)
+				}
+			},
+		},
+		{
+			name: "jsonPatchArrays",
+			patch: domain.StudentPatch{Type: domain.JSONPatchType, Data: []byte(`[
+				{"op":"replace","path":"/projects/1/score","value":100},
+				{"op":"remove","path":"/projects/0"},
+				{"op":"add","path":"/status_items/-","value":"graduated"}
+			]`)},
+			check: func(t *testing.T, got *domain.StudentRecord) {
+				want := []domain.Project{{Name: "Gem Puzzle", Score: 100}}
+				if !reflect.DeepEqual(got.Projects, want) {
+					t.Errorf("patchStudent() projects = %v, want %v", got.Projects, want)
+				}
+				if !reflect.DeepEqual(got.StatusItems, []string{"active", "graduated"}) {
+					t.Errorf("patchStudent() status items = %v", got.StatusItems)
+				}
+			},
+		},
+		{
+			name:    "readOnlyField",
+			patch:   domain.StudentPatch{Type: domain.MergePatchType, Data: []byte(`{"source":"WAC"}`)},
+			wantErr: domain.ErrInvalidParams,
+		},
+		{
+			name:    "deletionField",
+			patch:   domain.StudentPatch{Type: domain.JSONPatchType, Data: []byte(`[{"op":"add","path":"/deleted_at","value":"2022-11-01T10:00:00Z"}]`)},
+			wantErr: domain.ErrInvalidParams,
+		},
+		{
+			name:    "statusField",
+			patch:   domain.StudentPatch{Type: domain.MergePatchType, Data: []byte(`{"status":"graduated"}`)},
+			wantErr: domain.ErrInvalidParams,
+		},
+		{
+			name:    "invalidResult",
+			patch:   domain.StudentPatch{Type: domain.JSONPatchType, Data: []byte(`[{"op":"replace","path":"/projects/0/score","value":-1}]`)},
+			wantErr: domain.ErrInvalidParams,
+		},
+		{
+			name:    "unknownField",
+			patch:   domain.StudentPatch{Type: domain.MergePatchType, Data: []byte(`{"nickname":"Red Five"}`)},
+			wantErr: domain.ErrInvalidPatch,
+		},
+		{
+			name:    "wrongType",
+			patch:   domain.StudentPatch{Type: domain.MergePatchType, Data: []byte(`{"first_name":42}`)},
+			wantErr: domain.ErrInvalidPatch,
+		},
+		{
+			name:    "failedTest",
+			patch:   domain.StudentPatch{Type: domain.JSONPatchType, Data: []byte(`[{"op":"test","path":"/first_name","value":"Leia"}]`)},
+			wantErr: domain.ErrInvalidPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := patchStudent(student, tt.patch)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("patchStudent() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tt.check(t, got)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"github.com/abdukhashimov/student_aggregator/pkg/similarity"
+)
+
+var _ ports.PeopleService = (*PeopleService)(nil)
+
+type PeopleService struct {
+	repo ports.StudentsStore
+	cfg  *config.Config
+}
+
+func NewPeopleService(repo ports.StudentsStore, cfg *config.Config) *PeopleService {
+	return &PeopleService{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// GetProfile aggregates student records with the normalized email, or the ones records with it were merged into,
+// into a single profile. Records in trash are left out.
+func (ps *PeopleService) GetProfile(ctx context.Context, email string) (*domain.PersonProfile, error) {
+	email = domain.NormalizeEmail(email)
+	if email == "" {
+		return nil, domain.ErrNotFound
+	}
+
+	records, err := ps.repo.PersonRecords(ctx, email, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	return buildProfile(email, records), nil
+}
+
+// buildProfile combines person records into profile.
+// Identity fields are taken from the most recently imported records.
+func buildProfile(email string, records []domain.StudentRecord) *domain.PersonProfile {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].ImportedAt.After(records[j].ImportedAt)
+	})
+
+	profile := &domain.PersonProfile{
+		Email:     email,
+		Sources:   map[string][]domain.StudentRecord{},
+		Timeline:  []domain.TimelineEvent{},
+		Conflicts: []domain.FieldConflict{},
+	}
+
+	identity := &profile.Identity
+	for _, record := range records {
+		profile.Sources[record.Source] = append(profile.Sources[record.Source], record)
+
+		mergeString(&identity.FirstName, record.FirstName)
+		mergeString(&identity.LastName, record.LastName)
+		mergeString(&identity.FullName, record.DisplayName())
+		mergeString(&identity.Location, record.Location)
+		mergeString(&identity.Company, record.Company)
+		mergeString(&identity.Position, record.Position)
+
+		profile.Timeline = append(profile.Timeline, domain.TimelineEvent{
+			At:        record.ImportedAt,
+			Event:     domain.TimelineImported,
+			Source:    record.Source,
+			FileName:  record.FileName,
+			StudentID: record.ID,
+		})
+		for _, merged := range record.MergedFrom {
+			profile.Timeline = append(profile.Timeline, domain.TimelineEvent{
+				At:        merged.MergedAt,
+				Event:     domain.TimelineMerged,
+				Source:    merged.Source,
+				FileName:  merged.FileName,
+				StudentID: merged.ID,
+			})
+		}
+	}
+
+	sort.SliceStable(profile.Timeline, func(i, j int) bool {
+		return profile.Timeline[i].At.Before(profile.Timeline[j].At)
+	})
+
+	profile.Conflicts = findConflicts(records)
+
+	return profile
+}
+
+// findConflicts compares person records field by field ignoring case, punctuation and words order.
+// Returns fields having more than one distinct value.
+func findConflicts(records []domain.StudentRecord) []domain.FieldConflict {
+	fields := []struct {
+		name  string
+		value func(r *domain.StudentRecord) string
+	}{
+		{"name", func(r *domain.StudentRecord) string { return r.DisplayName() }},
+		{"location", func(r *domain.StudentRecord) string { return r.Location }},
+		{"company", func(r *domain.StudentRecord) string { return r.Company }},
+		{"position", func(r *domain.StudentRecord) string { return r.Position }},
+		{"membership_type", func(r *domain.StudentRecord) string { return r.MembershipType }},
+		{"application_date", func(r *domain.StudentRecord) string { return r.ApplicationDate }},
+		{"join_date", func(r *domain.StudentRecord) string { return r.JoinDate }},
+	}
+
+	conflicts := []domain.FieldConflict{}
+	for _, field := range fields {
+		var values []domain.SourcedValue
+		distinct := map[string]bool{}
+		for i := range records {
+			value := strings.TrimSpace(field.value(&records[i]))
+			if value == "" {
+				continue
+			}
+			distinct[strings.Join(sortedTokens(value), " ")] = true
+			values = append(values, domain.SourcedValue{
+				Value:     value,
+				Source:    records[i].Source,
+				StudentID: records[i].ID,
+			})
+		}
+
+		if len(distinct) > 1 {
+			conflicts = append(conflicts, domain.FieldConflict{
+				Field:  field.name,
+				Values: values,
+			})
+		}
+	}
+
+	return conflicts
+}
+
+func sortedTokens(s string) []string {
+	tokens := similarity.Tokens(s)
+	sort.Strings(tokens)
+
+	return tokens
+}
+
+func mergeString(dst *string, value string) {
+	if *dst == "" {
+		*dst = strings.TrimSpace(value)
+	}
+}
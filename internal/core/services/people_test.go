@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+func TestBuildProfile(t *testing.T) {
+	rssImport := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	wacImport := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	mergedAt := time.Date(2022, 11, 5, 0, 0, 0, 0, time.UTC)
+
+	records := []domain.StudentRecord{
+		{
+			ID:         "1",
+			Source:     domain.RSS,
+			Email:      "obi@jedi.rules",
+			FileName:   "rss.xlsx",
+			ImportedAt: rssImport,
+			StudentRSS: domain.StudentRSS{FirstName: "Obi-Wan", LastName: "Kenobi"},
+		},
+		{
+			ID:         "2",
+			Source:     domain.WAC,
+			Email:      "obi@jedi.rules",
+			FileName:   "wac.xlsx",
+			ImportedAt: wacImport,
+			StudentWAC: domain.StudentWAC{FullName: "Ben Kenobi", Location: "Tatooine", Company: "Jedi Order"},
+			MergedFrom: []domain.MergedRecord{{ID: "3", Source: domain.WAC, FileName: "wac-old.xlsx", MergedAt: mergedAt}},
+		},
+	}
+
+	profile := buildProfile("obi@jedi.rules", records)
+
+	wantIdentity := domain.PersonIdentity{
+		FirstName: "Obi-Wan",
+		LastName:  "Kenobi",
+		FullName:  "Ben Kenobi",
+		Location:  "Tatooine",
+		Company:   "Jedi Order",
+	}
+	if !reflect.DeepEqual(profile.Identity, wantIdentity) {
+		t.Errorf("buildProfile() identity = %v, want %v", profile.Identity, wantIdentity)
+	}
+
+	if len(profile.Sources[domain.RSS]) != 1 || len(profile.Sources[domain.WAC]) != 1 {
+		t.Errorf("buildProfile() sources = %v, want one record per source", profile.Sources)
+	}
+
+	var timeline []string
+	for _, e := range profile.Timeline {
+		timeline = append(timeline, e.Event+":"+e.StudentID)
+	}
+	wantTimeline := []string{"imported:1", "imported:2", "merged:3"}
+	if !reflect.DeepEqual(timeline, wantTimeline) {
+		t.Errorf("buildProfile() timeline = %v, want %v", timeline, wantTimeline)
+	}
+
+	if len(profile.Conflicts) != 1 || profile.Conflicts[0].Field != "name" {
+		t.Errorf("buildProfile() conflicts = %v, want name conflict", profile.Conflicts)
+	}
+}
+
+func TestGetProfile(t *testing.T) {
+	deletedAt := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	store := &personStore{students: []domain.StudentRecord{
+		{ID: "1", Source: domain.RSS, Email: "obi@jedi.rules"},
+		{ID: "2", Source: domain.WAC, Email: "ben@tatooine.sand", MergedFrom: []domain.MergedRecord{{ID: "3", Email: "obi@jedi.rules"}}},
+		{ID: "4", Source: domain.WAC, Email: "obi@jedi.rules", DeletedAt: &deletedAt},
+		{ID: "5", Source: domain.RSS, Email: "luke@jedi.rules"},
+	}}
+	ps := NewPeopleService(store, nil)
+
+	profile, err := ps.GetProfile(context.Background(), " Obi@Jedi.Rules")
+	if err != nil {
+		t.Fatalf("GetProfile() error = %v", err)
+	}
+
+	var ids []string
+	for _, source := range []string{domain.RSS, domain.WAC} {
+		for _, record := range profile.Sources[source] {
+			ids = append(ids, record.ID)
+		}
+	}
+	if want := []string{"1", "2"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("GetProfile() records = %v, want %v", ids, want)
+	}
+
+	if _, err := ps.GetProfile(context.Background(), "yoda@jedi.rules"); err != domain.ErrNotFound {
+		t.Errorf("GetProfile() error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"sort"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"github.com/abdukhashimov/student_aggregator/pkg/export"
+	"github.com/abdukhashimov/student_aggregator/pkg/hash"
+	"github.com/abdukhashimov/student_aggregator/pkg/parser"
+)
+
+const defaultErasuresLimit = 100
+
+var _ ports.PrivacyService = (*PrivacyService)(nil)
+
+// PrivacyService exports and erases personal data of a person found by normalized email.
+type PrivacyService struct {
+	students ports.StudentsStore
+	schemas  ports.SchemaStore
+	erasures ports.ErasuresStore
+	storage  ports.StorageService
+	hasher   hash.PasswordHasher
+	cfg      *config.Config
+	now      func() time.Time
+}
+
+func NewPrivacyService(students ports.StudentsStore, schemas ports.SchemaStore, erasures ports.ErasuresStore,
+	storage ports.StorageService, cfg *config.Config) *PrivacyService {
+	return &PrivacyService{
+		students: students,
+		schemas:  schemas,
+		erasures: erasures,
+		storage:  storage,
+		hasher:   hash.NewSHA1Hasher(cfg.Project.Salt),
+		cfg:      cfg,
+		now:      time.Now,
+	}
+}
+
+// ExportPerson collects student records of the person, their history and files they were imported from.
+func (ps *PrivacyService) ExportPerson(ctx context.Context, email string) (*domain.PersonExport, error) {
+	email = domain.NormalizeEmail(email)
+	if email == "" {
+		return nil, domain.ErrNotFound
+	}
+
+	records, history, err := ps.personData(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PersonExport{
+		Email:      email,
+		ExportedAt: ps.now().UTC(),
+		Records:    records,
+		Imports:    importReferences(records, history),
+		History:    history,
+	}, nil
+}
+
+// ExportPersonArchive writes zip archive with records, imports and history of the person as JSON files.
+// Nothing is written when the person is not found.
+func (ps *PrivacyService) ExportPersonArchive(ctx context.Context, email string, w io.Writer) error {
+	personExport, err := ps.ExportPerson(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"person.json", map[string]interface{}{"email": personExport.Email, "exported_at": personExport.ExportedAt}},
+		{"records.json", personExport.Records},
+		{"imports.json", personExport.Imports},
+		{"history.json", personExport.History},
+	}
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.data); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// ErasePerson removes person rows from stored source files, then removes student records of the person
+// with their history, including history of purged records, and saves the erasure audit record on behalf of ctx actor.
+// Files are changed first, so erasure failed midway can be repeated.
+func (ps *PrivacyService) ErasePerson(ctx context.Context, email string, input domain.EraseInput) (*domain.Erasure, error) {
+	email = domain.NormalizeEmail(email)
+	if email == "" {
+		return nil, domain.ErrNotFound
+	}
+
+	records, history, err := ps.personData(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	ids := historyIDs(personIDs(records), history)
+
+	emailHash, err := ps.hasher.Hash(email)
+	if err != nil {
+		return nil, err
+	}
+
+	erasure := domain.Erasure{
+		EmailHash:   emailHash,
+		Reason:      input.Reason,
+		RequestedBy: domain.ActorFromContext(ctx).Username,
+		At:          ps.now().UTC(),
+		Files:       []string{},
+	}
+
+	emails := personEmails(email, append(records, snapshots(history)...))
+	for _, ref := range importReferences(records, history) {
+		if containsString(erasure.Files, ref.FileName) {
+			continue
+		}
+		erased, err := ps.eraseFromFile(ctx, ref, emails)
+		if err != nil {
+			return nil, err
+		}
+		if erased {
+			erasure.Files = append(erasure.Files, ref.FileName)
+		}
+	}
+
+	erasure.Students, erasure.HistoryEntries, err = ps.students.Erase(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	erasure.ID, err = ps.erasures.Create(ctx, erasure)
+	if err != nil {
+		return nil, err
+	}
+
+	return &erasure, nil
+}
+
+// personData returns student records of the person and history of the records and of purged students
+// the person was imported as. The person is not found when there are neither records nor history.
+func (ps *PrivacyService) personData(ctx context.Context, email string) ([]domain.StudentRecord, []domain.HistoryEntry, error) {
+	records, err := ps.students.PersonRecords(ctx, email, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	history, err := ps.students.PersonHistory(ctx, email, personIDs(records))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 && len(history) == 0 {
+		return nil, nil, domain.ErrNotFound
+	}
+
+	return records, history, nil
+}
+
+func (ps *PrivacyService) ListErasures(ctx context.Context, limit int) ([]domain.Erasure, error) {
+	if limit <= 0 {
+		limit = defaultErasuresLimit
+	}
+
+	return ps.erasures.List(ctx, limit)
+}
+
+// eraseFromFile removes rows with emails from the stored file of the import and stores the file back
+// in the format and with the content type it was stored with, csv files are told by their content type.
+// Rows are grouped by the schema named after the import source. Files missing in storage are skipped.
+// Reports whether any rows were removed.
+func (ps *PrivacyService) eraseFromFile(ctx context.Context, ref domain.ImportReference, emails []string) (bool, error) {
+	r, info, err := ps.storage.GetFile(ctx, ref.FileName)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	schema, err := ps.sourceSchema(ctx, ref.Source)
+	if err != nil {
+		return false, err
+	}
+
+	erase := parser.EraseRecords
+	if isCSV(info.ContentType) {
+		erase = parser.EraseCSVRecords
+	}
+
+	buf, erased, err := erase(r, schema, emails)
+	if err != nil {
+		return false, err
+	}
+	if erased == 0 {
+		return false, nil
+	}
+
+	_, err = ps.storage.PutFile(ctx, domain.PutFileOptions{
+		ObjectName:  ref.FileName,
+		Body:        buf,
+		Size:        int64(buf.Len()),
+		ContentType: info.ContentType,
+	})
+
+	return err == nil, err
+}
+
+// isCSV reports whether content type is one of csv media types, parameters like charset are ignored.
+func isCSV(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == export.ContentType(export.CSV) || mediaType == "application/csv"
+}
+
+// sourceSchema returns parser schema of the schema named after source, or a schema with headers
+// and every row being a record when there is no such schema.
+func (ps *PrivacyService) sourceSchema(ctx context.Context, source string) (parser.Schema, error) {
+	schemas, _, err := ps.schemas.FindAll(ctx, domain.ListSchemasOptions{})
+	if err != nil {
+		return parser.Schema{}, err
+	}
+
+	for i := range schemas {
+		if schemas[i].Name == source {
+			return schemas[i].ConvertToParserSchema(), nil
+		}
+	}
+
+	return parser.Schema{Headers: true}, nil
+}
+
+// personIDs returns ids of person records and the ones merged into them.
+func personIDs(records []domain.StudentRecord) []string {
+	var ids []string
+	for _, record := range records {
+		ids = append(ids, record.ID)
+		for _, merged := range record.MergedFrom {
+			if !containsString(ids, merged.ID) {
+				ids = append(ids, merged.ID)
+			}
+		}
+	}
+
+	return ids
+}
+
+// historyIDs returns ids with ids of students history entries belong to.
+func historyIDs(ids []string, history []domain.HistoryEntry) []string {
+	for _, entry := range history {
+		if !containsString(ids, entry.StudentID) {
+			ids = append(ids, entry.StudentID)
+		}
+	}
+
+	return ids
+}
+
+// snapshots returns student records of history entries.
+func snapshots(history []domain.HistoryEntry) []domain.StudentRecord {
+	records := make([]domain.StudentRecord, 0, len(history))
+	for _, entry := range history {
+		records = append(records, entry.Snapshot)
+	}
+
+	return records
+}
+
+// personEmails returns email with other emails of person records.
+func personEmails(email string, records []domain.StudentRecord) []string {
+	emails := []string{email}
+	for _, record := range records {
+		for _, e := range append([]string{record.Email}, mergedEmails(record)...) {
+			if e = domain.NormalizeEmail(e); e != "" && !containsString(emails, e) {
+				emails = append(emails, e)
+			}
+		}
+	}
+
+	return emails
+}
+
+func mergedEmails(record domain.StudentRecord) []string {
+	emails := make([]string, 0, len(record.MergedFrom))
+	for _, merged := range record.MergedFrom {
+		emails = append(emails, merged.Email)
+	}
+
+	return emails
+}
+
+// importReferences returns files person records were imported from, found in records and history imports,
+// ordered by import time. Each file is referenced once per student with its first import time.
+func importReferences(records []domain.StudentRecord, history []domain.HistoryEntry) []domain.ImportReference {
+	var refs []domain.ImportReference
+	add := func(ref domain.ImportReference) {
+		if ref.FileName == "" {
+			return
+		}
+		for i := range refs {
+			if refs[i].FileName == ref.FileName && refs[i].StudentID == ref.StudentID {
+				if refs[i].ImportedAt.IsZero() || (!ref.ImportedAt.IsZero() && ref.ImportedAt.Before(refs[i].ImportedAt)) {
+					refs[i].ImportedAt = ref.ImportedAt
+				}
+				return
+			}
+		}
+		refs = append(refs, ref)
+	}
+
+	for _, record := range records {
+		add(domain.ImportReference{
+			FileName:   record.FileName,
+			Source:     record.Source,
+			StudentID:  record.ID,
+			ImportedAt: record.ImportedAt,
+		})
+		for _, merged := range record.MergedFrom {
+			add(domain.ImportReference{
+				FileName:  merged.FileName,
+				Source:    merged.Source,
+				StudentID: merged.ID,
+			})
+		}
+	}
+	for _, entry := range history {
+		if entry.Actor.Via != domain.ViaImport {
+			continue
+		}
+		add(domain.ImportReference{
+			FileName:   entry.Actor.FileName,
+			Source:     entry.Snapshot.Source,
+			StudentID:  entry.StudentID,
+			ImportedAt: entry.At,
+		})
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool {
+		return refs[i].ImportedAt.Before(refs[j].ImportedAt)
+	})
+	if refs == nil {
+		refs = []domain.ImportReference{}
+	}
+
+	return refs
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"github.com/abdukhashimov/student_aggregator/pkg/export"
+	"github.com/abdukhashimov/student_aggregator/pkg/hash"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestImportReferences(t *testing.T) {
+	first := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	third := second.Add(24 * time.Hour)
+
+	records := []domain.StudentRecord{
+		{
+			ID:         "1",
+			Source:     domain.RSS,
+			FileName:   "rss-2.xlsx",
+			ImportedAt: third,
+			MergedFrom: []domain.MergedRecord{{ID: "2", Source: domain.WAC, FileName: "wac.xlsx"}},
+		},
+	}
+	history := []domain.HistoryEntry{
+		{
+			StudentID: "1",
+			Actor:     domain.Actor{Via: domain.ViaImport, FileName: "rss-1.xlsx"},
+			At:        first,
+			Snapshot:  domain.StudentRecord{Source: domain.RSS},
+		},
+		{
+			StudentID: "1",
+			Actor:     domain.Actor{Via: domain.ViaImport, FileName: "rss-2.xlsx"},
+			At:        third.Add(-time.Second),
+			Snapshot:  domain.StudentRecord{Source: domain.RSS},
+		},
+		{
+			StudentID: "1",
+			Actor:     domain.Actor{Via: domain.ViaAPI, Username: "yoda"},
+			At:        third.Add(time.Hour),
+		},
+		{
+			StudentID: "2",
+			Actor:     domain.Actor{Via: domain.ViaImport, FileName: "wac.xlsx"},
+			At:        second,
+			Snapshot:  domain.StudentRecord{Source: domain.WAC},
+		},
+	}
+
+	want := []domain.ImportReference{
+		{FileName: "rss-1.xlsx", Source: domain.RSS, StudentID: "1", ImportedAt: first},
+		{FileName: "wac.xlsx", Source: domain.WAC, StudentID: "2", ImportedAt: second},
+		{FileName: "rss-2.xlsx", Source: domain.RSS, StudentID: "1", ImportedAt: third.Add(-time.Second)},
+	}
+
+	if got := importReferences(records, history); !reflect.DeepEqual(got, want) {
+		t.Errorf("importReferences() = %v, want %v", got, want)
+	}
+}
+
+func TestPersonEmails(t *testing.T) {
+	records := []domain.StudentRecord{
+		{ID: "1", Email: "obi@jedi.rules", MergedFrom: []domain.MergedRecord{{ID: "2", Email: "Ben.Kenobi@Tatooine.org"}}},
+		{ID: "3", MergedFrom: []domain.MergedRecord{{ID: "4", Email: "obi@jedi.rules"}}},
+	}
+
+	wantEmails := []string{"obi@jedi.rules", "ben.kenobi@tatooine.org"}
+	if got := personEmails("obi@jedi.rules", records); !reflect.DeepEqual(got, wantEmails) {
+		t.Errorf("personEmails() = %v, want %v", got, wantEmails)
+	}
+
+	wantIDs := []string{"1", "2", "3", "4"}
+	if got := personIDs(records); !reflect.DeepEqual(got, wantIDs) {
+		t.Errorf("personIDs() = %v, want %v", got, wantIDs)
+	}
+}
+
+// personStore keeps students and their history in memory, looking them up by email as the mongodb repo does.
+type personStore struct {
+	ports.StudentsStore
+	students []domain.StudentRecord
+	history  []domain.HistoryEntry
+}
+
+func (ps *personStore) Purge(_ context.Context, before time.Time) (int64, error) {
+	var kept []domain.StudentRecord
+	for _, s := range ps.students {
+		if s.DeletedAt == nil || !s.DeletedAt.Before(before) {
+			kept = append(kept, s)
+		}
+	}
+	purged := int64(len(ps.students) - len(kept))
+	ps.students = kept
+
+	return purged, nil
+}
+
+func (ps *personStore) PersonRecords(_ context.Context, email string, withDeleted bool) ([]domain.StudentRecord, error) {
+	var records []domain.StudentRecord
+	for _, s := range ps.students {
+		if hasEmail(s, email) && (withDeleted || s.DeletedAt == nil) {
+			records = append(records, s)
+		}
+	}
+
+	return records, nil
+}
+
+func (ps *personStore) PersonHistory(_ context.Context, email string, ids []string) ([]domain.HistoryEntry, error) {
+	var entries []domain.HistoryEntry
+	for _, entry := range ps.history {
+		if containsString(ids, entry.StudentID) || hasEmail(entry.Snapshot, email) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+func (ps *personStore) Erase(_ context.Context, ids []string) (int64, int64, error) {
+	var students []domain.StudentRecord
+	for _, s := range ps.students {
+		if !containsString(ids, s.ID) {
+			students = append(students, s)
+		}
+	}
+	var history []domain.HistoryEntry
+	for _, entry := range ps.history {
+		if !containsString(ids, entry.StudentID) {
+			history = append(history, entry)
+		}
+	}
+	erased, erasedHistory := len(ps.students)-len(students), len(ps.history)-len(history)
+	ps.students, ps.history = students, history
+
+	return int64(erased), int64(erasedHistory), nil
+}
+
+func hasEmail(s domain.StudentRecord, email string) bool {
+	return s.Email == email || containsString(mergedEmails(s), email)
+}
+
+type savedErasures struct {
+	ports.ErasuresStore
+	erasures []domain.Erasure
+}
+
+func (se *savedErasures) Create(_ context.Context, erasure domain.Erasure) (string, error) {
+	se.erasures = append(se.erasures, erasure)
+
+	return fmt.Sprint(len(se.erasures)), nil
+}
+
+func TestErasePurgedPerson(t *testing.T) {
+	deletedAt := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+	obi := domain.StudentRecord{ID: "1", Email: "obi@jedi.rules", FileName: "rss.csv", DeletedAt: &deletedAt}
+	store := &personStore{
+		students: []domain.StudentRecord{obi, {ID: "2", Email: "luke@jedi.rules", FileName: "rss.csv"}},
+		history: []domain.HistoryEntry{
+			{StudentID: "1", Version: 1, Actor: domain.Actor{Via: domain.ViaImport, FileName: "rss.csv"}, Snapshot: obi},
+			{StudentID: "1", Version: 2, Action: domain.HistoryDeleted, Snapshot: obi},
+			{StudentID: "2", Version: 1, Snapshot: domain.StudentRecord{ID: "2", Email: "luke@jedi.rules"}},
+		},
+	}
+	storage := &storedFiles{files: map[string]domain.PutFileOptions{
+		"rss.csv": {
+			Body:        bytes.NewBufferString("Email,Name\nobi@jedi.rules,Obi-Wan\nluke@jedi.rules,Luke\n"),
+			ContentType: export.ContentType(export.CSV),
+		},
+	}}
+	erasures := &savedErasures{}
+	ps := &PrivacyService{
+		students: store,
+		schemas:  sourceSchemas{},
+		erasures: erasures,
+		storage:  storage,
+		hasher:   hash.NewSHA1Hasher("salt"),
+		now:      time.Now,
+	}
+	ctx := context.Background()
+
+	if purged, err := store.Purge(ctx, deletedAt.Add(time.Hour)); err != nil || purged != 1 {
+		t.Fatalf("Purge() = %v, %v, want 1 purged", purged, err)
+	}
+
+	erasure, err := ps.ErasePerson(ctx, "Obi@Jedi.Rules", domain.EraseInput{})
+	if err != nil {
+		t.Fatalf("ErasePerson() error = %v", err)
+	}
+	if erasure.Students != 0 || erasure.HistoryEntries != 2 {
+		t.Errorf("ErasePerson() erased %d students and %d history entries, want 0 and 2",
+			erasure.Students, erasure.HistoryEntries)
+	}
+	if want := []string{"rss.csv"}; !reflect.DeepEqual(erasure.Files, want) {
+		t.Errorf("ErasePerson() files = %v, want %v", erasure.Files, want)
+	}
+	if len(store.history) != 1 || store.history[0].StudentID != "2" {
+		t.Errorf("ErasePerson() kept history %v, want history of other students only", store.history)
+	}
+	if got, want := storedRows(t, storage.files["rss.csv"]), [][]string{{"Email", "Name"}, {"luke@jedi.rules", "Luke"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ErasePerson() file rows = %q, want %q", got, want)
+	}
+	if len(erasures.erasures) != 1 {
+		t.Errorf("ErasePerson() saved %d erasures, want 1", len(erasures.erasures))
+	}
+
+	if _, err := ps.ErasePerson(ctx, "obi@jedi.rules", domain.EraseInput{}); err != domain.ErrNotFound {
+		t.Errorf("ErasePerson() repeated error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
+
+type storedFiles struct {
+	ports.StorageService
+	files map[string]domain.PutFileOptions
+}
+
+func (sf *storedFiles) GetFile(_ context.Context, name string) (io.Reader, *domain.FileInfo, error) {
+	file, ok := sf.files[name]
+	if !ok {
+		return nil, nil, domain.ErrNotFound
+	}
+
+	return file.Body, &domain.FileInfo{Size: file.Size, ContentType: file.ContentType}, nil
+}
+
+func (sf *storedFiles) PutFile(_ context.Context, options domain.PutFileOptions) (string, error) {
+	sf.files[options.ObjectName] = options
+
+	return options.ObjectName, nil
+}
+
+type sourceSchemas struct {
+	ports.SchemaStore
+}
+
+func (sourceSchemas) FindAll(_ context.Context, _ domain.ListSchemasOptions) ([]domain.Schema, *domain.PageInfo, error) {
+	return nil, &domain.PageInfo{}, nil
+}
+
+func TestEraseFromFile(t *testing.T) {
+	xlsx := excelize.NewFile()
+	for i, row := range [][]interface{}{{"Email", "Name"}, {"obi@jedi.rules", "Obi-Wan"}, {"luke@jedi.rules", "Luke"}} {
+		if err := xlsx.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+1), &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	xlsxBody, err := xlsx.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		file        *domain.PutFileOptions
+		wantErased  bool
+		wantRows    [][]string
+		contentType string
+	}{
+		{
+			name: "missing file",
+		},
+		{
+			name: "csv file",
+			file: &domain.PutFileOptions{
+				Body:        bytes.NewBufferString("Email,Name\nobi@jedi.rules,Obi-Wan\nluke@jedi.rules,Luke\n"),
+				ContentType: "text/csv; charset=utf-8",
+			},
+			wantErased:  true,
+			wantRows:    [][]string{{"Email", "Name"}, {"luke@jedi.rules", "Luke"}},
+			contentType: "text/csv; charset=utf-8",
+		},
+		{
+			name:        "xlsx file",
+			file:        &domain.PutFileOptions{Body: xlsxBody, ContentType: export.ContentType(export.XLSX)},
+			wantErased:  true,
+			wantRows:    [][]string{{"Email", "Name"}, {"luke@jedi.rules", "Luke"}},
+			contentType: export.ContentType(export.XLSX),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &storedFiles{files: map[string]domain.PutFileOptions{}}
+			if tt.file != nil {
+				storage.files["jedi"] = *tt.file
+			}
+			ps := &PrivacyService{storage: storage, schemas: sourceSchemas{}}
+
+			ref := domain.ImportReference{FileName: "jedi", Source: domain.RSS}
+			erased, err := ps.eraseFromFile(context.Background(), ref, []string{"obi@jedi.rules"})
+			if err != nil {
+				t.Fatalf("eraseFromFile() error = %v", err)
+			}
+			if erased != tt.wantErased {
+				t.Errorf("eraseFromFile() = %v, want %v", erased, tt.wantErased)
+			}
+			if tt.file == nil {
+				if len(storage.files) > 0 {
+					t.Errorf("eraseFromFile() stored %v", storage.files)
+				}
+				return
+			}
+
+			stored := storage.files["jedi"]
+			if stored.ContentType != tt.contentType {
+				t.Errorf("eraseFromFile() content type = %q, want %q", stored.ContentType, tt.contentType)
+			}
+			if got := storedRows(t, stored); !reflect.DeepEqual(got, tt.wantRows) {
+				t.Errorf("eraseFromFile() rows = %q, want %q", got, tt.wantRows)
+			}
+		})
+	}
+}
+
+// storedRows reads rows of stored csv or xlsx file.
+func storedRows(t *testing.T, file domain.PutFileOptions) [][]string {
+	if isCSV(file.ContentType) {
+		rows, err := csv.NewReader(file.Body).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rows
+	}
+
+	f, err := excelize.OpenReader(file.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := f.GetRows("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rows
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
+)
+
+var _ ports.ProgressService = (*ProgressService)(nil)
+
+type ProgressService struct {
+	repo ports.StudentsStore
+	cfg  *config.Config
+}
+
+func NewProgressService(repo ports.StudentsStore, cfg *config.Config) *ProgressService {
+	return &ProgressService{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// Leaderboard ranks RSS students by the project score or by total score when project is not set.
+// Students without the project are not ranked.
+func (ps *ProgressService) Leaderboard(ctx context.Context, options domain.LeaderboardOptions) (*domain.Leaderboard, error) {
+	board := &domain.Leaderboard{
+		Project: options.Project,
+		Entries: []domain.LeaderboardEntry{},
+	}
+
+	if err := ps.iterateRSS(ctx, options.Filter, func(student *domain.StudentRecord) error {
+		score, ok := student.TotalScore, true
+		if options.Project != "" {
+			score, ok = projectScore(student, options.Project)
+		}
+		if ok {
+			board.Entries = append(board.Entries, domain.LeaderboardEntry{
+				StudentID: student.ID,
+				Email:     student.Email,
+				Name:      student.DisplayName(),
+				Score:     score,
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	rankEntries(board.Entries)
+	if options.Limit > 0 && len(board.Entries) > options.Limit {
+		board.Entries = board.Entries[:options.Limit]
+	}
+
+	return board, nil
+}
+
+// LateSubmissions lists projects finished after their deadlines, the most delayed first.
+func (ps *ProgressService) LateSubmissions(ctx context.Context, options domain.ProgressOptions) ([]domain.LateSubmission, error) {
+	late := []domain.LateSubmission{}
+	if err := ps.iterateRSS(ctx, options.Filter, func(student *domain.StudentRecord) error {
+		for i := range student.Projects {
+			p := &student.Projects[i]
+			if options.Project != "" && p.Name != options.Project {
+				continue
+			}
+			finished, deadline, isLate := p.IsLate()
+			if !isLate {
+				continue
+			}
+			late = append(late, domain.LateSubmission{
+				StudentID:  student.ID,
+				Email:      student.Email,
+				Name:       student.DisplayName(),
+				Project:    p.Name,
+				Score:      p.Score,
+				FinishedAt: finished,
+				Deadline:   deadline,
+				DelayHours: finished.Sub(deadline).Hours(),
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(late, func(i, j int) bool {
+		return late[i].DelayHours > late[j].DelayHours
+	})
+
+	return late, nil
+}
+
+// ListProgress calculates submitted, late and missing projects of RSS students and flags at-risk ones.
+func (ps *ProgressService) ListProgress(ctx context.Context, options domain.ProgressOptions) ([]domain.StudentProgress, error) {
+	required, err := requiredProjects(ctx, ps.repo, ps.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := []domain.StudentProgress{}
+	if err := ps.iterateRSS(ctx, options.Filter, func(student *domain.StudentRecord) error {
+		sp := studentProgress(student, required, ps.cfg.Progress.AtRisk)
+		if !options.AtRisk || sp.AtRisk {
+			progress = append(progress, *sp)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return progress, nil
+}
+
+// GetStudentProgress calculates projects progress of RSS student.
+func (ps *ProgressService) GetStudentProgress(ctx context.Context, id string) (*domain.StudentProgress, error) {
+	student, err := ps.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if student.Source != domain.RSS {
+		return nil, domain.ErrNotFound
+	}
+
+	required, err := requiredProjects(ctx, ps.repo, ps.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return studentProgress(student, required, ps.cfg.Progress.AtRisk), nil
+}
+
+func (ps *ProgressService) iterateRSS(ctx context.Context, filter query.Expr, fn func(student *domain.StudentRecord) error) error {
+	return ps.repo.Iterate(ctx, domain.ListStudentsOptions{
+		Source: domain.RSS,
+		Filter: filter,
+	}, fn)
+}
+
+// requiredProjects returns configured required projects, or names of all projects of RSS students sorted.
+func requiredProjects(ctx context.Context, repo ports.StudentsStore, cfg *config.Config) ([]string, error) {
+	if len(cfg.Progress.RequiredProjects) > 0 {
+		return cfg.Progress.RequiredProjects, nil
+	}
+
+	names := map[string]bool{}
+	if err := repo.Iterate(ctx, domain.ListStudentsOptions{Source: domain.RSS}, func(student *domain.StudentRecord) error {
+		for _, p := range student.Projects {
+			if p.Name != "" {
+				names[p.Name] = true
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	required := make([]string, 0, len(names))
+	for name := range names {
+		required = append(required, name)
+	}
+	sort.Strings(required)
+
+	return required, nil
+}
+
+// studentProgress compares student projects with required ones and checks at-risk rules.
+func studentProgress(student *domain.StudentRecord, required []string, rules config.AtRiskRules) *domain.StudentProgress {
+	sp := &domain.StudentProgress{
+		StudentID:     student.ID,
+		Email:         student.Email,
+		Name:          student.DisplayName(),
+		TotalScore:    student.ProjectsScore(),
+		Submitted:     []string{},
+		Late:          []string{},
+		Missing:       []string{},
+		AtRiskReasons: []string{},
+	}
+
+	submitted := map[string]bool{}
+	for i := range student.Projects {
+		p := &student.Projects[i]
+		if !p.IsSubmitted() {
+			continue
+		}
+		submitted[p.Name] = true
+		sp.Submitted = append(sp.Submitted, p.Name)
+		if _, _, isLate := p.IsLate(); isLate {
+			sp.Late = append(sp.Late, p.Name)
+		}
+	}
+
+	for _, name := range required {
+		if !submitted[name] {
+			sp.Missing = append(sp.Missing, name)
+		}
+	}
+
+	if len(required) > 0 {
+		sp.AverageScore = float64(sp.TotalScore) / float64(len(required))
+	}
+
+	if rules.MinAverageScore != nil && sp.AverageScore < *rules.MinAverageScore {
+		sp.AtRiskReasons = append(sp.AtRiskReasons, domain.RiskLowScore)
+	}
+	if rules.MaxLateProjects != nil && len(sp.Late) > *rules.MaxLateProjects {
+		sp.AtRiskReasons = append(sp.AtRiskReasons, domain.RiskLateProjects)
+	}
+	if rules.MaxMissingProjects != nil && len(sp.Missing) > *rules.MaxMissingProjects {
+		sp.AtRiskReasons = append(sp.AtRiskReasons, domain.RiskMissingProjects)
+	}
+	sp.AtRisk = len(sp.AtRiskReasons) > 0
+
+	return sp
+}
+
+// rankEntries sorts entries by score descending and ranks them, equal scores share the rank
+// and the next rank is skipped for each of them.
+func rankEntries(entries []domain.LeaderboardEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Score > entries[j].Score
+	})
+
+	for i := range entries {
+		if i > 0 && entries[i].Score == entries[i-1].Score {
+			entries[i].Rank = entries[i-1].Rank
+			continue
+		}
+		entries[i].Rank = i + 1
+	}
+}
+
+// projectScore returns score of the student project with name.
+func projectScore(student *domain.StudentRecord, name string) (int, bool) {
+	for _, p := range student.Projects {
+		if p.Name == name {
+			return p.Score, true
+		}
+	}
+
+	return 0, false
+}
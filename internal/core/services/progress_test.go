@@ -0,0 +1,112 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+func TestRankEntries(t *testing.T) {
+	entries := []domain.LeaderboardEntry{
+		{StudentID: "1", Score: 50},
+		{StudentID: "2", Score: 90},
+		{StudentID: "3", Score: 70},
+		{StudentID: "4", Score: 90},
+		{StudentID: "5", Score: 10},
+	}
+
+	rankEntries(entries)
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, fmt.Sprintf("%s:%d", e.StudentID, e.Rank))
+	}
+	want := []string{"2:1", "4:1", "3:3", "1:4", "5:5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rankEntries() = %v, want %v", got, want)
+	}
+}
+
+func TestStudentProgress(t *testing.T) {
+	minAverage := 50.0
+	maxLate := 0
+	maxMissing := 1
+
+	student := &domain.StudentRecord{
+		ID:     "1",
+		Source: domain.RSS,
+		Email:  "luke@jedi.rules",
+		StudentRSS: domain.StudentRSS{
+			FirstName: "Luke",
+			LastName:  "Skywalker",
+			Projects: []domain.Project{
+				{Name: "Songbird", Score: 100, FinishedAt: "2022-10-01", Deadline: "2022-10-05"},
+				{Name: "Virtual Keyboard", Score: 40, FinishedAt: "2022-10-20", Deadline: "2022-10-10"},
+				{Name: "Gem Puzzle"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		required    []string
+		rules       config.AtRiskRules
+		wantLate    []string
+		wantMissing []string
+		wantAverage float64
+		wantReasons []string
+	}{
+		{
+			name:        "noRules",
+			required:    []string{"Gem Puzzle", "Songbird", "Virtual Keyboard"},
+			wantLate:    []string{"Virtual Keyboard"},
+			wantMissing: []string{"Gem Puzzle"},
+			wantAverage: 140.0 / 3,
+			wantReasons: []string{},
+		},
+		{
+			name:     "allRulesBroken",
+			required: []string{"Gem Puzzle", "Songbird", "Virtual Keyboard", "Shelter"},
+			rules: config.AtRiskRules{
+				MinAverageScore:    &minAverage,
+				MaxLateProjects:    &maxLate,
+				MaxMissingProjects: &maxMissing,
+			},
+			wantLate:    []string{"Virtual Keyboard"},
+			wantMissing: []string{"Gem Puzzle", "Shelter"},
+			wantAverage: 35,
+			wantReasons: []string{domain.RiskLowScore, domain.RiskLateProjects, domain.RiskMissingProjects},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := studentProgress(student, tt.required, tt.rules)
+
+			if got.Name != "Luke Skywalker" || got.TotalScore != 140 {
+				t.Errorf("studentProgress() name = %q, total = %d", got.Name, got.TotalScore)
+			}
+			if !reflect.DeepEqual(got.Submitted, []string{"Songbird", "Virtual Keyboard"}) {
+				t.Errorf("studentProgress() submitted = %v", got.Submitted)
+			}
+			if !reflect.DeepEqual(got.Late, tt.wantLate) {
+				t.Errorf("studentProgress() late = %v, want %v", got.Late, tt.wantLate)
+			}
+			if !reflect.DeepEqual(got.Missing, tt.wantMissing) {
+				t.Errorf("studentProgress() missing = %v, want %v", got.Missing, tt.wantMissing)
+			}
+			if got.AverageScore != tt.wantAverage {
+				t.Errorf("studentProgress() average = %v, want %v", got.AverageScore, tt.wantAverage)
+			}
+			if !reflect.DeepEqual(got.AtRiskReasons, tt.wantReasons) {
+				t.Errorf("studentProgress() reasons = %v, want %v", got.AtRiskReasons, tt.wantReasons)
+			}
+			if got.AtRisk != (len(tt.wantReasons) > 0) {
+				t.Errorf("studentProgress() at risk = %v", got.AtRisk)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+)
+
+var _ ports.QualityService = (*QualityService)(nil)
+
+// QualityService reports data quality issues of imported students, so spreadsheets which need fixing are known.
+type QualityService struct {
+	repo ports.StudentsStore
+	cfg  *config.Config
+}
+
+func NewQualityService(repo ports.StudentsStore, cfg *config.Config) *QualityService {
+	return &QualityService{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// studentQuality is a student with its issues, duplicate emails are counted after all students are read.
+type studentQuality struct {
+	source     string
+	fileName   string
+	importedAt time.Time
+	email      string
+	metrics    domain.QualityMetrics
+}
+
+// Report counts issues of students not in trash in total, per source and per import file.
+// Students are read one by one, so encrypted fields are checked decrypted.
+func (qs *QualityService) Report(ctx context.Context, options domain.QualityOptions) (*domain.QualityReport, error) {
+	var students []studentQuality
+	emails := make(map[string]map[string]int)
+
+	if err := qs.repo.Iterate(ctx, domain.ListStudentsOptions{Source: options.Source}, func(student *domain.StudentRecord) error {
+		students = append(students, studentQuality{
+			source:     student.Source,
+			fileName:   student.FileName,
+			importedAt: student.ImportedAt,
+			email:      student.Email,
+			metrics:    studentIssues(student, qs.cfg.Quality),
+		})
+		if student.Email != "" {
+			if emails[student.Source] == nil {
+				emails[student.Source] = make(map[string]int)
+			}
+			emails[student.Source][student.Email]++
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	report := &domain.QualityReport{
+		BySource: []domain.SourceQuality{},
+		ByImport: []domain.ImportQuality{},
+	}
+	bySource := make(map[string]*domain.SourceQuality)
+	byImport := make(map[string]*domain.ImportQuality)
+
+	for i := range students {
+		s := &students[i]
+		if emails[s.source][s.email] > 1 {
+			s.metrics.DuplicateEmails++
+		}
+		if s.metrics.HasIssues() {
+			s.metrics.StudentsWithIssues = 1
+		}
+
+		report.Total.Add(s.metrics)
+
+		source, ok := bySource[s.source]
+		if !ok {
+			source = &domain.SourceQuality{Source: s.source}
+			bySource[s.source] = source
+		}
+		source.Add(s.metrics)
+
+		file, ok := byImport[s.fileName]
+		if !ok {
+			file = &domain.ImportQuality{FileName: s.fileName, Source: s.source}
+			byImport[s.fileName] = file
+		}
+		if s.importedAt.After(file.ImportedAt) {
+			file.ImportedAt = s.importedAt
+		}
+		file.Add(s.metrics)
+	}
+
+	for _, source := range bySource {
+		report.BySource = append(report.BySource, *source)
+	}
+	sort.Slice(report.BySource, func(i, j int) bool {
+		return report.BySource[i].Source < report.BySource[j].Source
+	})
+
+	for _, file := range byImport {
+		report.ByImport = append(report.ByImport, *file)
+	}
+	sort.Slice(report.ByImport, func(i, j int) bool {
+		a, b := report.ByImport[i], report.ByImport[j]
+		if !a.ImportedAt.Equal(b.ImportedAt) {
+			return a.ImportedAt.After(b.ImportedAt)
+		}
+		return a.FileName < b.FileName
+	})
+
+	return report, nil
+}
+
+// studentIssues counts issues of a single student except duplicate emails.
+func studentIssues(student *domain.StudentRecord, qc config.QualityConfig) domain.QualityMetrics {
+	m := domain.QualityMetrics{Students: 1}
+
+	if student.Email == "" {
+		m.MissingEmails++
+	} else if !validEmail(student.Email) {
+		m.InvalidEmails++
+	}
+
+	if qc.MaxScore != 0 {
+		for _, p := range student.Projects {
+			if p.Score < qc.MinScore || p.Score > qc.MaxScore {
+				m.OutOfRangeScores++
+			}
+		}
+	}
+
+	dates := []string{student.ApplicationDate, student.JoinDate}
+	for _, p := range student.Projects {
+		dates = append(dates, p.FinishedAt, p.Deadline)
+	}
+	for _, date := range dates {
+		if date != "" && domain.ParseDate(date) == nil {
+			m.UnparsableDates++
+		}
+	}
+
+	for _, field := range qc.RequiredFields[student.Source] {
+		if !fieldEmpty(student, field) {
+			continue
+		}
+		if m.EmptyFields == nil {
+			m.EmptyFields = make(map[string]int64)
+		}
+		m.EmptyFields[field]++
+		m.EmptyRequiredFields++
+	}
+
+	return m
+}
+
+// validEmail reports whether email is a bare address without a display name.
+func validEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+
+	return err == nil && address.Address == email
+}
+
+// fieldEmpty reports whether student has empty or blank value of config.QualityFields field.
+func fieldEmpty(student *domain.StudentRecord, field string) bool {
+	var value string
+	switch field {
+	case "student_rss.first_name":
+		value = student.FirstName
+	case "student_rss.last_name":
+		value = student.LastName
+	case "student_rss.application_date":
+		value = student.ApplicationDate
+	case "student_rss.projects":
+		return len(student.Projects) == 0
+	case "student_wac.full_name":
+		value = student.StudentWAC.FullName
+	case "student_wac.join_date":
+		value = student.JoinDate
+	case "student_wac.location":
+		value = student.Location
+	case "student_wac.company":
+		value = student.Company
+	case "student_wac.position":
+		value = student.Position
+	case "student_wac.membership_type":
+		value = student.MembershipType
+	}
+
+	return strings.TrimSpace(value) == ""
+}
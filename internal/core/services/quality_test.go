@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+)
+
+var testQualityConfig = config.QualityConfig{
+	MinScore: 0,
+	MaxScore: 100,
+	RequiredFields: map[string][]string{
+		domain.RSS: {"student_rss.first_name", "student_rss.last_name"},
+		domain.WAC: {"student_wac.full_name"},
+	},
+}
+
+// iteratedStore iterates over students.
+type iteratedStore struct {
+	ports.StudentsStore
+	students []domain.StudentRecord
+}
+
+func (is *iteratedStore) Iterate(_ context.Context, _ domain.ListStudentsOptions, fn func(student *domain.StudentRecord) error) error {
+	for i := range is.students {
+		if err := fn(&is.students[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestStudentIssues(t *testing.T) {
+	tests := []struct {
+		name    string
+		student domain.StudentRecord
+		want    domain.QualityMetrics
+	}{
+		{
+			name: "clean",
+			student: domain.StudentRecord{Source: domain.RSS, Email: "luke@jedi.rules", StudentRSS: domain.StudentRSS{
+				FirstName: "Luke", LastName: "Skywalker", ApplicationDate: "2022-10-01",
+				Projects: []domain.Project{{Name: "Songbird", Score: 100, FinishedAt: "2022-10-05"}},
+			}},
+			want: domain.QualityMetrics{Students: 1},
+		},
+		{
+			name:    "missing email and required fields",
+			student: domain.StudentRecord{Source: domain.RSS, StudentRSS: domain.StudentRSS{FirstName: "Luke", LastName: " "}},
+			want: domain.QualityMetrics{
+				Students: 1, MissingEmails: 1, EmptyRequiredFields: 1,
+				EmptyFields: map[string]int64{"student_rss.last_name": 1},
+			},
+		},
+		{
+			name:    "invalid email",
+			student: domain.StudentRecord{Source: domain.WAC, Email: "Han Solo <han@falcon.ship>", StudentWAC: domain.StudentWAC{FullName: "Han Solo"}},
+			want:    domain.QualityMetrics{Students: 1, InvalidEmails: 1},
+		},
+		{
+			name: "scores and dates",
+			student: domain.StudentRecord{Source: domain.RSS, Email: "leia@alderaan.gov", StudentRSS: domain.StudentRSS{
+				FirstName: "Leia", LastName: "Organa", ApplicationDate: "yesterday",
+				Projects: []domain.Project{
+					{Name: "Songbird", Score: 120, FinishedAt: "soon", Deadline: "2022-10-10"},
+					{Name: "Gem Puzzle", Score: -5},
+				},
+			}},
+			want: domain.QualityMetrics{Students: 1, OutOfRangeScores: 2, UnparsableDates: 2},
+		},
+		{
+			name:    "no required fields of unknown source",
+			student: domain.StudentRecord{Source: "CSV", Email: "r2d2@droids.net"},
+			want:    domain.QualityMetrics{Students: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := studentIssues(&tt.student, testQualityConfig); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("studentIssues() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualityReport(t *testing.T) {
+	earlier := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(24 * time.Hour)
+
+	store := &iteratedStore{students: []domain.StudentRecord{
+		{Source: domain.RSS, Email: "luke@jedi.rules", FileName: "rss-1.xlsx", ImportedAt: earlier,
+			StudentRSS: domain.StudentRSS{FirstName: "Luke", LastName: "Skywalker"}},
+		{Source: domain.RSS, Email: "luke@jedi.rules", FileName: "rss-2.xlsx", ImportedAt: later,
+			StudentRSS: domain.StudentRSS{FirstName: "Luke"}},
+		{Source: domain.WAC, Email: "luke@jedi.rules", FileName: "wac.xlsx", ImportedAt: earlier,
+			StudentWAC: domain.StudentWAC{FullName: "Luke Skywalker"}},
+	}}
+	qs := NewQualityService(store, &config.Config{Quality: testQualityConfig})
+
+	report, err := qs.Report(context.Background(), domain.QualityOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantTotal := domain.QualityMetrics{
+		Students: 3, StudentsWithIssues: 2, DuplicateEmails: 2, EmptyRequiredFields: 1,
+		EmptyFields: map[string]int64{"student_rss.last_name": 1},
+	}
+	if !reflect.DeepEqual(report.Total, wantTotal) {
+		t.Errorf("Report() total = %+v, want %+v", report.Total, wantTotal)
+	}
+
+	var sources []string
+	for _, s := range report.BySource {
+		sources = append(sources, s.Source)
+	}
+	if want := []string{domain.RSS, domain.WAC}; !reflect.DeepEqual(sources, want) {
+		t.Errorf("Report() sources = %v, want %v", sources, want)
+	}
+	if wac := report.BySource[1]; wac.Students != 1 || wac.HasIssues() {
+		t.Errorf("Report() WAC metrics = %+v, want a student without issues", wac.QualityMetrics)
+	}
+
+	var files []string
+	for _, f := range report.ByImport {
+		files = append(files, f.FileName)
+	}
+	if want := []string{"rss-2.xlsx", "rss-1.xlsx", "wac.xlsx"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("Report() imports = %v, want %v", files, want)
+	}
+	if latest := report.ByImport[0]; latest.Source != domain.RSS || latest.EmptyRequiredFields != 1 || latest.DuplicateEmails != 1 {
+		t.Errorf("Report() latest import = %+v, want an RSS import with an empty field and a duplicate email", latest)
+	}
+}
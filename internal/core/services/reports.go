@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"github.com/abdukhashimov/student_aggregator/pkg/export"
+)
+
+const (
+	membersGroup    = "community members"
+	nonMembersGroup = "not community members"
+)
+
+// attendanceBuckets group students by attended events, max is inclusive and negative for unbounded bucket.
+var attendanceBuckets = []struct {
+	name     string
+	min, max int
+}{
+	{"0", 0, 0},
+	{"1-2", 1, 2},
+	{"3-5", 3, 5},
+	{"6+", 6, -1},
+}
+
+// engagementMetrics are metrics of matched students correlated in the report.
+var engagementMetrics = []struct {
+	x, y string
+	xv   func(r *domain.EngagementRow) float64
+	yv   func(r *domain.EngagementRow) float64
+}{
+	{"attended_events", "total_score", attendedEvents, totalScore},
+	{"attended_events", "completion_rate", attendedEvents, completionRate},
+	{"registered_not_visited", "total_score", registeredNotVisited, totalScore},
+	{"registered_not_visited", "completion_rate", registeredNotVisited, completionRate},
+}
+
+var _ ports.ReportsService = (*ReportsService)(nil)
+
+type ReportsService struct {
+	repo ports.StudentsStore
+	cfg  *config.Config
+}
+
+func NewReportsService(repo ports.StudentsStore, cfg *config.Config) *ReportsService {
+	return &ReportsService{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// EngagementReport joins RSS students with WAC members by normalized email.
+// When a person has several WAC records the most recently imported one is used.
+func (rs *ReportsService) EngagementReport(ctx context.Context) (*domain.EngagementReport, error) {
+	required, err := requiredProjects(ctx, rs.repo, rs.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[string]*domain.StudentRecord{}
+	if err := rs.repo.Iterate(ctx, domain.ListStudentsOptions{Source: domain.WAC}, func(student *domain.StudentRecord) error {
+		email := domain.NormalizeEmail(student.Email)
+		if m, ok := members[email]; email == "" || ok && m.ImportedAt.After(student.ImportedAt) {
+			return nil
+		}
+		members[email] = student
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	rows := []domain.EngagementRow{}
+	if err := rs.repo.Iterate(ctx, domain.ListStudentsOptions{Source: domain.RSS}, func(student *domain.StudentRecord) error {
+		rows = append(rows, engagementRow(student, members[domain.NormalizeEmail(student.Email)], required))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	report := buildEngagementReport(rows)
+	report.WACMembers = len(members)
+
+	return report, nil
+}
+
+// ExportEngagementReport writes engagement report to w as XLSX workbook with summary, groups and students sheets.
+func (rs *ReportsService) ExportEngagementReport(ctx context.Context, w io.Writer) error {
+	report, err := rs.EngagementReport(ctx)
+	if err != nil {
+		return err
+	}
+
+	xw, err := export.NewXLSXWriter(w, "Summary")
+	if err != nil {
+		return err
+	}
+
+	summary := [][]interface{}{
+		{"generated_at", formatTime(report.GeneratedAt)},
+		{"rss_students", report.RSSStudents},
+		{"wac_members", report.WACMembers},
+		{"matched", report.Matched},
+		{},
+		{"x", "y", "samples", "coefficient"},
+	}
+	for _, c := range report.Correlations {
+		var coefficient interface{}
+		if c.Coefficient != nil {
+			coefficient = *c.Coefficient
+		}
+		summary = append(summary, []interface{}{c.X, c.Y, c.Samples, coefficient})
+	}
+	if err := writeRows(xw, summary); err != nil {
+		return err
+	}
+
+	if err := xw.NewSheet("Groups"); err != nil {
+		return err
+	}
+	groups := [][]interface{}{{"grouping", "group", "students", "avg_score", "completion_rate"}}
+	for _, g := range report.ByMembership {
+		groups = append(groups, []interface{}{"membership", g.Group, g.Students, g.AvgScore, g.CompletionRate})
+	}
+	for _, g := range report.ByAttendance {
+		groups = append(groups, []interface{}{"attended_events", g.Group, g.Students, g.AvgScore, g.CompletionRate})
+	}
+	if err := writeRows(xw, groups); err != nil {
+		return err
+	}
+
+	if err := xw.NewSheet("Students"); err != nil {
+		return err
+	}
+	students := [][]interface{}{{
+		"student_id", "email", "name", "total_score", "submitted", "completion_rate",
+		"community_member", "attended_events", "registered_not_visited", "registered",
+	}}
+	for _, r := range report.Students {
+		students = append(students, []interface{}{
+			r.StudentID, r.Email, r.Name, r.TotalScore, r.Submitted, r.CompletionRate,
+			r.CommunityMember, r.AttendedEvents, r.RegisteredNotVisited, r.Registered,
+		})
+	}
+	if err := writeRows(xw, students); err != nil {
+		return err
+	}
+
+	return xw.Close()
+}
+
+func engagementRow(student, member *domain.StudentRecord, required []string) domain.EngagementRow {
+	row := domain.EngagementRow{
+		StudentID:  student.ID,
+		Email:      student.Email,
+		Name:       student.DisplayName(),
+		TotalScore: student.ProjectsScore(),
+	}
+
+	submitted := map[string]bool{}
+	for i := range student.Projects {
+		if student.Projects[i].IsSubmitted() {
+			row.Submitted++
+			submitted[student.Projects[i].Name] = true
+		}
+	}
+
+	completed := 0
+	for _, name := range required {
+		if submitted[name] {
+			completed++
+		}
+	}
+	if len(required) > 0 {
+		row.CompletionRate = float64(completed) / float64(len(required))
+	}
+
+	if member != nil {
+		row.CommunityMember = true
+		row.AttendedEvents = member.AttendedEvents
+		row.RegisteredNotVisited = member.RegisteredNotVisited
+		row.Registered = member.Registered
+	}
+
+	return row
+}
+
+// buildEngagementReport groups and correlates report rows, students are ordered by total score descending.
+func buildEngagementReport(rows []domain.EngagementRow) *domain.EngagementReport {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].TotalScore > rows[j].TotalScore
+	})
+
+	report := &domain.EngagementReport{
+		GeneratedAt:  time.Now().UTC(),
+		RSSStudents:  len(rows),
+		Correlations: []domain.Correlation{},
+		Students:     rows,
+	}
+
+	var members, nonMembers []*domain.EngagementRow
+	buckets := make([][]*domain.EngagementRow, len(attendanceBuckets))
+	for i := range rows {
+		r := &rows[i]
+		if !r.CommunityMember {
+			nonMembers = append(nonMembers, r)
+			continue
+		}
+
+		members = append(members, r)
+		for b, bucket := range attendanceBuckets {
+			if r.AttendedEvents >= bucket.min && (bucket.max < 0 || r.AttendedEvents <= bucket.max) {
+				buckets[b] = append(buckets[b], r)
+				break
+			}
+		}
+	}
+	report.Matched = len(members)
+
+	report.ByMembership = []domain.EngagementGroup{
+		engagementGroup(membersGroup, members),
+		engagementGroup(nonMembersGroup, nonMembers),
+	}
+	for b, bucket := range attendanceBuckets {
+		report.ByAttendance = append(report.ByAttendance, engagementGroup(bucket.name, buckets[b]))
+	}
+
+	for _, m := range engagementMetrics {
+		xs := make([]float64, len(members))
+		ys := make([]float64, len(members))
+		for i, r := range members {
+			xs[i], ys[i] = m.xv(r), m.yv(r)
+		}
+		report.Correlations = append(report.Correlations, domain.Correlation{
+			X:           m.x,
+			Y:           m.y,
+			Samples:     len(members),
+			Coefficient: pearson(xs, ys),
+		})
+	}
+
+	return report
+}
+
+func engagementGroup(name string, rows []*domain.EngagementRow) domain.EngagementGroup {
+	group := domain.EngagementGroup{Group: name, Students: len(rows)}
+	if len(rows) == 0 {
+		return group
+	}
+
+	for _, r := range rows {
+		group.AvgScore += float64(r.TotalScore)
+		group.CompletionRate += r.CompletionRate
+	}
+	group.AvgScore /= float64(len(rows))
+	group.CompletionRate /= float64(len(rows))
+
+	return group
+}
+
+// pearson calculates Pearson correlation coefficient of xs and ys.
+// Returns nil for less than two samples or zero variance.
+func pearson(xs, ys []float64) *float64 {
+	n := float64(len(xs))
+	if len(xs) < 2 || len(xs) != len(ys) {
+		return nil
+	}
+
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return nil
+	}
+
+	r := cov / math.Sqrt(varX*varY)
+
+	return &r
+}
+
+func writeRows(w export.Writer, rows [][]interface{}) error {
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func attendedEvents(r *domain.EngagementRow) float64       { return float64(r.AttendedEvents) }
+func registeredNotVisited(r *domain.EngagementRow) float64 { return float64(r.RegisteredNotVisited) }
+func totalScore(r *domain.EngagementRow) float64           { return float64(r.TotalScore) }
+func completionRate(r *domain.EngagementRow) float64       { return r.CompletionRate }
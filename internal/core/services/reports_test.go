@@ -0,0 +1,96 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+func TestPearson(t *testing.T) {
+	tests := []struct {
+		name   string
+		xs, ys []float64
+		want   *float64
+	}{
+		{"positive", []float64{1, 2, 3, 4}, []float64{10, 20, 30, 40}, float64Ptr(1)},
+		{"negative", []float64{1, 2, 3}, []float64{3, 2, 1}, float64Ptr(-1)},
+		{"partial", []float64{1, 2, 3, 4}, []float64{1, 3, 2, 4}, float64Ptr(0.8)},
+		{"constant", []float64{1, 1, 1}, []float64{1, 2, 3}, nil},
+		{"singleSample", []float64{1}, []float64{1}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pearson(tt.xs, tt.ys)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("pearson() = %v, want %v", got, tt.want)
+			}
+			if got != nil && math.Abs(*got-*tt.want) > 1e-9 {
+				t.Errorf("pearson() = %v, want %v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildEngagementReport(t *testing.T) {
+	required := []string{"Songbird", "Gem Puzzle"}
+	members := map[string]*domain.StudentRecord{
+		"luke@jedi.rules": {Source: domain.WAC, StudentWAC: domain.StudentWAC{AttendedEvents: 6, Registered: 7}},
+		"leia@rebels.org": {Source: domain.WAC, StudentWAC: domain.StudentWAC{AttendedEvents: 1, RegisteredNotVisited: 3}},
+	}
+	students := []domain.StudentRecord{
+		{ID: "1", Email: "luke@jedi.rules", StudentRSS: domain.StudentRSS{Projects: []domain.Project{
+			{Name: "Songbird", Score: 100}, {Name: "Gem Puzzle", Score: 80},
+		}}},
+		{ID: "2", Email: "leia@rebels.org", StudentRSS: domain.StudentRSS{Projects: []domain.Project{
+			{Name: "Songbird", Score: 40},
+		}}},
+		{ID: "3", Email: "han@falcon.space", StudentRSS: domain.StudentRSS{Projects: []domain.Project{
+			{Name: "Gem Puzzle", Score: 10},
+		}}},
+	}
+
+	var rows []domain.EngagementRow
+	for i := range students {
+		rows = append(rows, engagementRow(&students[i], members[students[i].Email], required))
+	}
+
+	report := buildEngagementReport(rows)
+
+	if report.RSSStudents != 3 || report.Matched != 2 {
+		t.Errorf("buildEngagementReport() students = %d, matched = %d, want 3 and 2", report.RSSStudents, report.Matched)
+	}
+
+	if report.Students[0].StudentID != "1" || report.Students[0].CompletionRate != 1 {
+		t.Errorf("buildEngagementReport() first student = %+v, want student 1 with full completion", report.Students[0])
+	}
+
+	wantMembership := []domain.EngagementGroup{
+		{Group: membersGroup, Students: 2, AvgScore: 110, CompletionRate: 0.75},
+		{Group: nonMembersGroup, Students: 1, AvgScore: 10, CompletionRate: 0.5},
+	}
+	for i, want := range wantMembership {
+		if report.ByMembership[i] != want {
+			t.Errorf("buildEngagementReport() membership group = %+v, want %+v", report.ByMembership[i], want)
+		}
+	}
+
+	wantAttendance := map[string]int{"0": 0, "1-2": 1, "3-5": 0, "6+": 1}
+	for _, g := range report.ByAttendance {
+		if g.Students != wantAttendance[g.Group] {
+			t.Errorf("buildEngagementReport() attendance group %q has %d students, want %d", g.Group, g.Students, wantAttendance[g.Group])
+		}
+	}
+
+	if len(report.Correlations) != len(engagementMetrics) {
+		t.Fatalf("buildEngagementReport() correlations = %v", report.Correlations)
+	}
+	if c := report.Correlations[0]; c.Coefficient == nil || *c.Coefficient != 1 {
+		t.Errorf("buildEngagementReport() attended events and score correlation = %v, want 1", c.Coefficient)
+	}
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
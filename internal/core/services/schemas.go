@@ -32,6 +32,9 @@ func (ss *SchemaService) NewSchema(ctx context.Context, input domain.NewSchemaIn
 		SchemaType: input.SchemaType,
 		Headers:    input.Headers,
 		Fields:     input.Fields,
+		Lookups:    input.Lookups,
+		Options:    input.Options,
+		GroupBy:    input.GroupBy,
 	})
 	if err != nil {
 		return nil, err
@@ -42,10 +45,8 @@ func (ss *SchemaService) NewSchema(ctx context.Context, input domain.NewSchemaIn
 	return schema, err
 }
 
-func (ss *SchemaService) ListSchemas(ctx context.Context) ([]domain.Schema, error) {
-	schemas, err := ss.repo.FindAll(ctx)
-
-	return schemas, err
+func (ss *SchemaService) ListSchemas(ctx context.Context, options domain.ListSchemasOptions) ([]domain.Schema, *domain.PageInfo, error) {
+	return ss.repo.FindAll(ctx, options)
 }
 
 func (ss *SchemaService) GetSchemaById(ctx context.Context, id string) (*domain.Schema, error) {
@@ -54,13 +55,14 @@ func (ss *SchemaService) GetSchemaById(ctx context.Context, id string) (*domain.
 	return schema, err
 }
 
-func (ss *SchemaService) UpdateSchema(ctx context.Context, id string, input domain.UpdateSchemaInput) (*domain.Schema, error) {
+// UpdateSchema updates schema if it has revision, or regardless of revision when it is nil.
+func (ss *SchemaService) UpdateSchema(ctx context.Context, id string, input domain.UpdateSchemaInput, revision *int) (*domain.Schema, error) {
 	input.Slug = nil
 	if input.Name != nil {
 		slug := getSlug(*input.Name)
 		input.Slug = &slug
 	}
-	err := ss.repo.Update(ctx, id, input)
+	err := ss.repo.Update(ctx, id, input, revision)
 	if err != nil {
 		return nil, err
 	}
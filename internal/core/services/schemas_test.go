@@ -122,7 +122,7 @@ var schemasTestCaseGroup = []SchemasTestCaseGroup{
 	{
 		name: "ListSchemas",
 		executeMethod: func(ctx context.Context, s *SchemaService, inputID string, input interface{}, expectedError error) error {
-			schemasList, err := s.ListSchemas(ctx)
+			schemasList, _, err := s.ListSchemas(ctx, domain.ListSchemasOptions{})
 
 			if expectedError != nil {
 				if expectedError != err {
@@ -205,7 +205,7 @@ var schemasTestCaseGroup = []SchemasTestCaseGroup{
 			if !ok {
 				return errors.New("invalid input type")
 			}
-			updatedSchema, err := s.UpdateSchema(ctx, inputID, in)
+			updatedSchema, err := s.UpdateSchema(ctx, inputID, in, nil)
 
 			if expectedError != nil {
 				if expectedError != err {
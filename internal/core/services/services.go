@@ -7,25 +7,59 @@ import (
 )
 
 type Services struct {
-	Users      ports.UsersService
-	Schemas    ports.SchemaService
-	Students   ports.StudentsService
-	Storage    ports.StorageService
-	Aggregator ports.AggregatorService
+	Users       ports.UsersService
+	Schemas     ports.SchemaService
+	Students    ports.StudentsService
+	Storage     ports.StorageService
+	Aggregator  ports.AggregatorService
+	Duplicates  ports.DuplicatesService
+	People      ports.PeopleService
+	Stats       ports.StatsService
+	Progress    ports.ProgressService
+	Reports     ports.ReportsService
+	Trash       ports.TrashService
+	Annotations ports.AnnotationsService
+	Workflow    ports.WorkflowService
+	Privacy     ports.PrivacyService
+	Quality     ports.QualityService
 }
 
-func NewServices(repos *repository.Repositories, cfg *config.Config) *Services {
+func NewServices(repos *repository.Repositories, cfg *config.Config) (*Services, error) {
+	if err := validateWorkflow(cfg); err != nil {
+		return nil, err
+	}
+
 	usersService := NewUsersService(repos.Users, cfg)
 	schemasService := NewSchemaService(repos.Schemas, cfg)
-	studentsService := NewStudentsService(repos.Students, cfg)
+	workflowService := NewWorkflowService(repos.Students, cfg)
+	studentsService := NewStudentsService(repos.Students, repos.Schemas, workflowService, cfg)
 	storageService := NewStorageService(cfg)
-	parserService := NewAggregatorService(repos.Students, repos.Schemas, storageService)
+	parserService := NewAggregatorService(repos.Students, repos.Schemas, storageService, workflowService)
+	duplicatesService := NewDuplicatesService(repos.Students, cfg)
+	peopleService := NewPeopleService(repos.Students, cfg)
+	statsService := NewStatsService(repos.Stats, cfg)
+	progressService := NewProgressService(repos.Students, cfg)
+	reportsService := NewReportsService(repos.Students, cfg)
+	trashService := NewTrashService(repos.Students, repos.Schemas, cfg)
+	annotationsService := NewAnnotationsService(repos.Students, cfg)
+	privacyService := NewPrivacyService(repos.Students, repos.Schemas, repos.Erasures, storageService, cfg)
+	qualityService := NewQualityService(repos.Students, cfg)
 
 	return &Services{
-		Users:      usersService,
-		Schemas:    schemasService,
-		Students:   studentsService,
-		Storage:    storageService,
-		Aggregator: parserService,
-	}
+		Users:       usersService,
+		Schemas:     schemasService,
+		Students:    studentsService,
+		Storage:     storageService,
+		Aggregator:  parserService,
+		Duplicates:  duplicatesService,
+		People:      peopleService,
+		Stats:       statsService,
+		Progress:    progressService,
+		Reports:     reportsService,
+		Trash:       trashService,
+		Annotations: annotationsService,
+		Workflow:    workflowService,
+		Privacy:     privacyService,
+		Quality:     qualityService,
+	}, nil
 }
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+)
+
+var _ ports.StatsService = (*StatsService)(nil)
+
+type StatsService struct {
+	repo ports.StatsStore
+	cfg  *config.Config
+}
+
+func NewStatsService(repo ports.StatsStore, cfg *config.Config) *StatsService {
+	return &StatsService{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+func (ss *StatsService) StudentsStats(ctx context.Context, options domain.StudentsStatsOptions) (*domain.StudentsStats, error) {
+	return ss.repo.StudentsStats(ctx, options)
+}
+
+// TimeSeries counts applications, WAC joins or project completions per time interval.
+func (ss *StatsService) TimeSeries(ctx context.Context, options domain.TimeSeriesOptions) (*domain.TimeSeries, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	return ss.repo.TimeSeries(ctx, options)
+}
@@ -43,7 +43,7 @@ func (s *StorageService) PutFile(ctx context.Context, options domain.PutFileOpti
 	return info.Key, nil
 }
 
-func (s *StorageService) GetFile(ctx context.Context, objectName string) (content io.Reader, size int64, err error) {
+func (s *StorageService) GetFile(ctx context.Context, objectName string) (content io.Reader, info *domain.FileInfo, err error) {
 
 	content, err = s.client.GetObject(ctx, s.cfg.Storage.BucketName, objectName, minio.GetObjectOptions{})
 
@@ -54,8 +54,11 @@ func (s *StorageService) GetFile(ctx context.Context, objectName string) (conten
 	objectInfo, err := content.(*minio.Object).Stat()
 
 	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			err = domain.ErrNotFound
+		}
 		return
 	}
 
-	return content, objectInfo.Size, err
+	return content, &domain.FileInfo{Size: objectInfo.Size, ContentType: objectInfo.ContentType}, err
 }
@@ -2,47 +2,156 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/abdukhashimov/student_aggregator/internal/config"
 	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
 	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
 )
 
 var _ ports.StudentsService = (*StudentsService)(nil)
 
 type StudentsService struct {
-	repo ports.StudentsStore
-	cfg  *config.Config
+	repo        ports.StudentsStore
+	schemasRepo ports.SchemaStore
+	workflow    ports.WorkflowService
+	cfg         *config.Config
 }
 
-func NewStudentsService(repo ports.StudentsStore, cfg *config.Config) *StudentsService {
+func NewStudentsService(repo ports.StudentsStore, schemasRepo ports.SchemaStore, workflow ports.WorkflowService, cfg *config.Config) *StudentsService {
 	return &StudentsService{
-		repo: repo,
-		cfg:  cfg,
+		repo:        repo,
+		schemasRepo: schemasRepo,
+		workflow:    workflow,
+		cfg:         cfg,
 	}
 }
 
+// StudentFilterFields returns fields students may be filtered by, encrypted fields are excluded,
+// their stored values can not be compared.
+func StudentFilterFields(ec config.EncryptionConfig) query.Fields {
+	result := make(query.Fields, len(domain.StudentFilterFields))
+	for field, kind := range domain.StudentFilterFields {
+		if !ec.Encrypted(field) {
+			result[field] = kind
+		}
+	}
+
+	return result
+}
+
+// StudentSortFields returns fields students may be sorted by, encrypted fields are excluded.
+func StudentSortFields(ec config.EncryptionConfig) domain.SortFields {
+	result := make(domain.SortFields, len(domain.StudentSortFields))
+	for name, field := range domain.StudentSortFields {
+		if !ec.Encrypted(field) {
+			result[name] = field
+		}
+	}
+
+	return result
+}
+
 func (s *StudentsService) GetStudentById(ctx context.Context, id string) (*domain.StudentRecord, error) {
 	student, err := s.repo.GetById(ctx, id)
 
 	return student, err
 }
 
-func (s *StudentsService) ListStudents(ctx context.Context, options domain.ListStudentsOptions) ([]domain.StudentRecord, error) {
-	schemas, err := s.repo.GetAll(ctx, options)
-
-	return schemas, err
+func (s *StudentsService) ListStudents(ctx context.Context, options domain.ListStudentsOptions) ([]domain.StudentRecord, *domain.PageInfo, error) {
+	return s.repo.GetAll(ctx, options)
 }
 
-func (s *StudentsService) UpdateStudent(ctx context.Context, id string, input domain.StudentRecord) (*domain.StudentRecord, error) {
-	err := s.repo.Update(ctx, id, input)
+// SearchStudents finds students matching query words in names, email, company, location or project names.
+// Results are ordered by relevance and hold highlighted matched fields.
+func (s *StudentsService) SearchStudents(ctx context.Context, options domain.SearchStudentsOptions) ([]domain.StudentSearchResult, error) {
+	options.Query = strings.TrimSpace(options.Query)
+	if options.Query == "" {
+		return []domain.StudentSearchResult{}, nil
+	}
+
+	results, err := s.repo.Search(ctx, options)
 	if err != nil {
 		return nil, err
 	}
 
+	words := strings.Fields(options.Query)
+	for i := range results {
+		results[i].Highlights = highlightStudent(&results[i].Student, words)
+	}
+
+	// regex search results have no text score, rank them by matched fields count
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return len(results[i].Highlights) > len(results[j].Highlights)
+	})
+
+	return results, nil
+}
+
+// UpdateStudent updates student if it has revision, or regardless of revision when it is nil.
+// Source and merged records are set by imports and merges, status, tags, notes and annotations are changed
+// by their own operations only, changing any of them returns ErrInvalidParams.
+func (s *StudentsService) UpdateStudent(ctx context.Context, id string, input domain.StudentRecord, revision *int) (*domain.StudentRecord, error) {
 	student, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if revision != nil && *revision != student.Revision {
+		return nil, domain.ErrPreconditionFailed
+	}
 
-	return student, err
+	// omitted fields are kept as they are, sent ones should not differ from the stored ones
+	sent := *student
+	if input.Source != "" {
+		sent.Source = input.Source
+	}
+	if input.MergedFrom != nil {
+		sent.MergedFrom = input.MergedFrom
+	}
+	if input.Status != "" {
+		sent.Status = input.Status
+	}
+	if input.StatusChanges != nil {
+		sent.StatusChanges = input.StatusChanges
+	}
+	if input.Tags != nil {
+		sent.Tags = input.Tags
+	}
+	if input.Notes != nil {
+		sent.Notes = input.Notes
+	}
+	if input.Annotations != nil {
+		sent.Annotations = input.Annotations
+	}
+	readOnly := []readOnlyField{
+		{"source", student.Source, sent.Source},
+		{"merged_from", student.MergedFrom, sent.MergedFrom},
+	}
+	if err := checkReadOnly(append(readOnly, managedFields(student, &sent)...)); err != nil {
+		return nil, err
+	}
+
+	// source is always set by the update, so the stored one is written back
+	input.Source = student.Source
+	input.MergedFrom = nil
+	input.Status = ""
+	input.StatusChanges = nil
+	input.Tags = nil
+	input.Notes = nil
+	input.Annotations = nil
+
+	if err := s.repo.Update(ctx, id, input, &student.Revision); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetById(ctx, id)
 }
 
 func (s *StudentsService) DeleteStudent(ctx context.Context, id string) error {
@@ -56,3 +165,93 @@ func (s *StudentsService) DeleteStudentByFileName(ctx context.Context, fileName
 
 	return err
 }
+
+// BulkStudents applies the operation to students selected either by ids or by filter expression.
+// Failures of single students do not stop the operation and are reported in the result.
+func (s *StudentsService) BulkStudents(ctx context.Context, input domain.BulkStudentsInput) (*domain.BulkResult, error) {
+	if (len(input.IDs) == 0) == (input.Filter == "") {
+		return nil, fmt.Errorf("%w: either ids or filter is required", domain.ErrInvalidParams)
+	}
+	if len(input.IDs) > domain.MaxBulkIDs {
+		return nil, fmt.Errorf("%w: at most %d ids are allowed", domain.ErrInvalidParams, domain.MaxBulkIDs)
+	}
+
+	if err := input.Operation.Validate(); err != nil {
+		return nil, err
+	}
+
+	options := domain.BulkStudentsOptions{
+		IDs:       input.IDs,
+		Operation: input.Operation,
+	}
+	if input.Filter != "" {
+		filter, err := query.Parse(input.Filter, StudentFilterFields(s.cfg.Encryption))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidParams, err)
+		}
+		options.Filter = filter
+	}
+
+	if options.Operation.Type == domain.BulkTransition {
+		return s.workflow.BulkTransition(ctx, options)
+	}
+
+	return s.repo.BulkUpdate(ctx, options)
+}
+
+// GetStudentHistory returns versions of student record including deleted ones.
+func (s *StudentsService) GetStudentHistory(ctx context.Context, id string) ([]domain.HistoryEntry, error) {
+	history, err := s.repo.History(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	return history, nil
+}
+
+// RestoreStudent brings student record back to its version, restoring is recorded as a new version.
+func (s *StudentsService) RestoreStudent(ctx context.Context, id string, version int, revision *int) (*domain.StudentRecord, error) {
+	return s.repo.Restore(ctx, id, version, revision)
+}
+
+// highlightStudent wraps query words found in student searchable fields with <em></em>.
+// Returns matched fields by name.
+func highlightStudent(student *domain.StudentRecord, words []string) map[string]string {
+	projects := make([]string, 0, len(student.Projects))
+	for _, p := range student.Projects {
+		projects = append(projects, p.Name)
+	}
+
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"first_name", student.FirstName},
+		{"last_name", student.LastName},
+		{"full_name", student.StudentWAC.FullName},
+		{"email", student.Email},
+		{"company", student.Company},
+		{"location", student.Location},
+		{"projects", strings.Join(projects, ", ")},
+	}
+
+	quoted := make([]string, 0, len(words))
+	for _, w := range words {
+		quoted = append(quoted, regexp.QuoteMeta(w))
+	}
+	re := regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+
+	highlights := map[string]string{}
+	for _, field := range fields {
+		if field.value == "" || !re.MatchString(field.value) {
+			continue
+		}
+		highlights[field.name] = re.ReplaceAllString(field.value, "<em>$0</em>")
+	}
+
+	return highlights
+}
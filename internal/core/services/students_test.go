@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+)
+
+func TestHighlightStudent(t *testing.T) {
+	student := domain.StudentRecord{
+		Email: "obi@jedi.rules",
+		StudentRSS: domain.StudentRSS{
+			FirstName: "Obi-Wan",
+			LastName:  "Kenobi",
+			Projects:  []domain.Project{{Name: "Lightsaber"}, {Name: "Jedi Code"}},
+		},
+		StudentWAC: domain.StudentWAC{Company: "Jedi Order", Location: "Tatooine"},
+	}
+
+	tests := []struct {
+		name  string
+		words []string
+		want  map[string]string
+	}{
+		{
+			"partial word in several fields",
+			[]string{"jedi"},
+			map[string]string{
+				"email":    "obi@<em>jedi</em>.rules",
+				"company":  "<em>Jedi</em> Order",
+				"projects": "Lightsaber, <em>Jedi</em> Code",
+			},
+		},
+		{
+			"several words",
+			[]string{"OBI", "tatoo"},
+			map[string]string{
+				"first_name": "<em>Obi</em>-Wan",
+				"last_name":  "Ken<em>obi</em>",
+				"email":      "<em>obi</em>@jedi.rules",
+				"location":   "<em>Tatoo</em>ine",
+			},
+		},
+		{
+			"regexp characters are quoted",
+			[]string{"o.i"},
+			map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightStudent(&student, tt.words); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("highlightStudent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type updatedStore struct {
+	ports.StudentsStore
+	student domain.StudentRecord
+}
+
+func (us *updatedStore) GetById(_ context.Context, _ string) (*domain.StudentRecord, error) {
+	student := us.student
+	return &student, nil
+}
+
+func (us *updatedStore) Update(_ context.Context, _ string, input domain.StudentRecord, _ *int) error {
+	input.ID, input.Revision = us.student.ID, us.student.Revision+1
+	input.Status, input.Tags, input.Notes = us.student.Status, us.student.Tags, us.student.Notes
+	us.student = input
+
+	return nil
+}
+
+func TestUpdateStudent(t *testing.T) {
+	stored := domain.StudentRecord{
+		ID:         "1",
+		Source:     domain.RSS,
+		Email:      "luke@jedi.rules",
+		Status:     "active",
+		Revision:   2,
+		Tags:       []domain.Tag{{Name: "pilot"}},
+		StudentRSS: domain.StudentRSS{FirstName: "Luke"},
+	}
+	revision := func(r int) *int { return &r }
+
+	tests := []struct {
+		name     string
+		input    domain.StudentRecord
+		revision *int
+		wantErr  error
+	}{
+		{
+			name:  "omitted managed fields",
+			input: domain.StudentRecord{Source: domain.RSS, Email: "luke@jedi.rules", StudentRSS: domain.StudentRSS{FirstName: "Luke", LastName: "Skywalker"}},
+		},
+		{
+			name: "unchanged managed fields",
+			input: domain.StudentRecord{Source: domain.RSS, Email: "luke@jedi.rules", Status: "active", Tags: []domain.Tag{{Name: "pilot"}},
+				StudentRSS: domain.StudentRSS{FirstName: "Luke", LastName: "Skywalker"}},
+			revision: revision(2),
+		},
+		{
+			name:  "omitted source",
+			input: domain.StudentRecord{Email: "luke@jedi.rules", StudentRSS: domain.StudentRSS{FirstName: "Luke", LastName: "Skywalker"}},
+		},
+		{
+			name:    "sourceField",
+			input:   domain.StudentRecord{Source: domain.WAC, Email: "luke@jedi.rules"},
+			wantErr: domain.ErrInvalidParams,
+		},
+		{
+			name: "mergedFromField",
+			input: domain.StudentRecord{Source: domain.RSS, Email: "luke@jedi.rules",
+				MergedFrom: []domain.MergedRecord{{ID: "2"}}},
+			wantErr: domain.ErrInvalidParams,
+		},
+		{
+			name:    "statusField",
+			input:   domain.StudentRecord{Source: domain.RSS, Email: "luke@jedi.rules", Status: "graduated"},
+			wantErr: domain.ErrInvalidParams,
+		},
+		{
+			name:    "tagsField",
+			input:   domain.StudentRecord{Source: domain.RSS, Email: "luke@jedi.rules", Tags: []domain.Tag{{Name: "jedi"}}},
+			wantErr: domain.ErrInvalidParams,
+		},
+		{
+			name:    "notesField",
+			input:   domain.StudentRecord{Source: domain.RSS, Email: "luke@jedi.rules", Notes: []domain.Note{{Text: "trained by Yoda"}}},
+			wantErr: domain.ErrInvalidParams,
+		},
+		{
+			name: "annotationsField",
+			input: domain.StudentRecord{Source: domain.RSS, Email: "luke@jedi.rules",
+				Annotations: map[string]domain.Annotation{"rank": {Value: "commander"}}},
+			wantErr: domain.ErrInvalidParams,
+		},
+		{
+			name:     "staleRevision",
+			input:    domain.StudentRecord{Source: domain.RSS, Email: "luke@jedi.rules"},
+			revision: revision(1),
+			wantErr:  domain.ErrPreconditionFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &updatedStore{student: stored}
+			s := NewStudentsService(store, nil, nil, nil)
+
+			got, err := s.UpdateStudent(context.Background(), stored.ID, tt.input, tt.revision)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateStudent() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.LastName != "Skywalker" || got.Source != domain.RSS || got.Status != "active" || len(got.Tags) != 1 {
+				t.Errorf("UpdateStudent() = %+v, want updated name and kept source, status and tags", got)
+			}
+		})
+	}
+}
+
+func TestStudentFields(t *testing.T) {
+	ec := config.EncryptionConfig{Fields: []string{"email", "student_wac.location"}}
+
+	filterFields := StudentFilterFields(ec)
+	for _, field := range ec.Fields {
+		if _, ok := filterFields[field]; ok {
+			t.Errorf("StudentFilterFields() has encrypted field %s", field)
+		}
+	}
+	if len(filterFields) != len(domain.StudentFilterFields)-len(ec.Fields) {
+		t.Errorf("StudentFilterFields() has %d fields, want %d", len(filterFields), len(domain.StudentFilterFields)-len(ec.Fields))
+	}
+
+	sortFields := StudentSortFields(ec)
+	for _, name := range []string{"email", "location"} {
+		if _, ok := sortFields[name]; ok {
+			t.Errorf("StudentSortFields() has encrypted field %s", name)
+		}
+	}
+	if _, ok := sortFields["company"]; !ok {
+		t.Error("StudentSortFields() has no plain company field")
+	}
+
+	if plain := StudentFilterFields(config.EncryptionConfig{}); len(plain) != len(domain.StudentFilterFields) {
+		t.Errorf("StudentFilterFields() without encryption has %d fields, want %d", len(plain), len(domain.StudentFilterFields))
+	}
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"github.com/abdukhashimov/student_aggregator/internal/pkg/logger"
+)
+
+var _ ports.TrashService = (*TrashService)(nil)
+
+// defaultTrashLimit limits listed trash items when limit is not set.
+const defaultTrashLimit = 100
+
+type TrashService struct {
+	studentsRepo ports.StudentsStore
+	schemasRepo  ports.SchemaStore
+	cfg          *config.Config
+	now          func() time.Time
+}
+
+func NewTrashService(studentsRepo ports.StudentsStore, schemasRepo ports.SchemaStore, cfg *config.Config) *TrashService {
+	return &TrashService{
+		studentsRepo: studentsRepo,
+		schemasRepo:  schemasRepo,
+		cfg:          cfg,
+		now:          time.Now,
+	}
+}
+
+// ListTrash lists students and schemas moved to trash, the most recently deleted first.
+func (ts *TrashService) ListTrash(ctx context.Context, options domain.TrashOptions) ([]domain.TrashItem, error) {
+	switch options.Type {
+	case "", domain.TrashStudent, domain.TrashSchema:
+	default:
+		return nil, fmt.Errorf("%w: unknown trash item type %q", domain.ErrInvalidParams, options.Type)
+	}
+
+	limit := options.Limit
+	if limit <= 0 {
+		limit = defaultTrashLimit
+	}
+
+	var err error
+	var students []domain.StudentRecord
+	if options.Type != domain.TrashSchema {
+		if students, err = ts.studentsRepo.ListDeleted(ctx, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	var schemas []domain.Schema
+	if options.Type != domain.TrashStudent {
+		if schemas, err = ts.schemasRepo.ListDeleted(ctx, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	return trashItems(students, schemas, ts.retention(), limit), nil
+}
+
+// RestoreStudent restores student from trash.
+func (ts *TrashService) RestoreStudent(ctx context.Context, id string) (*domain.StudentRecord, error) {
+	return ts.studentsRepo.Undelete(ctx, id)
+}
+
+// RestoreSchema restores schema from trash.
+func (ts *TrashService) RestoreSchema(ctx context.Context, id string) (*domain.Schema, error) {
+	if err := ts.schemasRepo.Undelete(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return ts.schemasRepo.GetById(ctx, id)
+}
+
+// Purge permanently deletes students and schemas kept in trash longer than the retention period.
+// Nothing is deleted when retention is not set.
+func (ts *TrashService) Purge(ctx context.Context) (*domain.PurgeResult, error) {
+	result := &domain.PurgeResult{}
+
+	retention := ts.retention()
+	if retention == 0 {
+		return result, nil
+	}
+	before := ts.now().UTC().Add(-retention)
+
+	var err error
+	if result.Students, err = ts.studentsRepo.Purge(ctx, before); err != nil {
+		return nil, err
+	}
+	if result.Schemas, err = ts.schemasRepo.Purge(ctx, before); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RunPurgeJob purges trash right away and then every purge interval until ctx is done.
+// It returns at once when retention is not set.
+func (ts *TrashService) RunPurgeJob(ctx context.Context) {
+	if ts.retention() == 0 || ts.cfg.Trash.PurgeIntervalMinutes <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(ts.cfg.Trash.PurgeIntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		result, err := ts.Purge(ctx)
+		if err != nil {
+			logger.Log.Errorf("error to purge trash: %v", err)
+		} else if result.Students > 0 || result.Schemas > 0 {
+			logger.Log.Infof("trash purged: %d students, %d schemas", result.Students, result.Schemas)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ts *TrashService) retention() time.Duration {
+	return time.Duration(ts.cfg.Trash.RetentionDays) * 24 * time.Hour
+}
+
+// trashItems merges deleted students and schemas, the most recently deleted first, up to limit items.
+// Items get purge time when retention is set.
+func trashItems(students []domain.StudentRecord, schemas []domain.Schema, retention time.Duration, limit int) []domain.TrashItem {
+	items := make([]domain.TrashItem, 0, len(students)+len(schemas))
+	for _, s := range students {
+		items = append(items, newTrashItem(s.ID, domain.TrashStudent, s.Email, s.DeletedAt, s.DeletedBy, retention))
+	}
+	for _, s := range schemas {
+		items = append(items, newTrashItem(s.ID, domain.TrashSchema, s.Name, s.DeletedAt, s.DeletedBy, retention))
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+func newTrashItem(id, itemType, name string, deletedAt *time.Time, deletedBy string, retention time.Duration) domain.TrashItem {
+	item := domain.TrashItem{
+		ID:        id,
+		Type:      itemType,
+		Name:      name,
+		DeletedBy: deletedBy,
+	}
+	if deletedAt != nil {
+		item.DeletedAt = *deletedAt
+	}
+	if retention > 0 {
+		purgeAt := item.DeletedAt.Add(retention)
+		item.PurgeAt = &purgeAt
+	}
+
+	return item
+}
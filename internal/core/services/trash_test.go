@@ -0,0 +1,62 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+func TestTrashItems(t *testing.T) {
+	day := 24 * time.Hour
+	first := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	third := second.Add(time.Hour)
+
+	students := []domain.StudentRecord{
+		{ID: "s2", Email: "b@x.io", DeletedAt: &third, DeletedBy: "admin"},
+		{ID: "s1", Email: "a@x.io", DeletedAt: &first},
+	}
+	schemas := []domain.Schema{
+		{ID: "c1", Name: "RSS", DeletedAt: &second, DeletedBy: "editor"},
+	}
+
+	purgeAt := func(t time.Time) *time.Time {
+		p := t.Add(30 * day)
+		return &p
+	}
+
+	tests := []struct {
+		name      string
+		retention time.Duration
+		limit     int
+		want      []domain.TrashItem
+	}{
+		{
+			name:      "newest first with purge time",
+			retention: 30 * day,
+			limit:     10,
+			want: []domain.TrashItem{
+				{ID: "s2", Type: domain.TrashStudent, Name: "b@x.io", DeletedAt: third, DeletedBy: "admin", PurgeAt: purgeAt(third)},
+				{ID: "c1", Type: domain.TrashSchema, Name: "RSS", DeletedAt: second, DeletedBy: "editor", PurgeAt: purgeAt(second)},
+				{ID: "s1", Type: domain.TrashStudent, Name: "a@x.io", DeletedAt: first, PurgeAt: purgeAt(first)},
+			},
+		},
+		{
+			name:  "limited without retention",
+			limit: 2,
+			want: []domain.TrashItem{
+				{ID: "s2", Type: domain.TrashStudent, Name: "b@x.io", DeletedAt: third, DeletedBy: "admin"},
+				{ID: "c1", Type: domain.TrashSchema, Name: "RSS", DeletedAt: second, DeletedBy: "editor"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trashItems(students, schemas, tt.retention, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("trashItems() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
+)
+
+// importedReason is the reason of the initial status set to imported students.
+const importedReason = "imported"
+
+var _ ports.WorkflowService = (*WorkflowService)(nil)
+
+// WorkflowService changes student statuses by transitions of the configured workflow.
+type WorkflowService struct {
+	repo ports.StudentsStore
+	cfg  *config.Config
+	now  func() time.Time
+}
+
+func NewWorkflowService(repo ports.StudentsStore, cfg *config.Config) *WorkflowService {
+	return &WorkflowService{
+		repo: repo,
+		cfg:  cfg,
+		now:  time.Now,
+	}
+}
+
+// validateWorkflow checks filters of automatic transitions, they are parsed against fields
+// of the configured encryption, so it can not be done when config is loaded.
+func validateWorkflow(cfg *config.Config) error {
+	for i, rule := range cfg.Workflow.Automatic {
+		if rule.Filter == "" {
+			continue
+		}
+		if _, err := query.Parse(rule.Filter, StudentFilterFields(cfg.Encryption)); err != nil {
+			return fmt.Errorf("workflow automatic transition %d filter: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (ws *WorkflowService) GetWorkflow() *domain.Workflow {
+	wc := ws.cfg.Workflow
+
+	workflow := &domain.Workflow{
+		InitialStatus: wc.InitialStatus,
+		Statuses:      wc.Statuses(),
+		Transitions:   map[string][]string{},
+		Automatic:     []domain.AutomaticTransition{},
+	}
+	for from, to := range wc.Transitions {
+		workflow.Transitions[from] = to
+	}
+	for _, rule := range wc.Automatic {
+		workflow.Automatic = append(workflow.Automatic, domain.AutomaticTransition{
+			From:      rule.From,
+			To:        rule.To,
+			Condition: rule.Condition,
+			Filter:    rule.Filter,
+			Reason:    rule.Reason,
+		})
+	}
+
+	return workflow
+}
+
+// ChangeStatus changes student status on behalf of ctx actor if the workflow allows it.
+// Student without status may be given any status of the workflow.
+// Student is changed if it has revision, or regardless of revision when it is nil.
+func (ws *WorkflowService) ChangeStatus(ctx context.Context, id string, input domain.StatusInput, revision *int) (*domain.StudentRecord, error) {
+	student, err := ws.repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if revision != nil && *revision != student.Revision {
+		return nil, domain.ErrPreconditionFailed
+	}
+
+	if !transitionAllowed(ws.cfg.Workflow, student.Status, input.Status) {
+		return nil, fmt.Errorf("%w: from %q to %q", domain.ErrTransitionNotAllowed, student.Status, input.Status)
+	}
+
+	return ws.repo.ChangeStatus(ctx, student, domain.StatusChange{
+		From:      student.Status,
+		To:        input.Status,
+		Reason:    input.Reason,
+		ChangedBy: domain.ActorFromContext(ctx).Username,
+		ChangedAt: ws.now().UTC(),
+	})
+}
+
+// BulkTransition changes status of students selected by ids or filter on behalf of ctx actor,
+// every student is changed only if the workflow allows the transition. Students already having the status
+// are not changed. Unknown ids, disallowed transitions and students modified concurrently are reported as item errors.
+// Returns ErrBulkTooLarge when filter matches more than MaxBulkIDs students, nobody is changed then.
+func (ws *WorkflowService) BulkTransition(ctx context.Context, bulk domain.BulkStudentsOptions) (*domain.BulkResult, error) {
+	result := &domain.BulkResult{Errors: []domain.BulkItemError{}}
+	op := bulk.Operation
+
+	var students []domain.StudentRecord
+	err := ws.repo.Iterate(ctx, domain.ListStudentsOptions{IDs: bulk.IDs, Filter: bulk.Filter}, func(student *domain.StudentRecord) error {
+		if len(students) == domain.MaxBulkIDs {
+			return domain.ErrBulkTooLarge
+		}
+		students = append(students, *student)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Matched = int64(len(students))
+
+	found := make(map[string]bool, len(students))
+	for _, student := range students {
+		found[student.ID] = true
+	}
+	for _, id := range bulk.IDs {
+		if !found[id] {
+			result.Errors = append(result.Errors, domain.BulkItemError{ID: id, Error: domain.ErrNotFound.Error()})
+		}
+	}
+
+	for i := range students {
+		student := &students[i]
+		if student.Status == op.Status {
+			continue
+		}
+		if !transitionAllowed(ws.cfg.Workflow, student.Status, op.Status) {
+			err := fmt.Errorf("%w: from %q to %q", domain.ErrTransitionNotAllowed, student.Status, op.Status)
+			result.Errors = append(result.Errors, domain.BulkItemError{ID: student.ID, Error: err.Error()})
+			continue
+		}
+
+		_, err := ws.repo.ChangeStatus(ctx, student, domain.StatusChange{
+			From:      student.Status,
+			To:        op.Status,
+			Reason:    op.Reason,
+			ChangedBy: domain.ActorFromContext(ctx).Username,
+			ChangedAt: ws.now().UTC(),
+		})
+		if errors.Is(err, domain.ErrPreconditionFailed) {
+			result.Errors = append(result.Errors, domain.BulkItemError{ID: student.ID, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Modified++
+	}
+
+	return result, nil
+}
+
+// ApplyImport gives the initial status to students imported from the file without status,
+// then applies automatic transitions to them in the configured order.
+// Students modified concurrently are skipped.
+func (ws *WorkflowService) ApplyImport(ctx context.Context, fileName string) (*domain.WorkflowResult, error) {
+	wc := ws.cfg.Workflow
+	result := &domain.WorkflowResult{}
+
+	if wc.InitialStatus != "" {
+		students, err := ws.collect(ctx, domain.ListStudentsOptions{FileName: fileName}, func(student *domain.StudentRecord) bool {
+			return student.Status == ""
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := ws.transition(ctx, students, wc.InitialStatus, importedReason)
+		if err != nil {
+			return nil, err
+		}
+		result.Initialized = n
+	}
+
+	var required []string
+	for i, rule := range wc.Automatic {
+		options := domain.ListStudentsOptions{FileName: fileName, Status: rule.From}
+		if rule.Filter != "" {
+			expr, err := query.Parse(rule.Filter, StudentFilterFields(ws.cfg.Encryption))
+			if err != nil {
+				return nil, fmt.Errorf("workflow automatic transition %d filter: %w", i, err)
+			}
+			options.Filter = expr
+		}
+
+		if rule.Condition != "" && required == nil {
+			var err error
+			if required, err = requiredProjects(ctx, ws.repo, ws.cfg); err != nil {
+				return nil, err
+			}
+		}
+
+		students, err := ws.collect(ctx, options, func(student *domain.StudentRecord) bool {
+			return conditionHolds(rule.Condition, student, required, ws.cfg.Progress.AtRisk)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := ws.transition(ctx, students, rule.To, rule.Reason)
+		if err != nil {
+			return nil, err
+		}
+		result.Transitioned += n
+	}
+
+	return result, nil
+}
+
+// collect returns students matching options for whom match is true.
+// They are read before being changed, so changes do not affect iteration.
+func (ws *WorkflowService) collect(ctx context.Context, options domain.ListStudentsOptions, match func(student *domain.StudentRecord) bool) ([]domain.StudentRecord, error) {
+	var students []domain.StudentRecord
+	err := ws.repo.Iterate(ctx, options, func(student *domain.StudentRecord) error {
+		if match(student) {
+			students = append(students, *student)
+		}
+		return nil
+	})
+
+	return students, err
+}
+
+// transition automatically changes status of students to status and returns the number of changed ones.
+func (ws *WorkflowService) transition(ctx context.Context, students []domain.StudentRecord, status string, reason string) (int, error) {
+	changed := 0
+	for i := range students {
+		student := &students[i]
+		_, err := ws.repo.ChangeStatus(ctx, student, domain.StatusChange{
+			From:      student.Status,
+			To:        status,
+			Reason:    reason,
+			ChangedBy: domain.ActorFromContext(ctx).Username,
+			ChangedAt: ws.now().UTC(),
+			Automatic: true,
+		})
+		if errors.Is(err, domain.ErrPreconditionFailed) {
+			continue
+		}
+		if err != nil {
+			return changed, err
+		}
+		changed++
+	}
+
+	return changed, nil
+}
+
+// transitionAllowed reports whether student may change status from one to another by the workflow.
+func transitionAllowed(wc config.WorkflowConfig, from string, to string) bool {
+	if from != "" {
+		return wc.Allows(from, to)
+	}
+
+	for _, status := range wc.Statuses() {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// conditionHolds checks automatic transition condition, empty condition always holds.
+func conditionHolds(condition string, student *domain.StudentRecord, required []string, rules config.AtRiskRules) bool {
+	switch condition {
+	case "":
+		return true
+	case config.ConditionProjectsFinished:
+		return student.Source == domain.RSS && len(required) > 0 &&
+			len(studentProgress(student, required, rules).Missing) == 0
+	case config.ConditionAtRisk:
+		return student.Source == domain.RSS && studentProgress(student, required, rules).AtRisk
+	default:
+		return false
+	}
+}
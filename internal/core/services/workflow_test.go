@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+)
+
+func TestTransitionAllowed(t *testing.T) {
+	wc := config.WorkflowConfig{
+		InitialStatus: "applied",
+		Transitions: map[string][]string{
+			"applied":  {"accepted", "dropped"},
+			"accepted": {"studying"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want bool
+	}{
+		{"listed transition", "applied", "accepted", true},
+		{"unlisted transition", "applied", "studying", false},
+		{"from final status", "dropped", "applied", false},
+		{"same status", "accepted", "accepted", false},
+		{"no status to known status", "", "studying", true},
+		{"no status to unknown status", "", "expelled", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transitionAllowed(wc, tt.from, tt.to); got != tt.want {
+				t.Errorf("transitionAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWorkflow(t *testing.T) {
+	cfg := &config.Config{
+		Workflow: config.WorkflowConfig{
+			Automatic: []config.AutomaticTransition{
+				{From: "studying", To: "graduated", Filter: `student_wac.location="Tatooine"`},
+			},
+		},
+	}
+	if err := validateWorkflow(cfg); err != nil {
+		t.Errorf("validateWorkflow() error = %v", err)
+	}
+
+	cfg.Encryption.Fields = []string{"student_wac.location"}
+	if err := validateWorkflow(cfg); err == nil {
+		t.Error("validateWorkflow() accepts filter by encrypted field")
+	}
+}
+
+func TestConditionHolds(t *testing.T) {
+	minScore := 60.0
+	rules := config.AtRiskRules{MinAverageScore: &minScore}
+	required := []string{"Songbird", "Gem Puzzle"}
+
+	finished := &domain.StudentRecord{
+		Source: domain.RSS,
+		StudentRSS: domain.StudentRSS{Projects: []domain.Project{
+			{Name: "Songbird", Score: 100},
+			{Name: "Gem Puzzle", Score: 90},
+		}},
+	}
+	unfinished := &domain.StudentRecord{
+		Source:     domain.RSS,
+		StudentRSS: domain.StudentRSS{Projects: []domain.Project{{Name: "Songbird", Score: 100}}},
+	}
+	wac := &domain.StudentRecord{Source: domain.WAC}
+
+	tests := []struct {
+		name      string
+		condition string
+		student   *domain.StudentRecord
+		required  []string
+		want      bool
+	}{
+		{"no condition", "", wac, required, true},
+		{"projects finished", config.ConditionProjectsFinished, finished, required, true},
+		{"project missing", config.ConditionProjectsFinished, unfinished, required, false},
+		{"no required projects", config.ConditionProjectsFinished, finished, []string{}, false},
+		{"not RSS student", config.ConditionProjectsFinished, wac, required, false},
+		{"at risk", config.ConditionAtRisk, unfinished, required, true},
+		{"not at risk", config.ConditionAtRisk, finished, required, false},
+		{"unknown condition", "graduated", finished, required, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conditionHolds(tt.condition, tt.student, tt.required, rules); got != tt.want {
+				t.Errorf("conditionHolds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// transitionStore lists students by ids and changes their statuses, students listed in stale are modified concurrently.
+type transitionStore struct {
+	ports.StudentsStore
+	students []domain.StudentRecord
+	stale    map[string]bool
+	changes  map[string]domain.StatusChange
+}
+
+func (ts *transitionStore) Iterate(_ context.Context, options domain.ListStudentsOptions, fn func(student *domain.StudentRecord) error) error {
+	for i := range ts.students {
+		for _, id := range options.IDs {
+			if ts.students[i].ID != id {
+				continue
+			}
+			student := ts.students[i]
+			if err := fn(&student); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ts *transitionStore) ChangeStatus(_ context.Context, student *domain.StudentRecord, change domain.StatusChange) (*domain.StudentRecord, error) {
+	if ts.stale[student.ID] {
+		return nil, domain.ErrPreconditionFailed
+	}
+	ts.changes[student.ID] = change
+
+	after := *student
+	after.Status = change.To
+
+	return &after, nil
+}
+
+func TestBulkTransition(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	ctx := domain.ContextWithActor(context.Background(), domain.Actor{Username: "yoda"})
+
+	store := &transitionStore{
+		students: []domain.StudentRecord{
+			{ID: "1", Status: "applied"},
+			{ID: "2", Status: "dropped"},
+			{ID: "3", Status: "accepted"},
+			{ID: "4"},
+			{ID: "5", Status: "applied"},
+		},
+		stale:   map[string]bool{"5": true},
+		changes: map[string]domain.StatusChange{},
+	}
+	ws := NewWorkflowService(store, &config.Config{Workflow: config.WorkflowConfig{
+		InitialStatus: "applied",
+		Transitions: map[string][]string{
+			"applied": {"accepted", "dropped"},
+		},
+	}})
+	ws.now = func() time.Time { return now }
+
+	result, err := ws.BulkTransition(ctx, domain.BulkStudentsOptions{
+		IDs:       []string{"1", "2", "3", "4", "5", "6"},
+		Operation: domain.BulkOperation{Type: domain.BulkTransition, Status: "accepted", Reason: "interviewed"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Matched != 5 || result.Modified != 2 {
+		t.Errorf("BulkTransition() matched %d, modified %d, want 5 and 2", result.Matched, result.Modified)
+	}
+
+	var failed []string
+	for _, e := range result.Errors {
+		failed = append(failed, e.ID)
+	}
+	if want := []string{"6", "2", "5"}; !reflect.DeepEqual(failed, want) {
+		t.Errorf("BulkTransition() failed ids = %v, want %v", failed, want)
+	}
+	if !strings.Contains(result.Errors[1].Error, domain.ErrTransitionNotAllowed.Error()) {
+		t.Errorf("BulkTransition() error = %q, want transition not allowed", result.Errors[1].Error)
+	}
+
+	want := map[string]domain.StatusChange{
+		"1": {From: "applied", To: "accepted", Reason: "interviewed", ChangedBy: "yoda", ChangedAt: now},
+		"4": {To: "accepted", Reason: "interviewed", ChangedBy: "yoda", ChangedAt: now},
+	}
+	if !reflect.DeepEqual(store.changes, want) {
+		t.Errorf("BulkTransition() changes = %v, want %v", store.changes, want)
+	}
+}
+
+func TestBulkTransitionTooLarge(t *testing.T) {
+	store := &transitionStore{changes: map[string]domain.StatusChange{}}
+	var ids []string
+	for i := 0; i <= domain.MaxBulkIDs; i++ {
+		id := strconv.Itoa(i)
+		ids = append(ids, id)
+		store.students = append(store.students, domain.StudentRecord{ID: id})
+	}
+	ws := NewWorkflowService(store, &config.Config{})
+
+	_, err := ws.BulkTransition(context.Background(), domain.BulkStudentsOptions{
+		IDs:       ids,
+		Operation: domain.BulkOperation{Type: domain.BulkTransition, Status: "accepted"},
+	})
+	if !errors.Is(err, domain.ErrInvalidParams) {
+		t.Errorf("BulkTransition() error = %v, want %v", err, domain.ErrInvalidParams)
+	}
+	if len(store.changes) > 0 {
+		t.Errorf("BulkTransition() changed %d students, want none", len(store.changes))
+	}
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/gorilla/mux"
+)
+
+// @Summary Add Student Tag
+// @Description tags student, tags are kept when the student is imported again
+// @Security UsersAuth
+// @Tags annotations
+// @Param id path string true "student id"
+// @Param input body domain.TagInput true "tag"
+// @Success 200 {object} StudentResponse
+// @Header 200 {string} ETag "student revision"
+// @Failure 401
+// @Failure 404
+// @Failure 412
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /students/{id}/tags [post]
+func (s *Server) addStudentTag(w http.ResponseWriter, r *http.Request) {
+	input, err := inputFromContext[domain.TagInput](r.Context())
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	student, err := s.annotationsService.AddTag(r.Context(), mux.Vars(r)["id"], *input)
+	sendAnnotatedStudent(w, student, err)
+}
+
+// @Summary Remove Student Tag
+// @Description removes tag of student
+// @Security UsersAuth
+// @Tags annotations
+// @Param id path string true "student id"
+// @Param tag path string true "tag name"
+// @Success 200 {object} StudentResponse
+// @Header 200 {string} ETag "student revision"
+// @Failure 401
+// @Failure 404
+// @Failure 412
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /students/{id}/tags/{tag} [delete]
+func (s *Server) removeStudentTag(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	student, err := s.annotationsService.RemoveTag(r.Context(), vars["id"], vars["tag"])
+	sendAnnotatedStudent(w, student, err)
+}
+
+// @Summary Add Student Note
+// @Description adds a note authored by the current user to student
+// @Security UsersAuth
+// @Tags annotations
+// @Param id path string true "student id"
+// @Param input body domain.NoteInput true "note"
+// @Success 200 {object} StudentResponse
+// @Header 200 {string} ETag "student revision"
+// @Failure 401
+// @Failure 404
+// @Failure 412
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /students/{id}/notes [post]
+func (s *Server) addStudentNote(w http.ResponseWriter, r *http.Request) {
+	input, err := inputFromContext[domain.NoteInput](r.Context())
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	student, err := s.annotationsService.AddNote(r.Context(), mux.Vars(r)["id"], *input)
+	sendAnnotatedStudent(w, student, err)
+}
+
+// @Summary Update Student Note
+// @Description replaces text of student note
+// @Security UsersAuth
+// @Tags annotations
+// @Param id path string true "student id"
+// @Param noteId path string true "note id"
+// @Param input body domain.NoteInput true "note"
+// @Success 200 {object} StudentResponse
+// @Header 200 {string} ETag "student revision"
+// @Failure 401
+// @Failure 404
+// @Failure 412
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /students/{id}/notes/{noteId} [put]
+func (s *Server) updateStudentNote(w http.ResponseWriter, r *http.Request) {
+	input, err := inputFromContext[domain.NoteInput](r.Context())
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	student, err := s.annotationsService.UpdateNote(r.Context(), vars["id"], vars["noteId"], *input)
+	sendAnnotatedStudent(w, student, err)
+}
+
+// @Summary Delete Student Note
+// @Description deletes student note
+// @Security UsersAuth
+// @Tags annotations
+// @Param id path string true "student id"
+// @Param noteId path string true "note id"
+// @Success 200 {object} StudentResponse
+// @Header 200 {string} ETag "student revision"
+// @Failure 401
+// @Failure 404
+// @Failure 412
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /students/{id}/notes/{noteId} [delete]
+func (s *Server) deleteStudentNote(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	student, err := s.annotationsService.DeleteNote(r.Context(), vars["id"], vars["noteId"])
+	sendAnnotatedStudent(w, student, err)
+}
+
+// @Summary Set Student Annotation
+// @Description sets custom annotation value of student by key
+// @Security UsersAuth
+// @Tags annotations
+// @Param id path string true "student id"
+// @Param key path string true "annotation key, lowercase letters, digits and underscores"
+// @Param input body domain.AnnotationInput true "annotation"
+// @Success 200 {object} StudentResponse
+// @Header 200 {string} ETag "student revision"
+// @Failure 401
+// @Failure 404
+// @Failure 412
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /students/{id}/annotations/{key} [put]
+func (s *Server) setStudentAnnotation(w http.ResponseWriter, r *http.Request) {
+	input, err := inputFromContext[domain.AnnotationInput](r.Context())
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	student, err := s.annotationsService.SetAnnotation(r.Context(), vars["id"], vars["key"], *input)
+	sendAnnotatedStudent(w, student, err)
+}
+
+// @Summary Delete Student Annotation
+// @Description deletes custom annotation of student
+// @Security UsersAuth
+// @Tags annotations
+// @Param id path string true "student id"
+// @Param key path string true "annotation key"
+// @Success 200 {object} StudentResponse
+// @Header 200 {string} ETag "student revision"
+// @Failure 401
+// @Failure 404
+// @Failure 412
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /students/{id}/annotations/{key} [delete]
+func (s *Server) deleteStudentAnnotation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	student, err := s.annotationsService.DeleteAnnotation(r.Context(), vars["id"], vars["key"])
+	sendAnnotatedStudent(w, student, err)
+}
+
+// sendAnnotatedStudent writes student changed by annotation operation or the operation error.
+func sendAnnotatedStudent(w http.ResponseWriter, student *domain.StudentRecord, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			sendNotFoundError(w)
+		case errors.Is(err, domain.ErrPreconditionFailed):
+			sendPreconditionFailedError(w)
+		case errors.Is(err, domain.ErrInvalidParams):
+			sendValidationError(w, []string{err.Error()})
+		default:
+			sendServerError(w, err)
+		}
+		return
+	}
+
+	setETag(w, student.Revision)
+	writeJSON(w, http.StatusOK, StudentResponse{
+		Student: *student,
+	})
+}
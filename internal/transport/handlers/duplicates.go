@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+type DuplicatesResponse struct {
+	Duplicates []domain.DuplicateCandidate `json:"duplicates"`
+}
+
+// @Summary List Duplicate Students
+// @Description finds students which are probably the same person registered with different emails
+// @Security UsersAuth
+// @Tags student
+// @Success 200 {object} DuplicatesResponse
+// @Param threshold query number false "minimal score from 0 to 1, default 0.75"
+// @Param source query string false "source"
+// @Param limit query int false "limit"
+// @Failure 401
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /students/duplicates [get]
+func (s *Server) listDuplicates(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	options := domain.FindDuplicatesOptions{
+		Source: params.Get("source"),
+	}
+
+	if params.Has("threshold") {
+		threshold, err := strconv.ParseFloat(params.Get("threshold"), 64)
+		if err != nil || threshold < 0 || threshold > 1 {
+			sendValidationError(w, []string{"threshold must be a number from 0 to 1"})
+			return
+		}
+		options.Threshold = threshold
+	}
+
+	if params.Has("limit") {
+		limit, err := strconv.Atoi(params.Get("limit"))
+		if err != nil || limit < 0 {
+			sendValidationError(w, []string{"limit must be a positive number"})
+			return
+		}
+		options.Limit = limit
+	}
+
+	duplicates, err := s.duplicatesService.FindDuplicates(r.Context(), options)
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, DuplicatesResponse{
+		Duplicates: duplicates,
+	})
+}
+
+// @Summary Merge Duplicate Students
+// @Description merges duplicate students into the primary one keeping provenance of merged records
+// @Security UsersAuth
+// @Tags student
+// @Param input body domain.MergeStudentsInput true "merge info"
+// @Success 200 {object} StudentResponse
+// @Failure 404
+// @Failure 412
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /students/duplicates/merge [post]
+func (s *Server) mergeDuplicates(w http.ResponseWriter, r *http.Request) {
+	input, err := inputFromContext[domain.MergeStudentsInput](r.Context())
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	student, err := s.duplicatesService.MergeStudents(r.Context(), *input)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			sendNotFoundError(w)
+			return
+		}
+		if err == domain.ErrSelfMerge {
+			sendValidationError(w, []string{err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrPreconditionFailed) {
+			sendPreconditionFailedError(w)
+			return
+		}
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, StudentResponse{
+		Student: *student,
+	})
+}
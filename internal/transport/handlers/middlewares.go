@@ -34,6 +34,11 @@ func (s *Server) authenticate(next http.Handler) http.Handler {
 			Username: user.Username,
 			Email:    user.Email,
 		})
+		r = r.WithContext(domain.ContextWithActor(r.Context(), domain.Actor{
+			UserID:   user.ID,
+			Username: user.Username,
+			Via:      domain.ViaAPI,
+		}))
 
 		next.ServeHTTP(w, r)
 	})
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/gorilla/mux"
+)
+
+type PersonProfileResponse struct {
+	Profile domain.PersonProfile `json:"profile"`
+}
+
+// @Summary Get Person Profile
+// @Description combines student records with the email, or the ones records with it were merged into, into a single profile
+// @Security UsersAuth
+// @Tags people
+// @Success 200 {object} PersonProfileResponse
+// @Param email path string true "person email"
+// @Failure 404
+// @Failure 422
+// @Failure 500
+// @Accept  json
+// @Produce  json
+// @Router /people/{email} [get]
+func (s *Server) getPersonProfile(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	email := vars["email"]
+	if email == "" {
+		sendUnprocessableEntityError(w, errors.New("email should not be empty"))
+		return
+	}
+
+	profile, err := s.peopleService.GetProfile(r.Context(), email)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			sendNotFoundError(w)
+			return
+		}
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PersonProfileResponse{
+		Profile: *profile,
+	})
+}
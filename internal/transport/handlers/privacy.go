@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/pkg/logger"
+	"github.com/gorilla/mux"
+)
+
+const zipFormat = "zip"
+
+type PersonExportResponse struct {
+	Export domain.PersonExport `json:"export"`
+}
+
+type ErasureResponse struct {
+	Erasure domain.Erasure `json:"erasure"`
+}
+
+type ErasuresResponse struct {
+	Erasures []domain.Erasure `json:"erasures"`
+}
+
+// @Summary Export Person Data
+// @Description exports every student record of the person, including merged and deleted ones, files they were imported from and their history
+// @Security UsersAuth
+// @Tags people
+// @Param email path string true "person email"
+// @Param format query string false "export format, json by default" Enums(json, zip)
+// @Success 200 {object} PersonExportResponse
+// @Failure 401
+// @Failure 404
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json,application/zip
+// @Router /people/{email}/export [get]
+func (s *Server) exportPerson(w http.ResponseWriter, r *http.Request) {
+	email := mux.Vars(r)["email"]
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = jsonFormat
+	}
+
+	switch format {
+	case jsonFormat:
+		personExport, err := s.privacyService.ExportPerson(r.Context(), email)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				sendNotFoundError(w)
+				return
+			}
+			sendServerError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, PersonExportResponse{
+			Export: *personExport,
+		})
+	case zipFormat:
+		extendWriteTimeout(r, exportWriteTimeout)
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "person-export.zip"))
+
+		out := &countingWriter{w: w}
+		if err := s.privacyService.ExportPersonArchive(r.Context(), email, out); err != nil {
+			if out.n > 0 {
+				// the response is already sent partially, so only log the error
+				logger.Log.Error(err)
+				return
+			}
+
+			w.Header().Del("Content-Disposition")
+			if errors.Is(err, domain.ErrNotFound) {
+				sendNotFoundError(w)
+				return
+			}
+			sendServerError(w, err)
+		}
+	default:
+		sendValidationError(w, []string{"format should be one of json, zip"})
+	}
+}
+
+// @Summary Erase Person Data
+// @Description removes every student record of the person with their history and the person rows of stored source files.
+// @Description The erasure is recorded to the audit log without the email.
+// @Security UsersAuth
+// @Tags people
+// @Param email path string true "person email"
+// @Param input body domain.EraseInput true "erasure reason"
+// @Success 200 {object} ErasureResponse
+// @Failure 401
+// @Failure 404
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /people/{email}/erase [post]
+func (s *Server) erasePerson(w http.ResponseWriter, r *http.Request) {
+	input, err := inputFromContext[domain.EraseInput](r.Context())
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	erasure, err := s.privacyService.ErasePerson(r.Context(), mux.Vars(r)["email"], *input)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			sendNotFoundError(w)
+			return
+		}
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ErasureResponse{
+		Erasure: *erasure,
+	})
+}
+
+// @Summary List Erasures
+// @Description lists audit records of personal data erasures, the most recent first
+// @Security UsersAuth
+// @Tags people
+// @Param limit query int false "limit, 100 by default"
+// @Success 200 {object} ErasuresResponse
+// @Failure 401
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /erasures [get]
+func (s *Server) listErasures(w http.ResponseWriter, r *http.Request) {
+	var limit int
+	if l := r.URL.Query().Get("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil || limit < 1 {
+			sendValidationError(w, []string{"limit must be a positive number"})
+			return
+		}
+	}
+
+	erasures, err := s.privacyService.ListErasures(r.Context(), limit)
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ErasuresResponse{
+		Erasures: erasures,
+	})
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
+	"github.com/gorilla/mux"
+)
+
+type LeaderboardResponse struct {
+	Leaderboard domain.Leaderboard `json:"leaderboard"`
+}
+
+type LateSubmissionsResponse struct {
+	Late []domain.LateSubmission `json:"late"`
+}
+
+type StudentsProgressResponse struct {
+	Progress []domain.StudentProgress `json:"progress"`
+}
+
+type StudentProgressResponse struct {
+	Progress domain.StudentProgress `json:"progress"`
+}
+
+// @Summary Projects Leaderboard
+// @Description ranks RSS students by the project score or by total score, equal scores share the rank
+// @Security UsersAuth
+// @Tags progress
+// @Success 200 {object} LeaderboardResponse
+// @Param project query string false "project name, students are ranked by total score when empty"
+// @Param limit query int false "max number of entries"
+// @Param filter query string false "filter expression, e.g. student_rss.total_score>=100"
+// @Failure 401
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /progress/leaderboard [get]
+func (s *Server) getLeaderboard(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	filter, err := query.Parse(params.Get("filter"), s.studentFilterFields())
+	if err != nil {
+		sendValidationError(w, []string{err.Error()})
+		return
+	}
+
+	options := domain.LeaderboardOptions{
+		Project: params.Get("project"),
+		Filter:  filter,
+	}
+
+	if params.Has("limit") {
+		limit, err := strconv.Atoi(params.Get("limit"))
+		if err != nil || limit < 0 {
+			sendValidationError(w, []string{"limit must be a positive number"})
+			return
+		}
+		options.Limit = limit
+	}
+
+	board, err := s.progressService.Leaderboard(r.Context(), options)
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, LeaderboardResponse{
+		Leaderboard: *board,
+	})
+}
+
+// @Summary Late Submissions
+// @Description lists RSS projects finished after their deadlines, the most delayed first
+// @Security UsersAuth
+// @Tags progress
+// @Success 200 {object} LateSubmissionsResponse
+// @Param project query string false "project name"
+// @Param filter query string false "filter expression, e.g. student_rss.total_score>=100"
+// @Failure 401
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /progress/late [get]
+func (s *Server) listLateSubmissions(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	filter, err := query.Parse(params.Get("filter"), s.studentFilterFields())
+	if err != nil {
+		sendValidationError(w, []string{err.Error()})
+		return
+	}
+
+	late, err := s.progressService.LateSubmissions(r.Context(), domain.ProgressOptions{
+		Project: params.Get("project"),
+		Filter:  filter,
+	})
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, LateSubmissionsResponse{
+		Late: late,
+	})
+}
+
+// @Summary Students Progress
+// @Description lists submitted, late and missing projects of RSS students and flags at-risk students by configured rules
+// @Security UsersAuth
+// @Tags progress
+// @Success 200 {object} StudentsProgressResponse
+// @Param at_risk query bool false "at-risk students only"
+// @Param filter query string false "filter expression, e.g. student_rss.total_score>=100"
+// @Failure 401
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /progress/students [get]
+func (s *Server) listStudentsProgress(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	filter, err := query.Parse(params.Get("filter"), s.studentFilterFields())
+	if err != nil {
+		sendValidationError(w, []string{err.Error()})
+		return
+	}
+
+	options := domain.ProgressOptions{
+		Filter: filter,
+	}
+
+	if params.Has("at_risk") {
+		atRisk, err := strconv.ParseBool(params.Get("at_risk"))
+		if err != nil {
+			sendValidationError(w, []string{"at_risk must be a boolean"})
+			return
+		}
+		options.AtRisk = atRisk
+	}
+
+	progress, err := s.progressService.ListProgress(r.Context(), options)
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, StudentsProgressResponse{
+		Progress: progress,
+	})
+}
+
+// @Summary Student Progress
+// @Description shows submitted, late and missing projects of RSS student and at-risk flags
+// @Security UsersAuth
+// @Tags progress
+// @Success 200 {object} StudentProgressResponse
+// @Param id path string true "student id"
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /progress/students/{id} [get]
+func (s *Server) getStudentProgress(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if id == "" {
+		sendUnprocessableEntityError(w, errors.New("id should not be empty"))
+		return
+	}
+
+	progress, err := s.progressService.GetStudentProgress(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			sendNotFoundError(w)
+			return
+		}
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, StudentProgressResponse{
+		Progress: *progress,
+	})
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+)
+
+type QualityReportResponse struct {
+	Quality domain.QualityReport `json:"quality"`
+}
+
+// @Summary Data Quality Report
+// @Description counts missing, invalid and duplicate emails, out-of-range project scores, unparsable dates and empty required fields of students in total, per source and per import file
+// @Security UsersAuth
+// @Tags stats
+// @Success 200 {object} QualityReportResponse
+// @Param source query string false "source"
+// @Failure 401
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /quality [get]
+func (s *Server) getQualityReport(w http.ResponseWriter, r *http.Request) {
+	report, err := s.qualityService.Report(r.Context(), domain.QualityOptions{
+		Source: r.URL.Query().Get("source"),
+	})
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, QualityReportResponse{
+		Quality: *report,
+	})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/pkg/logger"
+	"github.com/abdukhashimov/student_aggregator/pkg/export"
+)
+
+const jsonFormat = "json"
+
+type EngagementReportResponse struct {
+	Report domain.EngagementReport `json:"report"`
+}
+
+// @Summary Engagement Report
+// @Description joins RSS students with WAC community members by email and correlates community participation with project scores and completion
+// @Security UsersAuth
+// @Tags reports
+// @Success 200 {object} EngagementReportResponse
+// @Param format query string false "report format, json by default" Enums(json, xlsx)
+// @Failure 401
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Router /reports/engagement [get]
+func (s *Server) getEngagementReport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = jsonFormat
+	}
+
+	switch format {
+	case jsonFormat:
+		report, err := s.reportsService.EngagementReport(r.Context())
+		if err != nil {
+			sendServerError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, EngagementReportResponse{
+			Report: *report,
+		})
+	case export.XLSX:
+		w.Header().Set("Content-Type", export.ContentType(format))
+		w.Header().Set("Content-Disposition", "attachment; filename=\"engagement.xlsx\"")
+
+		out := &countingWriter{w: w}
+		if err := s.reportsService.ExportEngagementReport(r.Context(), out); err != nil {
+			if out.n > 0 {
+				// the response is already sent partially, so only log the error
+				logger.Log.Error(err)
+				return
+			}
+
+			w.Header().Del("Content-Disposition")
+			sendServerError(w, err)
+		}
+	default:
+		sendValidationError(w, []string{"format should be one of json, xlsx"})
+	}
+}
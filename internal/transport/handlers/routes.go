@@ -37,11 +37,50 @@ func (s *Server) routes() {
 		// aggregator
 		authApiRoutes.Handle("/aggregator/parse", validatorWrapper[domain.ParseFileInput](s.parseFile)).Methods(http.MethodPost)
 		// student
+		authApiRoutes.Handle("/students/export", http.HandlerFunc(s.exportStudents)).Methods(http.MethodGet)
+		authApiRoutes.Handle("/students/search", http.HandlerFunc(s.searchStudents)).Methods(http.MethodGet)
+		authApiRoutes.Handle("/students/duplicates", http.HandlerFunc(s.listDuplicates)).Methods(http.MethodGet)
+		authApiRoutes.Handle("/students/duplicates/merge", validatorWrapper[domain.MergeStudentsInput](s.mergeDuplicates)).Methods(http.MethodPost)
+		authApiRoutes.Handle("/students/bulk", validatorWrapper[domain.BulkStudentsInput](s.bulkStudents)).Methods(http.MethodPost)
 		authApiRoutes.Handle("/students/{id}", http.HandlerFunc(s.getStudentById)).Methods(http.MethodGet)
+		authApiRoutes.Handle("/students/{id}/history", http.HandlerFunc(s.getStudentHistory)).Methods(http.MethodGet)
+		authApiRoutes.Handle("/students/{id}/history/{version}/restore", http.HandlerFunc(s.restoreStudent)).Methods(http.MethodPost)
+		authApiRoutes.Handle("/students/{id}/status", validatorWrapper[domain.StatusInput](s.changeStudentStatus)).Methods(http.MethodPost)
+		authApiRoutes.Handle("/students/{id}/tags", validatorWrapper[domain.TagInput](s.addStudentTag)).Methods(http.MethodPost)
+		authApiRoutes.Handle("/students/{id}/tags/{tag}", http.HandlerFunc(s.removeStudentTag)).Methods(http.MethodDelete)
+		authApiRoutes.Handle("/students/{id}/notes", validatorWrapper[domain.NoteInput](s.addStudentNote)).Methods(http.MethodPost)
+		authApiRoutes.Handle("/students/{id}/notes/{noteId}", validatorWrapper[domain.NoteInput](s.updateStudentNote)).Methods(http.MethodPut)
+		authApiRoutes.Handle("/students/{id}/notes/{noteId}", http.HandlerFunc(s.deleteStudentNote)).Methods(http.MethodDelete)
+		authApiRoutes.Handle("/students/{id}/annotations/{key}", validatorWrapper[domain.AnnotationInput](s.setStudentAnnotation)).Methods(http.MethodPut)
+		authApiRoutes.Handle("/students/{id}/annotations/{key}", http.HandlerFunc(s.deleteStudentAnnotation)).Methods(http.MethodDelete)
 		authApiRoutes.Handle("/students", http.HandlerFunc(s.listStudents)).Methods(http.MethodGet)
 		authApiRoutes.Handle("/students/{id}", http.HandlerFunc(s.updateStudent)).Methods(http.MethodPut)
+		authApiRoutes.Handle("/students/{id}", http.HandlerFunc(s.patchStudent)).Methods(http.MethodPatch)
 		authApiRoutes.Handle("/students-by-file-name/{fileName}", http.HandlerFunc(s.deleteStudentByFileName)).Methods(http.MethodDelete)
 		authApiRoutes.Handle("/students/{id}", http.HandlerFunc(s.deleteStudent)).Methods(http.MethodDelete)
+		// people
+		authApiRoutes.Handle("/people/{email}", http.HandlerFunc(s.getPersonProfile)).Methods(http.MethodGet)
+		authApiRoutes.Handle("/people/{email}/export", http.HandlerFunc(s.exportPerson)).Methods(http.MethodGet)
+		authApiRoutes.Handle("/people/{email}/erase", validatorWrapper[domain.EraseInput](s.erasePerson)).Methods(http.MethodPost)
+		authApiRoutes.Handle("/erasures", http.HandlerFunc(s.listErasures)).Methods(http.MethodGet)
+
+		authApiRoutes.Handle("/stats/students", http.HandlerFunc(s.getStudentsStats)).Methods(http.MethodGet)
+		authApiRoutes.Handle("/stats/timeseries", http.HandlerFunc(s.getTimeSeries)).Methods(http.MethodGet)
+		// progress
+		authApiRoutes.Handle("/progress/leaderboard", http.HandlerFunc(s.getLeaderboard)).Methods(http.MethodGet)
+		authApiRoutes.Handle("/progress/late", http.HandlerFunc(s.listLateSubmissions)).Methods(http.MethodGet)
+		authApiRoutes.Handle("/progress/students", http.HandlerFunc(s.listStudentsProgress)).Methods(http.MethodGet)
+		authApiRoutes.Handle("/progress/students/{id}", http.HandlerFunc(s.getStudentProgress)).Methods(http.MethodGet)
+		// reports
+		authApiRoutes.Handle("/reports/engagement", http.HandlerFunc(s.getEngagementReport)).Methods(http.MethodGet)
+		// data quality
+		authApiRoutes.Handle("/quality", http.HandlerFunc(s.getQualityReport)).Methods(http.MethodGet)
+		// workflow
+		authApiRoutes.Handle("/workflow", http.HandlerFunc(s.getWorkflow)).Methods(http.MethodGet)
+		// trash
+		authApiRoutes.Handle("/trash", http.HandlerFunc(s.listTrash)).Methods(http.MethodGet)
+		authApiRoutes.Handle("/trash/students/{id}/restore", http.HandlerFunc(s.restoreStudentFromTrash)).Methods(http.MethodPost)
+		authApiRoutes.Handle("/trash/schemas/{id}/restore", http.HandlerFunc(s.restoreSchemaFromTrash)).Methods(http.MethodPost)
 
 	}
 }
@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
 	"github.com/gorilla/mux"
@@ -13,6 +14,7 @@ type SchemaResponse struct {
 }
 type SchemasResponse struct {
 	Schemas []domain.Schema `json:"schemas"`
+	domain.PageInfo
 }
 
 // @Summary List Schemas
@@ -20,20 +22,46 @@ type SchemasResponse struct {
 // @Security UsersAuth
 // @Tags schema
 // @Success 200 {object} SchemasResponse
+// @Param limit query int false "limit, 20 by default, 100 at most"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param with_total query bool false "count total number of schemas"
+// @Param sort query string false "comma separated sort fields, minus prefix for descending order, e.g. -version,name"
+// @Param with_deleted query bool false "include schemas moved to trash"
 // @Failure 401
+// @Failure 422
 // @Failure 500
 // @Accept json
 // @Produce json
 // @Router /schemas [get]
 func (s *Server) listSchemas(w http.ResponseWriter, r *http.Request) {
-	schemas, err := s.schemasService.ListSchemas(r.Context())
+	params := r.URL.Query()
+
+	sort, err := domain.ParseSort(params.Get("sort"), domain.SchemaSortFields)
+	if err != nil {
+		sendValidationError(w, []string{err.Error()})
+		return
+	}
+
+	withDeleted, _ := strconv.ParseBool(params.Get("with_deleted"))
+
+	schemas, page, err := s.schemasService.ListSchemas(r.Context(), domain.ListSchemasOptions{
+		Sort:        sort,
+		WithDeleted: withDeleted,
+		PageOptions: getPageOptions(params),
+	})
 	if err != nil {
+		if err == domain.ErrInvalidCursor {
+			sendValidationError(w, []string{err.Error()})
+			return
+		}
 		sendServerError(w, err)
 		return
 	}
 
+	setLinkHeader(w, r, page)
 	writeJSON(w, http.StatusOK, SchemasResponse{
-		Schemas: schemas,
+		Schemas:  schemas,
+		PageInfo: *page,
 	})
 }
 
@@ -75,6 +103,7 @@ func (s *Server) createSchema(w http.ResponseWriter, r *http.Request) {
 // @Security UsersAuth
 // @Tags schema
 // @Success 200 {object} SchemaResponse
+// @Header 200 {string} ETag "schema revision"
 // @Param id path string true "schema id"
 // @Failure 404
 // @Failure 500
@@ -101,19 +130,23 @@ func (s *Server) getSchemaById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, schema.Revision)
 	writeJSON(w, http.StatusOK, SchemaResponse{
 		Schema: *schema,
 	})
 }
 
 // @Summary Update Schema By ID
-// @Description update schema by id
+// @Description update schema by id, the schema is updated only if it has the revision of If-Match header when it is set
 // @Security UsersAuth
 // @Tags schema
 // @Param id path string true "schema id"
+// @Param If-Match header string false "ETag of the schema revision"
 // @Param input body domain.UpdateSchemaInput true "update info"
 // @Success 200 {object} SchemaResponse
+// @Header 200 {string} ETag "schema revision"
 // @Failure 404
+// @Failure 412
 // @Failure 422
 // @Failure 500
 // @Accept  json
@@ -133,8 +166,18 @@ func (s *Server) updateSchema(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	schema, err := s.schemasService.UpdateSchema(r.Context(), id, *input)
+	revision, ok := ifMatchRevision(r)
+	if !ok {
+		sendPreconditionFailedError(w)
+		return
+	}
+
+	schema, err := s.schemasService.UpdateSchema(r.Context(), id, *input, revision)
 	if err != nil {
+		if err == domain.ErrPreconditionFailed {
+			sendPreconditionFailedError(w)
+			return
+		}
 		if err == domain.DuplicationError {
 			sendDuplicatedError(w, "name")
 			return
@@ -147,13 +190,14 @@ func (s *Server) updateSchema(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, schema.Revision)
 	writeJSON(w, http.StatusOK, SchemaResponse{
 		Schema: *schema,
 	})
 }
 
 // @Summary Delete Schema
-// @Description delete schema
+// @Description moves schema to trash, it can be restored until it is purged
 // @Security UsersAuth
 // @Tags schema
 // @Param id path string true "schema id"
@@ -53,9 +53,36 @@ var SchemasTestCases = []SchemaTestCaseGroup{
 		testCases: []SchemaTestCase{
 			{
 				name:         "success",
-				expectedBody: `{"schemas":[{"id":"1","name":"RSS","version":"1.0.0","schema_type":"coords","headers":true,"fields":[{"col":"A","name":"first_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"last_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}]},{"id":"2","name":"WAC","version":"1.0.0","schema_type":"coords","headers":true,"fields":[{"col":"A","name":"name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"surname","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}]}]}`,
+				expectedBody: `{"schemas":[{"id":"1","name":"RSS","version":"1.0.0","schema_type":"coords","headers":true,"fields":[{"col":"A","name":"first_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"last_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}]},{"id":"2","name":"WAC","version":"1.0.0","schema_type":"coords","headers":true,"fields":[{"col":"A","name":"name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"surname","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}]}],"has_more":false}`,
 				expectedCode: http.StatusOK,
 			},
+			{
+				name: "firstPage",
+				prepareRequest: func(r *http.Request) *http.Request {
+					r.URL.RawQuery = "limit=1&with_total=true"
+					return r
+				},
+				expectedBody: `{"schemas":[{"id":"1","name":"RSS","version":"1.0.0","schema_type":"coords","headers":true,"fields":[{"col":"A","name":"first_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"last_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}]}],"next_cursor":"1","has_more":true,"total":2}`,
+				expectedCode: http.StatusOK,
+			},
+			{
+				name: "nextPage",
+				prepareRequest: func(r *http.Request) *http.Request {
+					r.URL.RawQuery = "limit=1&cursor=1"
+					return r
+				},
+				expectedBody: `{"schemas":[{"id":"2","name":"WAC","version":"1.0.0","schema_type":"coords","headers":true,"fields":[{"col":"A","name":"name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"surname","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}]}],"has_more":false}`,
+				expectedCode: http.StatusOK,
+			},
+			{
+				name: "invalidSort",
+				prepareRequest: func(r *http.Request) *http.Request {
+					r.URL.RawQuery = "sort=-password"
+					return r
+				},
+				expectedBody: `{"errors":["sort: unknown field \"password\", allowed fields are id, name, schema_type, version"]}`,
+				expectedCode: http.StatusUnprocessableEntity,
+			},
 			{
 				name: "internalError",
 				prepareRequest: func(r *http.Request) *http.Request {
@@ -86,7 +113,7 @@ var SchemasTestCases = []SchemaTestCaseGroup{
 						{Name: "email", Col: "C"},
 					},
 				},
-				expectedBody: `{"schema":{"id":"3","name":"New Schema","version":"1.0.0","schema_type":"coords","headers":true,"fields":[{"col":"A","name":"name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"surname","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}]}}`,
+				expectedBody: `{"schema":{"id":"3","name":"New Schema","version":"1.0.0","schema_type":"coords","headers":true,"fields":[{"col":"A","name":"name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"surname","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}],"revision":1}}`,
 				expectedCode: http.StatusCreated,
 			},
 			{
@@ -190,7 +217,7 @@ var SchemasTestCases = []SchemaTestCaseGroup{
 					Headers:    &updateSchemaHeaders,
 					Fields:     &updateSchemaFields,
 				},
-				expectedBody: `{"schema":{"id":"1","name":"updateSchemaName","version":"updateSchemaVersion","schema_type":"updateSchemaSchemaType","headers":false,"fields":[{"col":"D","name":"name","is_multiple":false,"is_map":false,"map_start":false},{"col":"E","name":"surname","is_multiple":false,"is_map":false,"map_start":false},{"col":"F","name":"em","is_multiple":false,"is_map":false,"map_start":false}]}}`,
+				expectedBody: `{"schema":{"id":"1","name":"updateSchemaName","version":"updateSchemaVersion","schema_type":"updateSchemaSchemaType","headers":false,"fields":[{"col":"D","name":"name","is_multiple":false,"is_map":false,"map_start":false},{"col":"E","name":"surname","is_multiple":false,"is_map":false,"map_start":false},{"col":"F","name":"em","is_multiple":false,"is_map":false,"map_start":false}],"revision":1}}`,
 				expectedCode: http.StatusOK,
 			},
 			{
@@ -204,7 +231,7 @@ var SchemasTestCases = []SchemaTestCaseGroup{
 				requestInput: &domain.UpdateSchemaInput{
 					Name: &updateSchemaName,
 				},
-				expectedBody: `{"schema":{"id":"1","name":"updateSchemaName","version":"1.0.0","schema_type":"coords","headers":true,"fields":[{"col":"A","name":"first_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"last_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}]}}`,
+				expectedBody: `{"schema":{"id":"1","name":"updateSchemaName","version":"1.0.0","schema_type":"coords","headers":true,"fields":[{"col":"A","name":"first_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"last_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}],"revision":1}}`,
 				expectedCode: http.StatusOK,
 			},
 			{
@@ -218,7 +245,7 @@ var SchemasTestCases = []SchemaTestCaseGroup{
 				requestInput: &domain.UpdateSchemaInput{
 					Version: &updateSchemaVersion,
 				},
-				expectedBody: `{"schema":{"id":"1","name":"RSS","version":"updateSchemaVersion","schema_type":"coords","headers":true,"fields":[{"col":"A","name":"first_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"last_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}]}}`,
+				expectedBody: `{"schema":{"id":"1","name":"RSS","version":"updateSchemaVersion","schema_type":"coords","headers":true,"fields":[{"col":"A","name":"first_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"last_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}],"revision":1}}`,
 				expectedCode: http.StatusOK,
 			},
 			{
@@ -232,7 +259,7 @@ var SchemasTestCases = []SchemaTestCaseGroup{
 				requestInput: &domain.UpdateSchemaInput{
 					SchemaType: &updateSchemaSchemaType,
 				},
-				expectedBody: `{"schema":{"id":"1","name":"RSS","version":"1.0.0","schema_type":"updateSchemaSchemaType","headers":true,"fields":[{"col":"A","name":"first_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"last_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}]}}`,
+				expectedBody: `{"schema":{"id":"1","name":"RSS","version":"1.0.0","schema_type":"updateSchemaSchemaType","headers":true,"fields":[{"col":"A","name":"first_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"last_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}],"revision":1}}`,
 				expectedCode: http.StatusOK,
 			},
 			{
@@ -246,7 +273,7 @@ var SchemasTestCases = []SchemaTestCaseGroup{
 				requestInput: &domain.UpdateSchemaInput{
 					Headers: &updateSchemaHeaders,
 				},
-				expectedBody: `{"schema":{"id":"1","name":"RSS","version":"1.0.0","schema_type":"coords","headers":false,"fields":[{"col":"A","name":"first_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"last_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}]}}`,
+				expectedBody: `{"schema":{"id":"1","name":"RSS","version":"1.0.0","schema_type":"coords","headers":false,"fields":[{"col":"A","name":"first_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"B","name":"last_name","is_multiple":false,"is_map":false,"map_start":false},{"col":"C","name":"email","is_multiple":false,"is_map":false,"map_start":false}],"revision":1}}`,
 				expectedCode: http.StatusOK,
 			},
 			{
@@ -260,7 +287,7 @@ var SchemasTestCases = []SchemaTestCaseGroup{
 				requestInput: &domain.UpdateSchemaInput{
 					Fields: &updateSchemaFields,
 				},
-				expectedBody: `{"schema":{"id":"1","name":"RSS","version":"1.0.0","schema_type":"coords","headers":true,"fields":[{"col":"D","name":"name","is_multiple":false,"is_map":false,"map_start":false},{"col":"E","name":"surname","is_multiple":false,"is_map":false,"map_start":false},{"col":"F","name":"em","is_multiple":false,"is_map":false,"map_start":false}]}}`,
+				expectedBody: `{"schema":{"id":"1","name":"RSS","version":"1.0.0","schema_type":"coords","headers":true,"fields":[{"col":"D","name":"name","is_multiple":false,"is_map":false,"map_start":false},{"col":"E","name":"surname","is_multiple":false,"is_map":false,"map_start":false},{"col":"F","name":"em","is_multiple":false,"is_map":false,"map_start":false}],"revision":1}}`,
 				expectedCode: http.StatusOK,
 			},
 			{
@@ -292,6 +319,36 @@ var SchemasTestCases = []SchemaTestCaseGroup{
 				expectedBody: `{"errors":"resource not found"}`,
 				expectedCode: http.StatusNotFound,
 			},
+			{
+				name: "revisionMismatch",
+				prepareRequest: func(r *http.Request) *http.Request {
+					r = mux.SetURLVars(r, map[string]string{
+						"id": schemas.ValidSchemaID1,
+					})
+					r.Header.Set("If-Match", `"5"`)
+					return r
+				},
+				requestInput: &domain.UpdateSchemaInput{
+					Name: &updateSchemaName,
+				},
+				expectedBody: `{"errors":"resource was modified, get it again and retry"}`,
+				expectedCode: http.StatusPreconditionFailed,
+			},
+			{
+				name: "invalidIfMatch",
+				prepareRequest: func(r *http.Request) *http.Request {
+					r = mux.SetURLVars(r, map[string]string{
+						"id": schemas.ValidSchemaID1,
+					})
+					r.Header.Set("If-Match", `W/"1"`)
+					return r
+				},
+				requestInput: &domain.UpdateSchemaInput{
+					Name: &updateSchemaName,
+				},
+				expectedBody: `{"errors":"resource was modified, get it again and retry"}`,
+				expectedCode: http.StatusPreconditionFailed,
+			},
 			{
 				name: "internalError",
 				prepareRequest: func(r *http.Request) *http.Request {
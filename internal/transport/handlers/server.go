@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -21,15 +22,33 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// exportWriteTimeout limits writing of streamed exports, which take longer than the server write timeout.
+const exportWriteTimeout = 10 * time.Minute
+
+// connContextKey keeps connection of the request in its context.
+type connContextKey struct{}
+
 type Server struct {
-	server            *http.Server
-	router            *mux.Router
-	userService       ports.UsersService
-	schemasService    ports.SchemaService
-	studentsService   ports.StudentsService
-	storageService    ports.StorageService
-	aggregatorService ports.AggregatorService
-	config            *config.Config
+	server             *http.Server
+	router             *mux.Router
+	userService        ports.UsersService
+	schemasService     ports.SchemaService
+	studentsService    ports.StudentsService
+	storageService     ports.StorageService
+	aggregatorService  ports.AggregatorService
+	duplicatesService  ports.DuplicatesService
+	peopleService      ports.PeopleService
+	statsService       ports.StatsService
+	progressService    ports.ProgressService
+	reportsService     ports.ReportsService
+	trashService       ports.TrashService
+	annotationsService ports.AnnotationsService
+	workflowService    ports.WorkflowService
+	privacyService     ports.PrivacyService
+	qualityService     ports.QualityService
+	config             *config.Config
+	// stopJobs stops background jobs on shutdown.
+	stopJobs context.CancelFunc
 }
 
 func NewServer(db *mongo.Database, storageClient *minio.Client, cfg *config.Config) *Server {
@@ -38,25 +57,48 @@ func NewServer(db *mongo.Database, storageClient *minio.Client, cfg *config.Conf
 			WriteTimeout: 5 * time.Second,
 			ReadTimeout:  5 * time.Second,
 			IdleTimeout:  5 * time.Second,
+			ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+				return context.WithValue(ctx, connContextKey{}, c)
+			},
 		},
 		router: mux.NewRouter().StrictSlash(true),
 		config: cfg,
 	}
 
-	repos := mongodb.NewRepositories(db)
+	repos, err := mongodb.NewRepositories(db, cfg)
+	if err != nil {
+		panic(err)
+	}
 	logger.Log.Info("repositories successfully initialized")
 
-	servs := services.NewServices(repos, cfg)
+	servs, err := services.NewServices(repos, cfg)
+	if err != nil {
+		panic(err)
+	}
 	s.userService = servs.Users
 	s.schemasService = servs.Schemas
 	s.studentsService = servs.Students
 	s.aggregatorService = servs.Aggregator
+	s.duplicatesService = servs.Duplicates
+	s.peopleService = servs.People
+	s.statsService = servs.Stats
+	s.progressService = servs.Progress
+	s.reportsService = servs.Reports
+	s.trashService = servs.Trash
+	s.annotationsService = servs.Annotations
+	s.workflowService = servs.Workflow
+	s.privacyService = servs.Privacy
+	s.qualityService = servs.Quality
 
 	s.storageService = servs.Storage
 	s.storageService.SetClient(storageClient)
 
 	logger.Log.Info("services successfully initialized")
 
+	var jobsCtx context.Context
+	jobsCtx, s.stopJobs = context.WithCancel(context.Background())
+	go s.trashService.RunPurgeJob(jobsCtx)
+
 	s.routes()
 	s.server.Handler = s.router
 	logger.Log.Info("handlers successfully initialized")
@@ -99,6 +141,22 @@ func (s *Server) Run(port string) error {
 	return s.server.ListenAndServe()
 }
 
+// extendWriteTimeout lets the response to r be written for timeout from now instead of the server write timeout.
+// The server sets its write timeout again before reading the next request of the connection.
+func extendWriteTimeout(r *http.Request, timeout time.Duration) {
+	conn, ok := r.Context().Value(connContextKey{}).(net.Conn)
+	if !ok {
+		return
+	}
+
+	if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		logger.Log.Error(err)
+	}
+}
+
 func (s *Server) Shutdown(ctx context.Context) {
+	if s.stopJobs != nil {
+		s.stopJobs()
+	}
 	_ = s.server.Shutdown(ctx)
 }
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExtendWriteTimeout(t *testing.T) {
+	const writeTimeout = 50 * time.Millisecond
+
+	for _, extend := range []bool{false, true} {
+		ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if extend {
+				extendWriteTimeout(r, time.Minute)
+			}
+			time.Sleep(2 * writeTimeout)
+			_, _ = w.Write([]byte("students"))
+		}))
+		ts.Config.WriteTimeout = writeTimeout
+		ts.Config.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, connContextKey{}, c)
+		}
+		ts.Start()
+
+		var body []byte
+		resp, err := http.Get(ts.URL)
+		if err == nil {
+			body, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+		ts.Close()
+
+		if extend && (err != nil || string(body) != "students") {
+			t.Errorf("extended response = %q, %v, want the whole body", body, err)
+		}
+		if !extend && err == nil {
+			t.Errorf("response = %q, want it cut by the write timeout", body)
+		}
+	}
+}
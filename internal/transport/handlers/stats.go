@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
+)
+
+type StudentsStatsResponse struct {
+	Stats domain.StudentsStats `json:"stats"`
+}
+
+type TimeSeriesResponse struct {
+	TimeSeries domain.TimeSeries `json:"time_series"`
+}
+
+// @Summary Students Statistics
+// @Description counts students by source, status, file, location and membership type, RSS project scores distribution and WAC attendance
+// @Security UsersAuth
+// @Tags stats
+// @Success 200 {object} StudentsStatsResponse
+// @Param source query string false "source"
+// @Param filter query string false "filter expression, e.g. student_rss.projects.score>=80 AND student_wac.attended_events>2"
+// @Failure 401
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /stats/students [get]
+func (s *Server) getStudentsStats(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	filter, err := query.Parse(params.Get("filter"), s.studentFilterFields())
+	if err != nil {
+		sendValidationError(w, []string{err.Error()})
+		return
+	}
+
+	stats, err := s.statsService.StudentsStats(r.Context(), domain.StudentsStatsOptions{
+		Source: params.Get("source"),
+		Filter: filter,
+	})
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, StudentsStatsResponse{
+		Stats: *stats,
+	})
+}
+
+// @Summary Students Time Series
+// @Description counts RSS applications, WAC joins or RSS project completions per time interval
+// @Security UsersAuth
+// @Tags stats
+// @Success 200 {object} TimeSeriesResponse
+// @Param metric query string true "counted metric" Enums(applications, joins, completions)
+// @Param interval query string false "time bucket, month by default" Enums(day, week, month, year)
+// @Param group_by query string false "group by dimension, project is available for completions only" Enums(source, status, location, membership_type, project)
+// @Param from query string false "inclusive start date, e.g. 2022-01-01"
+// @Param to query string false "exclusive end date, e.g. 2023-01-01"
+// @Param source query string false "source"
+// @Param filter query string false "filter expression, e.g. student_rss.projects.score>=80 AND student_wac.attended_events>2"
+// @Failure 401
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /stats/timeseries [get]
+func (s *Server) getTimeSeries(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	filter, err := query.Parse(params.Get("filter"), s.studentFilterFields())
+	if err != nil {
+		sendValidationError(w, []string{err.Error()})
+		return
+	}
+
+	options := domain.TimeSeriesOptions{
+		Metric:   params.Get("metric"),
+		Interval: params.Get("interval"),
+		GroupBy:  params.Get("group_by"),
+		Source:   params.Get("source"),
+		Filter:   filter,
+	}
+	if options.Interval == "" {
+		options.Interval = domain.IntervalMonth
+	}
+
+	dates := []struct {
+		name string
+		dst  *time.Time
+	}{
+		{"from", &options.From},
+		{"to", &options.To},
+	}
+	for _, date := range dates {
+		if !params.Has(date.name) {
+			continue
+		}
+		t, err := query.ParseDate(params.Get(date.name))
+		if err != nil {
+			sendValidationError(w, []string{fmt.Sprintf("%s should be a date like 2006-01-02", date.name)})
+			return
+		}
+		*date.dst = t
+	}
+
+	ts, err := s.statsService.TimeSeries(r.Context(), options)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidParams) {
+			sendValidationError(w, []string{err.Error()})
+			return
+		}
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TimeSeriesResponse{
+		TimeSeries: *ts,
+	})
+}
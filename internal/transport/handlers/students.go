@@ -2,17 +2,36 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/pkg/logger"
+	"github.com/abdukhashimov/student_aggregator/pkg/export"
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
 	"github.com/gorilla/mux"
 )
 
+// maxPatchSize limits size of student patch documents.
+const maxPatchSize = 1 << 20
+
 type StudentResponse struct {
 	Student domain.StudentRecord `json:"student"`
 }
 type StudentsResponse struct {
 	Students []domain.StudentRecord `json:"students"`
+	domain.PageInfo
+}
+type StudentHistoryResponse struct {
+	History []domain.HistoryEntry `json:"history"`
+}
+type StudentsSearchResponse struct {
+	Results []domain.StudentSearchResult `json:"results"`
 }
 
 // @Summary Get Student By ID
@@ -20,6 +39,7 @@ type StudentsResponse struct {
 // @Security UsersAuth
 // @Tags student
 // @Success 200 {object} StudentResponse
+// @Header 200 {string} ETag "student revision"
 // @Param id path string true "student id"
 // @Failure 404
 // @Failure 500
@@ -46,6 +66,7 @@ func (s *Server) getStudentById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, student.Revision)
 	writeJSON(w, http.StatusOK, StudentResponse{
 		Student: *student,
 	})
@@ -56,46 +77,196 @@ func (s *Server) getStudentById(w http.ResponseWriter, r *http.Request) {
 // @Security UsersAuth
 // @Tags student
 // @Success 200 {object} StudentsResponse
-// @Param limit query int false "limit"
-// @Param skip query int false "skip"
+// @Param limit query int false "limit, 20 by default, 100 at most"
+// @Param skip query int false "skip, ignored with cursor"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param with_total query bool false "count total number of students"
 // @Param email query string false "email"
 // @Param source query string false "source"
-// @Param sort query string false "sort"
+// @Param sort query string false "comma separated sort fields, minus prefix for descending order, e.g. -score,last_name"
+// @Param tag query string false "tag name"
+// @Param filter query string false "filter expression, e.g. student_rss.projects.score>=80 AND student_wac.attended_events>2"
+// @Param with_deleted query bool false "include students moved to trash"
 // @Failure 401
+// @Failure 422
 // @Failure 500
 // @Accept json
 // @Produce json
 // @Router /students [get]
 func (s *Server) listStudents(w http.ResponseWriter, r *http.Request) {
 	params := r.URL.Query()
-	limit, skip := getLimitSkip(params)
-	sort := getSort(params)
 
-	students, err := s.studentsService.ListStudents(r.Context(), domain.ListStudentsOptions{
-		Email:  params.Get("email"),
+	options, err := s.listStudentsOptions(params)
+	if err != nil {
+		sendValidationError(w, []string{err.Error()})
+		return
+	}
+	options.PageOptions = getPageOptions(params)
+
+	students, page, err := s.studentsService.ListStudents(r.Context(), options)
+	if err != nil {
+		if err == domain.ErrInvalidCursor {
+			sendValidationError(w, []string{err.Error()})
+			return
+		}
+		sendServerError(w, err)
+		return
+	}
+
+	setLinkHeader(w, r, page)
+	writeJSON(w, http.StatusOK, StudentsResponse{
+		Students: students,
+		PageInfo: *page,
+	})
+}
+
+// @Summary Export Students
+// @Description exports students matching the same filters as students list to CSV or XLSX file
+// @Security UsersAuth
+// @Tags student
+// @Success 200 {file} file
+// @Param format query string true "file format" Enums(csv, xlsx)
+// @Param columns query string false "comma separated columns, projects adds project_N_* columns, all columns by default"
+// @Param schema_id query string false "export in the layout of the schema to import the file back, columns are ignored"
+// @Param email query string false "email"
+// @Param source query string false "source"
+// @Param sort query string false "comma separated sort fields, minus prefix for descending order, e.g. -score,last_name"
+// @Param tag query string false "tag name"
+// @Param filter query string false "filter expression, e.g. student_rss.projects.score>=80 AND student_wac.attended_events>2"
+// @Param with_deleted query bool false "include students moved to trash"
+// @Failure 401
+// @Failure 404
+// @Failure 422
+// @Failure 500
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Router /students/export [get]
+func (s *Server) exportStudents(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	format := params.Get("format")
+	if format != export.CSV && format != export.XLSX {
+		sendValidationError(w, []string{"format should be one of csv, xlsx"})
+		return
+	}
+
+	options, err := s.listStudentsOptions(params)
+	if err != nil {
+		sendValidationError(w, []string{err.Error()})
+		return
+	}
+
+	var columns []string
+	if params.Get("columns") != "" {
+		columns = strings.Split(params.Get("columns"), ",")
+	}
+
+	extendWriteTimeout(r, exportWriteTimeout)
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"students.%s\"", format))
+
+	out := &countingWriter{w: w}
+	err = s.studentsService.ExportStudents(r.Context(), domain.ExportStudentsOptions{
+		Format:              format,
+		Columns:             columns,
+		SchemaID:            params.Get("schema_id"),
+		ListStudentsOptions: options,
+	}, out)
+	if err != nil {
+		if out.n > 0 {
+			// the response is already sent partially, so only log the error
+			logger.Log.Error(err)
+			return
+		}
+
+		w.Header().Del("Content-Disposition")
+		if errors.Is(err, domain.ErrUnknownColumn) {
+			sendValidationError(w, []string{err.Error()})
+			return
+		}
+		if err == domain.ErrNotFound {
+			sendNotFoundError(w)
+			return
+		}
+		sendServerError(w, err)
+	}
+}
+
+// listStudentsOptions reads students list filters and sorting shared by list and export, page options are not read.
+func (s *Server) listStudentsOptions(params url.Values) (domain.ListStudentsOptions, error) {
+	sort, err := domain.ParseSort(params.Get("sort"), s.studentSortFields())
+	if err != nil {
+		return domain.ListStudentsOptions{}, err
+	}
+
+	filter, err := query.Parse(params.Get("filter"), s.studentFilterFields())
+	if err != nil {
+		return domain.ListStudentsOptions{}, err
+	}
+
+	withDeleted, _ := strconv.ParseBool(params.Get("with_deleted"))
+
+	return domain.ListStudentsOptions{
+		Email:       params.Get("email"),
+		Source:      params.Get("source"),
+		Tag:         domain.NormalizeTag(params.Get("tag")),
+		Filter:      filter,
+		Sort:        sort,
+		WithDeleted: withDeleted,
+	}, nil
+}
+
+// @Summary Search Students
+// @Description full-text search by names, email, company, location and project names ordered by relevance
+// @Security UsersAuth
+// @Tags student
+// @Success 200 {object} StudentsSearchResponse
+// @Param q query string true "search query"
+// @Param source query string false "source"
+// @Param limit query int false "limit, 20 by default, 100 at most"
+// @Failure 401
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /students/search [get]
+func (s *Server) searchStudents(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	limit, _ := getLimitSkip(params)
+
+	q := params.Get("q")
+	if strings.TrimSpace(q) == "" {
+		sendUnprocessableEntityError(w, errors.New("q should not be empty"))
+		return
+	}
+
+	results, err := s.studentsService.SearchStudents(r.Context(), domain.SearchStudentsOptions{
+		Query:  q,
 		Source: params.Get("source"),
-		Sort:   sort,
 		Limit:  limit,
-		Skip:   skip,
 	})
 	if err != nil {
 		sendServerError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, StudentsResponse{
-		Students: students,
+	writeJSON(w, http.StatusOK, StudentsSearchResponse{
+		Results: results,
 	})
 }
 
 // @Summary Update Student By ID
-// @Description update student by id
+// @Description update student by id, the student is updated only if it has the revision of If-Match header when it is set.
+// @Description source and merged_from are read-only, status, status_changes, tags, notes and annotations are changed by their own endpoints, they may be omitted or sent unchanged only.
 // @Security UsersAuth
 // @Tags student
 // @Param id path string true "student id"
+// @Param If-Match header string false "ETag of the student revision"
 // @Param input body domain.StudentRecord true "update info"
 // @Success 200 {object} StudentResponse
+// @Header 200 {string} ETag "student revision"
 // @Failure 404
+// @Failure 412
 // @Failure 422
 // @Failure 500
 // @Accept  json
@@ -115,23 +286,113 @@ func (s *Server) updateStudent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	student, err := s.studentsService.UpdateStudent(r.Context(), id, *input)
+	revision, ok := ifMatchRevision(r)
+	if !ok {
+		sendPreconditionFailedError(w)
+		return
+	}
+
+	student, err := s.studentsService.UpdateStudent(r.Context(), id, *input, revision)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			sendNotFoundError(w)
+			return
+		}
+		if err == domain.ErrPreconditionFailed {
+			sendPreconditionFailedError(w)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidParams) {
+			sendValidationError(w, []string{err.Error()})
+			return
+		}
+		sendServerError(w, err)
+		return
+	}
+
+	setETag(w, student.Revision)
+	writeJSON(w, http.StatusOK, StudentResponse{
+		Student: *student,
+	})
+}
+
+// @Summary Patch Student By ID
+// @Description applies JSON Merge Patch (application/merge-patch+json, also used for application/json) or JSON Patch (application/json-patch+json) to student JSON representation.
+// @Description id, source, file_name, imported_at and merged_from are read-only, total_score and parsed dates are recalculated.
+// @Security UsersAuth
+// @Tags student
+// @Param id path string true "student id"
+// @Param If-Match header string false "ETag of the student revision"
+// @Param input body object true "merge patch object or array of patch operations"
+// @Success 200 {object} StudentResponse
+// @Header 200 {string} ETag "student revision"
+// @Failure 404
+// @Failure 412
+// @Failure 415
+// @Failure 422
+// @Failure 500
+// @Accept  application/merge-patch+json,application/json-patch+json,json
+// @Produce  json
+// @Router /students/{id} [patch]
+func (s *Server) patchStudent(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if id == "" {
+		sendUnprocessableEntityError(w, errors.New("id should not be empty"))
+		return
+	}
+
+	patchType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || patchType == "application/json" {
+		patchType = domain.MergePatchType
+	}
+	if patchType != domain.MergePatchType && patchType != domain.JSONPatchType {
+		writeErrorResponse(w, http.StatusUnsupportedMediaType,
+			fmt.Sprintf("content type should be one of %s, %s", domain.MergePatchType, domain.JSONPatchType))
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPatchSize))
+	if err != nil {
+		sendUnprocessableEntityError(w, err)
+		return
+	}
+
+	revision, ok := ifMatchRevision(r)
+	if !ok {
+		sendPreconditionFailedError(w)
+		return
+	}
+
+	student, err := s.studentsService.PatchStudent(r.Context(), id, domain.StudentPatch{
+		Type: patchType,
+		Data: data,
+	}, revision)
 	if err != nil {
 		if err == domain.ErrNotFound {
 			sendNotFoundError(w)
 			return
 		}
+		if err == domain.ErrPreconditionFailed {
+			sendPreconditionFailedError(w)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidPatch) || errors.Is(err, domain.ErrInvalidParams) {
+			sendValidationError(w, []string{err.Error()})
+			return
+		}
 		sendServerError(w, err)
 		return
 	}
 
+	setETag(w, student.Revision)
 	writeJSON(w, http.StatusOK, StudentResponse{
 		Student: *student,
 	})
 }
 
 // @Summary Delete Student
-// @Description delete student
+// @Description moves student to trash, it can be restored until it is purged
 // @Security UsersAuth
 // @Tags student
 // @Param id path string true "student id"
@@ -189,3 +450,131 @@ func (s *Server) deleteStudentByFileName(w http.ResponseWriter, r *http.Request)
 
 	sendCode(w, http.StatusOK)
 }
+
+// @Summary Get Student History
+// @Description lists versions of student record with changed fields, who and how changed them
+// @Security UsersAuth
+// @Tags student
+// @Success 200 {object} StudentHistoryResponse
+// @Param id path string true "student id"
+// @Failure 404
+// @Failure 500
+// @Accept  json
+// @Produce  json
+// @Router /students/{id}/history [get]
+func (s *Server) getStudentHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if id == "" {
+		sendUnprocessableEntityError(w, errors.New("id should not be empty"))
+		return
+	}
+
+	history, err := s.studentsService.GetStudentHistory(r.Context(), id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			sendNotFoundError(w)
+			return
+		}
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, StudentHistoryResponse{
+		History: history,
+	})
+}
+
+// @Summary Restore Student Version
+// @Description restores student record to the version, deleted students are restored with the same id
+// @Security UsersAuth
+// @Tags student
+// @Param id path string true "student id"
+// @Param version path int true "version to restore"
+// @Param If-Match header string false "ETag of the student revision"
+// @Success 200 {object} StudentResponse
+// @Header 200 {string} ETag "student revision"
+// @Failure 404
+// @Failure 412
+// @Failure 422
+// @Failure 500
+// @Accept  json
+// @Produce  json
+// @Router /students/{id}/history/{version}/restore [post]
+func (s *Server) restoreStudent(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	if id == "" {
+		sendUnprocessableEntityError(w, errors.New("id should not be empty"))
+		return
+	}
+
+	version, err := strconv.Atoi(vars["version"])
+	if err != nil || version < 1 {
+		sendValidationError(w, []string{"version must be a positive number"})
+		return
+	}
+
+	revision, ok := ifMatchRevision(r)
+	if !ok {
+		sendPreconditionFailedError(w)
+		return
+	}
+
+	student, err := s.studentsService.RestoreStudent(r.Context(), id, version, revision)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			sendNotFoundError(w)
+			return
+		}
+		if err == domain.ErrPreconditionFailed {
+			sendPreconditionFailedError(w)
+			return
+		}
+		sendServerError(w, err)
+		return
+	}
+
+	setETag(w, student.Revision)
+	writeJSON(w, http.StatusOK, StudentResponse{
+		Student: *student,
+	})
+}
+
+type BulkResponse struct {
+	Result domain.BulkResult `json:"result"`
+}
+
+// @Summary Bulk Students Operation
+// @Description sets a field, adds or removes a tag, changes status by a workflow transition or deletes students selected either by ids or by filter expression, at most 1000 students at once
+// @Security UsersAuth
+// @Tags student
+// @Param input body domain.BulkStudentsInput true "students and operation"
+// @Success 200 {object} BulkResponse
+// @Failure 401
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /students/bulk [post]
+func (s *Server) bulkStudents(w http.ResponseWriter, r *http.Request) {
+	input, err := inputFromContext[domain.BulkStudentsInput](r.Context())
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	result, err := s.studentsService.BulkStudents(r.Context(), *input)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidParams) {
+			sendValidationError(w, []string{err.Error()})
+			return
+		}
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, BulkResponse{
+		Result: *result,
+	})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/abdukhashimov/student_aggregator/internal/config"
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
+)
+
+type exportedStudents struct {
+	ports.StudentsService
+	options domain.ExportStudentsOptions
+}
+
+func (es *exportedStudents) ExportStudents(_ context.Context, options domain.ExportStudentsOptions, w io.Writer) error {
+	es.options = options
+	_, err := w.Write([]byte("email\n"))
+
+	return err
+}
+
+func TestExportStudents(t *testing.T) {
+	status, err := query.Parse(`status="active"`, domain.StudentFilterFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		encryption config.EncryptionConfig
+		wantCode   int
+		want       domain.ListStudentsOptions
+	}{
+		{
+			name:     "tag",
+			query:    "format=csv&tag=top%20%20talent&source=RSS",
+			wantCode: http.StatusOK,
+			want:     domain.ListStudentsOptions{Source: domain.RSS, Tag: "top talent"},
+		},
+		{
+			name:     "with deleted",
+			query:    "format=csv&with_deleted=true&sort=-score&filter=status%3D%22active%22",
+			wantCode: http.StatusOK,
+			want: domain.ListStudentsOptions{
+				WithDeleted: true,
+				Sort:        []domain.SortField{{Field: "student_rss.total_score", Desc: true}},
+				Filter:      status,
+			},
+		},
+		{
+			name:     "unknown sort",
+			query:    "format=csv&sort=password",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "encrypted filter",
+			query:      "format=csv&filter=email%3D%22obi%40jedi.rules%22",
+			encryption: config.EncryptionConfig{Fields: []string{"email"}},
+			wantCode:   http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &exportedStudents{}
+			s := &Server{studentsService: service, config: &config.Config{Encryption: tt.encryption}}
+
+			r := httptest.NewRequest(http.MethodGet, "/students/export?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			s.exportStudents(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("exportStudents() code = %d, want %d, body %s", w.Code, tt.wantCode, w.Body)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if !reflect.DeepEqual(service.options.ListStudentsOptions, tt.want) {
+				t.Errorf("exportStudents() options = %+v, want %+v", service.options.ListStudentsOptions, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/gorilla/mux"
+)
+
+type TrashResponse struct {
+	Items []domain.TrashItem `json:"items"`
+}
+
+// @Summary List Trash
+// @Description lists students and schemas moved to trash, the most recently deleted first
+// @Security UsersAuth
+// @Tags trash
+// @Success 200 {object} TrashResponse
+// @Param type query string false "item type, all types by default" Enums(student, schema)
+// @Param limit query int false "limit, 100 by default"
+// @Failure 401
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /trash [get]
+func (s *Server) listTrash(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	var limit int
+	if l := params.Get("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil || limit < 1 {
+			sendValidationError(w, []string{"limit must be a positive number"})
+			return
+		}
+	}
+
+	items, err := s.trashService.ListTrash(r.Context(), domain.TrashOptions{
+		Type:  params.Get("type"),
+		Limit: limit,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidParams) {
+			sendValidationError(w, []string{err.Error()})
+			return
+		}
+		sendServerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TrashResponse{
+		Items: items,
+	})
+}
+
+// @Summary Restore Student From Trash
+// @Description restores student moved to trash
+// @Security UsersAuth
+// @Tags trash
+// @Success 200 {object} StudentResponse
+// @Param id path string true "student id"
+// @Failure 401
+// @Failure 404
+// @Failure 412
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /trash/students/{id}/restore [post]
+func (s *Server) restoreStudentFromTrash(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		sendUnprocessableEntityError(w, errors.New("id should not be empty"))
+		return
+	}
+
+	student, err := s.trashService.RestoreStudent(r.Context(), id)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			sendNotFoundError(w)
+		case domain.ErrPreconditionFailed:
+			sendPreconditionFailedError(w)
+		default:
+			sendServerError(w, err)
+		}
+		return
+	}
+
+	setETag(w, student.Revision)
+	writeJSON(w, http.StatusOK, StudentResponse{
+		Student: *student,
+	})
+}
+
+// @Summary Restore Schema From Trash
+// @Description restores schema moved to trash
+// @Security UsersAuth
+// @Tags trash
+// @Success 200 {object} SchemaResponse
+// @Param id path string true "schema id"
+// @Failure 401
+// @Failure 404
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /trash/schemas/{id}/restore [post]
+func (s *Server) restoreSchemaFromTrash(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if id == "" {
+		sendUnprocessableEntityError(w, errors.New("id should not be empty"))
+		return
+	}
+
+	schema, err := s.trashService.RestoreSchema(r.Context(), id)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			sendNotFoundError(w)
+			return
+		}
+		sendServerError(w, err)
+		return
+	}
+
+	setETag(w, schema.Revision)
+	writeJSON(w, http.StatusOK, SchemaResponse{
+		Schema: *schema,
+	})
+}
@@ -9,17 +9,16 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/abdukhashimov/student_aggregator/internal/core/services"
 	"github.com/abdukhashimov/student_aggregator/internal/pkg/logger"
+	"github.com/abdukhashimov/student_aggregator/pkg/query"
 )
 
 const (
-	defaultLimit       = 20
-	defaultSortField   = "_id,DESC"
-	defaultSortType    = -1
-	DescSortType       = -1
-	AscSortType        = 1
-	DescSortTypeString = "DESC"
-	AscSortTypeString  = "ASC"
+	defaultLimit = 20
+	// maxLimit is the largest page size, larger limits are reduced to it.
+	maxLimit = 100
 )
 
 type M map[string]interface{}
@@ -94,50 +93,112 @@ func sendDuplicatedError(w http.ResponseWriter, field string) {
 	writeErrorResponse(w, http.StatusUnprocessableEntity, fmt.Sprintf("the field [%s] is taken", field))
 }
 
+func sendPreconditionFailedError(w http.ResponseWriter) {
+	writeErrorResponse(w, http.StatusPreconditionFailed, "resource was modified, get it again and retry")
+}
+
 func writeErrorResponse(w http.ResponseWriter, code int, errs interface{}) {
 	writeJSON(w, code, M{"errors": errs})
 }
 
+// getLimitSkip reads limit and skip query params, invalid and non-positive limits are replaced with the default one
+// and limits above maxLimit with maxLimit, so a request never lists the whole collection, and invalid or negative skip with 0.
 func getLimitSkip(params url.Values) (int, int) {
 	limit := defaultLimit
 	if params.Has("limit") {
 		var err error
 		limit, err = strconv.Atoi(params.Get("limit"))
-		if err != nil {
+		if err != nil || limit <= 0 {
 			limit = defaultLimit
 		}
+		if limit > maxLimit {
+			limit = maxLimit
+		}
 	}
 	skip := 0
 	if params.Has("skip") {
 		var err error
 		skip, err = strconv.Atoi(params.Get("skip"))
-		if err != nil {
+		if err != nil || skip < 0 {
 			skip = 0
 		}
 	}
 	return limit, skip
 }
 
-func getSort(params url.Values) map[string]int {
-	sort := map[string]int{
-		defaultSortField: defaultSortType,
+// getPageOptions reads cursor, limit, skip and with_total query params.
+func getPageOptions(params url.Values) domain.PageOptions {
+	limit, skip := getLimitSkip(params)
+	withTotal, _ := strconv.ParseBool(params.Get("with_total"))
+
+	return domain.PageOptions{
+		Cursor:    params.Get("cursor"),
+		Limit:     limit,
+		Skip:      skip,
+		WithTotal: withTotal,
 	}
-	if params.Has("sort") {
-		parts := strings.Split(params.Get("sort"), ",")
-		if len(parts) == 1 {
-			delete(sort, defaultSortField)
-			sort[parts[0]] = defaultSortType
-		} else if len(parts) == 2 {
-			if parts[1] == DescSortTypeString {
-				delete(sort, defaultSortField)
-				sort[parts[0]] = DescSortType
-			}
-			if parts[1] == AscSortTypeString {
-				delete(sort, defaultSortField)
-				sort[parts[0]] = AscSortType
-			}
-		}
+}
 
+// studentFilterFields returns fields students may be filtered by, encrypted fields are excluded.
+func (s *Server) studentFilterFields() query.Fields {
+	return services.StudentFilterFields(s.config.Encryption)
+}
+
+// studentSortFields returns fields students may be sorted by, encrypted fields are excluded.
+func (s *Server) studentSortFields() domain.SortFields {
+	return services.StudentSortFields(s.config.Encryption)
+}
+
+// setLinkHeader sets Link header pointing to the next page when there is one.
+func setLinkHeader(w http.ResponseWriter, r *http.Request, page *domain.PageInfo) {
+	if page == nil || page.NextCursor == "" {
+		return
 	}
-	return sort
+
+	params := r.URL.Query()
+	params.Del("skip")
+	params.Set("cursor", page.NextCursor)
+
+	next := url.URL{Path: r.URL.Path, RawQuery: params.Encode()}
+	w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.String()))
+}
+
+// setETag sets ETag header to strong entity tag of resource revision.
+func setETag(w http.ResponseWriter, revision int) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(revision)))
+}
+
+// ifMatchRevision parses If-Match header holding a single entity tag set by setETag.
+// Returns nil revision when the header is missing or "*", ok is false when the tag is not a revision tag,
+// so it can not match any revision.
+func ifMatchRevision(r *http.Request) (revision *int, ok bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	// weak tags never match since If-Match uses strong comparison
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return nil, false
+	}
+
+	rev, err := strconv.Atoi(header[1 : len(header)-1])
+	if err != nil {
+		return nil, false
+	}
+
+	return &rev, true
+}
+
+// countingWriter counts bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+
+	return n, err
 }
@@ -7,10 +7,12 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"reflect"
 	"testing"
 
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
 	"github.com/abdukhashimov/student_aggregator/mocks"
 )
 
@@ -214,3 +216,89 @@ func checkResult(result *http.Response, expectedCode int, expectedBody string) e
 
 	return nil
 }
+
+func TestGetLimitSkip(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantLimit int
+		wantSkip  int
+	}{
+		{"", defaultLimit, 0},
+		{"limit=5&skip=10", 5, 10},
+		{"limit=0", defaultLimit, 0},
+		{"limit=-1&skip=-3", defaultLimit, 0},
+		{"limit=many&skip=few", defaultLimit, 0},
+		{"limit=1000000", maxLimit, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			params, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			limit, skip := getLimitSkip(params)
+			if limit != tt.wantLimit || skip != tt.wantSkip {
+				t.Errorf("getLimitSkip() = %d, %d, want %d, %d", limit, skip, tt.wantLimit, tt.wantSkip)
+			}
+		})
+	}
+}
+
+func TestSetLinkHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		page *domain.PageInfo
+		want string
+	}{
+		{"no page", nil, ""},
+		{"last page", &domain.PageInfo{}, ""},
+		{"next page", &domain.PageInfo{NextCursor: "abc", HasMore: true}, `</students?cursor=abc&limit=10&source=RSS>; rel="next"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/students?limit=10&skip=20&source=RSS", nil)
+
+			setLinkHeader(w, r, tt.page)
+
+			if got := w.Header().Get("Link"); got != tt.want {
+				t.Errorf("setLinkHeader() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIfMatchRevision(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   *int
+		ok     bool
+	}{
+		{"missing", "", nil, true},
+		{"any", "*", nil, true},
+		{"strong", `"3"`, intPtr(3), true},
+		{"weak", `W/"3"`, nil, false},
+		{"not a number", `"abc"`, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/schemas/1", nil)
+			if tt.header != "" {
+				r.Header.Set("If-Match", tt.header)
+			}
+
+			got, ok := ifMatchRevision(r)
+			if ok != tt.ok {
+				t.Fatalf("ifMatchRevision() ok = %v, want %v", ok, tt.ok)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("ifMatchRevision() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
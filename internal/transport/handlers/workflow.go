@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
+	"github.com/gorilla/mux"
+)
+
+// @Summary Get Workflow
+// @Description returns student statuses, allowed transitions between them and automatic transitions applied on import
+// @Security UsersAuth
+// @Tags workflow
+// @Success 200 {object} domain.Workflow
+// @Failure 401
+// @Produce json
+// @Router /workflow [get]
+func (s *Server) getWorkflow(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.workflowService.GetWorkflow())
+}
+
+// @Summary Change Student Status
+// @Description changes student status if the workflow allows the transition, the change is recorded with its reason and author.
+// @Description Student without status may be given any workflow status.
+// @Security UsersAuth
+// @Tags workflow
+// @Param id path string true "student id"
+// @Param If-Match header string false "student revision from ETag, the status is changed only if it matches"
+// @Param input body domain.StatusInput true "new status"
+// @Success 200 {object} StudentResponse
+// @Header 200 {string} ETag "student revision"
+// @Failure 401
+// @Failure 404
+// @Failure 412
+// @Failure 422
+// @Failure 500
+// @Accept json
+// @Produce json
+// @Router /students/{id}/status [post]
+func (s *Server) changeStudentStatus(w http.ResponseWriter, r *http.Request) {
+	input, err := inputFromContext[domain.StatusInput](r.Context())
+	if err != nil {
+		sendServerError(w, err)
+		return
+	}
+
+	revision, ok := ifMatchRevision(r)
+	if !ok {
+		sendPreconditionFailedError(w)
+		return
+	}
+
+	student, err := s.workflowService.ChangeStatus(r.Context(), mux.Vars(r)["id"], *input, revision)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			sendNotFoundError(w)
+		case errors.Is(err, domain.ErrPreconditionFailed):
+			sendPreconditionFailedError(w)
+		case errors.Is(err, domain.ErrTransitionNotAllowed):
+			sendValidationError(w, []string{err.Error()})
+		default:
+			sendServerError(w, err)
+		}
+		return
+	}
+
+	setETag(w, student.Revision)
+	writeJSON(w, http.StatusOK, StudentResponse{
+		Student: *student,
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/abdukhashimov/student_aggregator/internal/core/domain"
 	"github.com/abdukhashimov/student_aggregator/internal/core/ports"
@@ -91,15 +92,19 @@ func (m *mockSchemasRepository) Create(ctx context.Context, input domain.Schema)
 		SchemaType: input.SchemaType,
 		Headers:    input.Headers,
 		Fields:     input.Fields,
+		Lookups:    input.Lookups,
+		Options:    input.Options,
+		GroupBy:    input.GroupBy,
+		Revision:   1,
 	}
 	m.schemasStorage[newId] = newSchema
 
 	return newId, nil
 }
 
-func (m *mockSchemasRepository) FindAll(ctx context.Context) ([]domain.Schema, error) {
+func (m *mockSchemasRepository) FindAll(ctx context.Context, options domain.ListSchemasOptions) ([]domain.Schema, *domain.PageInfo, error) {
 	if utils.WithError(ctx) {
-		return nil, InternalError
+		return nil, nil, InternalError
 	}
 
 	m.mutex.Lock()
@@ -107,6 +112,9 @@ func (m *mockSchemasRepository) FindAll(ctx context.Context) ([]domain.Schema, e
 
 	var result []domain.Schema
 	for _, v := range m.schemasStorage {
+		if v.DeletedAt != nil && !options.WithDeleted {
+			continue
+		}
 		schemaCopy := utils.CopySchema(v)
 		result = append(result, *schemaCopy)
 	}
@@ -115,7 +123,9 @@ func (m *mockSchemasRepository) FindAll(ctx context.Context) ([]domain.Schema, e
 		return result[i].ID < result[j].ID
 	})
 
-	return result, nil
+	result, info := utils.PageSchemas(result, options.PageOptions)
+
+	return result, info, nil
 }
 
 func (m *mockSchemasRepository) GetById(ctx context.Context, id string) (*domain.Schema, error) {
@@ -127,7 +137,7 @@ func (m *mockSchemasRepository) GetById(ctx context.Context, id string) (*domain
 	defer m.mutex.Unlock()
 
 	schema, ok := m.schemasStorage[id]
-	if !ok {
+	if !ok || schema.DeletedAt != nil {
 		return nil, domain.ErrNotFound
 	}
 
@@ -136,7 +146,7 @@ func (m *mockSchemasRepository) GetById(ctx context.Context, id string) (*domain
 	return schemaCopy, nil
 }
 
-func (m *mockSchemasRepository) Update(ctx context.Context, id string, input domain.UpdateSchemaInput) error {
+func (m *mockSchemasRepository) Update(ctx context.Context, id string, input domain.UpdateSchemaInput, revision *int) error {
 	if utils.WithError(ctx) {
 		return InternalError
 	}
@@ -145,10 +155,14 @@ func (m *mockSchemasRepository) Update(ctx context.Context, id string, input dom
 	defer m.mutex.Unlock()
 
 	schema, ok := m.schemasStorage[id]
-	if !ok {
+	if !ok || schema.DeletedAt != nil {
 		return domain.ErrNotFound
 	}
 
+	if revision != nil && *revision != schema.Revision {
+		return domain.ErrPreconditionFailed
+	}
+
 	if input.Name != nil {
 		slug := utils.GetSlug(*input.Name)
 		if m.isDuplicate(slug, id) {
@@ -174,6 +188,20 @@ func (m *mockSchemasRepository) Update(ctx context.Context, id string, input dom
 		schema.Fields = *input.Fields
 	}
 
+	if input.Lookups != nil {
+		schema.Lookups = *input.Lookups
+	}
+
+	if input.Options != nil {
+		schema.Options = input.Options
+	}
+
+	if input.GroupBy != nil {
+		schema.GroupBy = *input.GroupBy
+	}
+
+	schema.Revision++
+
 	return nil
 }
 
@@ -185,15 +213,83 @@ func (m *mockSchemasRepository) Delete(ctx context.Context, id string) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	if _, ok := m.schemasStorage[id]; !ok {
+	schema, ok := m.schemasStorage[id]
+	if !ok || schema.DeletedAt != nil {
+		return domain.ErrNotFound
+	}
+
+	deletedAt := time.Now().UTC()
+	schema.DeletedAt = &deletedAt
+	schema.DeletedBy = domain.ActorFromContext(ctx).Username
+	schema.Revision++
+
+	return nil
+}
+
+func (m *mockSchemasRepository) ListDeleted(ctx context.Context, limit int) ([]domain.Schema, error) {
+	if utils.WithError(ctx) {
+		return nil, InternalError
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	result := []domain.Schema{}
+	for _, v := range m.schemasStorage {
+		if v.DeletedAt != nil {
+			result = append(result, *utils.CopySchema(v))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DeletedAt.After(*result[j].DeletedAt)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+func (m *mockSchemasRepository) Undelete(ctx context.Context, id string) error {
+	if utils.WithError(ctx) {
+		return InternalError
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	schema, ok := m.schemasStorage[id]
+	if !ok || schema.DeletedAt == nil {
 		return domain.ErrNotFound
 	}
 
-	delete(m.schemasStorage, id)
+	schema.DeletedAt = nil
+	schema.DeletedBy = ""
+	schema.Revision++
 
 	return nil
 }
 
+func (m *mockSchemasRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	if utils.WithError(ctx) {
+		return 0, InternalError
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var count int64
+	for id, v := range m.schemasStorage {
+		if v.DeletedAt != nil && v.DeletedAt.Before(before) {
+			delete(m.schemasStorage, id)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
 func (m *mockSchemasRepository) isDuplicate(slug string, id string) bool {
 	for _, s := range m.schemasStorage {
 		if s.Slug == slug && s.ID != id {
@@ -88,6 +88,10 @@ func (m *mockSchemasService) NewSchema(ctx context.Context, input domain.NewSche
 		SchemaType: input.SchemaType,
 		Headers:    input.Headers,
 		Fields:     input.Fields,
+		Lookups:    input.Lookups,
+		Options:    input.Options,
+		GroupBy:    input.GroupBy,
+		Revision:   1,
 	}
 
 	schemaCopy := utils.CopySchema(m.schemasStorage[m.lastSchemaId])
@@ -95,9 +99,9 @@ func (m *mockSchemasService) NewSchema(ctx context.Context, input domain.NewSche
 	return schemaCopy, nil
 }
 
-func (m *mockSchemasService) ListSchemas(ctx context.Context) ([]domain.Schema, error) {
+func (m *mockSchemasService) ListSchemas(ctx context.Context, options domain.ListSchemasOptions) ([]domain.Schema, *domain.PageInfo, error) {
 	if utils.WithError(ctx) {
-		return nil, InternalError
+		return nil, nil, InternalError
 	}
 
 	m.mutex.Lock()
@@ -112,7 +116,10 @@ func (m *mockSchemasService) ListSchemas(ctx context.Context) ([]domain.Schema,
 	sort.Slice(result, func(i, j int) bool {
 		return result[i].ID < result[j].ID
 	})
-	return result, nil
+
+	result, info := utils.PageSchemas(result, options.PageOptions)
+
+	return result, info, nil
 }
 
 func (m *mockSchemasService) GetSchemaById(ctx context.Context, id string) (*domain.Schema, error) {
@@ -133,7 +140,7 @@ func (m *mockSchemasService) GetSchemaById(ctx context.Context, id string) (*dom
 	return schemaCopy, nil
 }
 
-func (m *mockSchemasService) UpdateSchema(ctx context.Context, id string, input domain.UpdateSchemaInput) (*domain.Schema, error) {
+func (m *mockSchemasService) UpdateSchema(ctx context.Context, id string, input domain.UpdateSchemaInput, revision *int) (*domain.Schema, error) {
 	if utils.WithError(ctx) {
 		return nil, InternalError
 	}
@@ -146,6 +153,10 @@ func (m *mockSchemasService) UpdateSchema(ctx context.Context, id string, input
 		return nil, domain.ErrNotFound
 	}
 
+	if revision != nil && *revision != schema.Revision {
+		return nil, domain.ErrPreconditionFailed
+	}
+
 	if input.Name != nil {
 		slug := utils.GetSlug(*input.Name)
 		if m.isDuplicate(slug, id) {
@@ -171,6 +182,20 @@ func (m *mockSchemasService) UpdateSchema(ctx context.Context, id string, input
 		schema.Fields = *input.Fields
 	}
 
+	if input.Lookups != nil {
+		schema.Lookups = *input.Lookups
+	}
+
+	if input.Options != nil {
+		schema.Options = input.Options
+	}
+
+	if input.GroupBy != nil {
+		schema.GroupBy = *input.GroupBy
+	}
+
+	schema.Revision++
+
 	schemaCopy := utils.CopySchema(schema)
 
 	return schemaCopy, nil
@@ -61,3 +61,32 @@ func IncrementMongoId(in string) string {
 
 	return fmt.Sprintf("%s%09x", in[:15], value)
 }
+
+// PageSchemas returns a page of schemas sorted by id using the last schema id as a page cursor.
+func PageSchemas(schemas []domain.Schema, page domain.PageOptions) ([]domain.Schema, *domain.PageInfo) {
+	info := &domain.PageInfo{}
+	if page.WithTotal {
+		total := int64(len(schemas))
+		info.Total = &total
+	}
+
+	start := 0
+	if page.Cursor != "" {
+		for start < len(schemas) && schemas[start].ID <= page.Cursor {
+			start++
+		}
+	} else if page.Skip < len(schemas) {
+		start = page.Skip
+	} else {
+		start = len(schemas)
+	}
+	schemas = schemas[start:]
+
+	if page.Limit > 0 && len(schemas) > page.Limit {
+		schemas = schemas[:page.Limit]
+		info.HasMore = true
+		info.NextCursor = schemas[len(schemas)-1].ID
+	}
+
+	return schemas, info
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// Writer writes table rows in a file format.
+// Close must be called after the last row to flush the output.
+type Writer interface {
+	Write(row []interface{}) error
+	Close() error
+}
+
+// NewWriter creates Writer for format writing to w.
+// Returns Writer and an error for unsupported formats.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case CSV:
+		return NewCSVWriter(w), nil
+	case XLSX:
+		return NewXLSXWriter(w, "")
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ContentType returns MIME type of format.
+func ContentType(format string) string {
+	switch format {
+	case CSV:
+		return "text/csv"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+// NewCSVWriter creates Writer streaming CSV rows to w.
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (cw *csvWriter) Write(row []interface{}) error {
+	record := make([]string, len(row))
+	for i, v := range row {
+		if v != nil {
+			record[i] = fmt.Sprint(v)
+		}
+	}
+
+	return cw.w.Write(record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+
+	return cw.w.Error()
+}
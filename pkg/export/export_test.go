@@ -0,0 +1,96 @@
+package export
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var rows = [][]interface{}{
+	{"email", "score", "name"},
+	{"obi@jedi.rules", 90, "Obi-Wan, Kenobi"},
+	{"anakin@jedi.rules", nil, "Anakin"},
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(CSV, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "email,score,name\nobi@jedi.rules,90,\"Obi-Wan, Kenobi\"\nanakin@jedi.rules,,Anakin\n"
+	if got := buf.String(); got != want {
+		t.Errorf("csv = %q, want %q", got, want)
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewXLSXWriter(&buf, "students")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.NewSheet("summary"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([]interface{}{"total", 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := f.GetSheetList(); !reflect.DeepEqual(got, []string{"students", "summary"}) {
+		t.Errorf("sheets = %v", got)
+	}
+
+	got, err := f.GetRows("students")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"email", "score", "name"},
+		{"obi@jedi.rules", "90", "Obi-Wan, Kenobi"},
+		{"anakin@jedi.rules", "", "Anakin"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("students rows = %v, want %v", got, want)
+	}
+
+	got, err = f.GetRows("summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, [][]string{{"total", "2"}}) {
+		t.Errorf("summary rows = %v", got)
+	}
+}
+
+func TestNewWriterUnsupportedFormat(t *testing.T) {
+	if _, err := NewWriter("pdf", &bytes.Buffer{}); err == nil {
+		t.Error("NewWriter() expected an error")
+	}
+}
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const defaultSheet = "Sheet1"
+
+// XLSXWriter writes rows to workbook sheets with excelize stream writer,
+// so rows are not kept in memory as cells. The workbook is written to the output on Close.
+type XLSXWriter struct {
+	out    io.Writer
+	f      *excelize.File
+	sw     *excelize.StreamWriter
+	sheets []*excelize.StreamWriter
+	row    int
+}
+
+// NewXLSXWriter creates XLSXWriter writing rows to the sheet, which is "Sheet1" when empty.
+// Returns XLSXWriter pointer and an error.
+func NewXLSXWriter(w io.Writer, sheet string) (*XLSXWriter, error) {
+	xw := &XLSXWriter{
+		out: w,
+		f:   excelize.NewFile(),
+	}
+
+	if sheet != "" && sheet != defaultSheet {
+		xw.f.SetSheetName(defaultSheet, sheet)
+	}
+	if sheet == "" {
+		sheet = defaultSheet
+	}
+
+	if err := xw.stream(sheet); err != nil {
+		return nil, err
+	}
+
+	return xw, nil
+}
+
+// NewSheet adds sheet to the workbook, following rows are written to it.
+func (xw *XLSXWriter) NewSheet(sheet string) error {
+	xw.f.NewSheet(sheet)
+
+	return xw.stream(sheet)
+}
+
+func (xw *XLSXWriter) stream(sheet string) error {
+	sw, err := xw.f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	xw.sw = sw
+	xw.sheets = append(xw.sheets, sw)
+	xw.row = 0
+
+	return nil
+}
+
+func (xw *XLSXWriter) Write(row []interface{}) error {
+	xw.row++
+	axis, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+
+	return xw.sw.SetRow(axis, row)
+}
+
+func (xw *XLSXWriter) Close() error {
+	for _, sw := range xw.sheets {
+		if err := sw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if err := xw.f.Write(xw.out); err != nil {
+		return err
+	}
+
+	return xw.f.Close()
+}
@@ -0,0 +1,153 @@
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks encrypted values, it is followed by the key id and the encoded nonce with ciphertext.
+const prefix = "enc:v1:"
+
+const keySize = 32
+
+var (
+	ErrUnknownKey = errors.New("value is encrypted with unknown key")
+	ErrMalformed  = errors.New("malformed encrypted value")
+)
+
+// Cipher encrypts string values with AES-256-GCM and computes deterministic blind indexes of them.
+// Values are encrypted with the first key, the other keys only decrypt values encrypted before key rotation.
+type Cipher struct {
+	current  string
+	aeads    map[string]cipher.AEAD
+	indexKey []byte
+}
+
+// New creates Cipher from base64 encoded 32 byte keys, the first key is the current one.
+// Returns Cipher pointer and an error.
+func New(keys []string, indexKey string) (*Cipher, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no encryption keys")
+	}
+
+	c := &Cipher{aeads: make(map[string]cipher.AEAD, len(keys))}
+	for i, k := range keys {
+		key, err := decodeKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %d: %w", i, err)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		id := keyID(key)
+		if i == 0 {
+			c.current = id
+		}
+		c.aeads[id] = aead
+	}
+
+	var err error
+	if c.indexKey, err = decodeKey(indexKey); err != nil {
+		return nil, fmt.Errorf("index key: %w", err)
+	}
+
+	return c, nil
+}
+
+// Encrypt encrypts value with the current key, empty values are returned as is.
+// Values looking encrypted are encrypted too, so callers re-encrypting stored values decrypt them first.
+func (c *Cipher) Encrypt(value string) (string, error) {
+	if value == "" {
+		return value, nil
+	}
+
+	aead := c.aeads[c.current]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(c.current))
+
+	return prefix + c.current + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts value encrypted with any of the keys, values which are not encrypted are returned as is.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	id, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+	aead, ok := c.aeads[id]
+	if !ok {
+		return "", fmt.Errorf("%w %s", ErrUnknownKey, id)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformed
+	}
+
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	return string(plain), nil
+}
+
+// IsCurrent reports whether value is encrypted with the current key.
+func (c *Cipher) IsCurrent(value string) bool {
+	return strings.HasPrefix(value, prefix+c.current+":")
+}
+
+// BlindIndex returns keyed hash of value, equal values have equal indexes, so values can be looked up by them.
+func (c *Cipher) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether value is encrypted by Cipher.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+func decodeKey(k string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(k))
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key should be %d bytes long, got %d", keySize, len(key))
+	}
+
+	return key, nil
+}
+
+// keyID identifies key without revealing it.
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+
+	return hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,107 @@
+package fieldcrypt
+
+import (
+	"errors"
+	"testing"
+)
+
+const (
+	keyOne   = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+	keyTwo   = "HwAeAR0CHAMbBBoFGQYYBxcIFgkVChQLEwwSDREOEA8="
+	indexKey = "ICEiIyQlJicoKSorLC0uLzAxMjM0NTY3ODk6Ozw9Pj8="
+)
+
+func TestCipher(t *testing.T) {
+	c, err := New([]string{keyOne}, indexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := c.Encrypt("obi@jedi.rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(encrypted) || !c.IsCurrent(encrypted) {
+		t.Errorf("Encrypt() = %q, want value encrypted with the current key", encrypted)
+	}
+
+	again, _ := c.Encrypt("obi@jedi.rules")
+	if again == encrypted {
+		t.Error("Encrypt() returned equal values for equal inputs, want random nonces")
+	}
+
+	prefixed := prefix + "plain"
+	got, err := c.Encrypt(prefixed)
+	if err != nil || got == prefixed || !c.IsCurrent(got) {
+		t.Errorf("Encrypt() = %q, %v, want plaintext with the prefix encrypted", got, err)
+	}
+	if plain, err := c.Decrypt(got); err != nil || plain != prefixed {
+		t.Errorf("Decrypt() = %q, %v, want %q", plain, err, prefixed)
+	}
+
+	if got, err := c.Decrypt(encrypted); err != nil || got != "obi@jedi.rules" {
+		t.Errorf("Decrypt() = %q, %v, want original value", got, err)
+	}
+
+	if got, err := c.Decrypt("plain"); err != nil || got != "plain" {
+		t.Errorf("Decrypt() = %q, %v, want not encrypted value as is", got, err)
+	}
+
+	if _, err := c.Decrypt(encrypted[:len(encrypted)-2]); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Decrypt() of tampered value error = %v, want ErrMalformed", err)
+	}
+
+	if c.BlindIndex("obi@jedi.rules") != c.BlindIndex("obi@jedi.rules") || c.BlindIndex("obi@jedi.rules") == c.BlindIndex("ben@jedi.rules") {
+		t.Error("BlindIndex() should be equal for equal values only")
+	}
+}
+
+func TestCipherRotation(t *testing.T) {
+	old, err := New([]string{keyOne}, indexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := old.Encrypt("Tatooine")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err := New([]string{keyTwo, keyOne}, indexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.IsCurrent(encrypted) {
+		t.Error("IsCurrent() = true for value encrypted with the previous key")
+	}
+	if got, err := rotated.Decrypt(encrypted); err != nil || got != "Tatooine" {
+		t.Errorf("Decrypt() = %q, %v, want value encrypted with the previous key decrypted", got, err)
+	}
+
+	withoutOld, err := New([]string{keyTwo}, indexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := withoutOld.Decrypt(encrypted); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Decrypt() error = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []string
+		indexKey string
+	}{
+		{"no keys", nil, indexKey},
+		{"short key", []string{"AAECAwQFBgcICQoLDA0ODw=="}, indexKey},
+		{"not base64", []string{"not a key"}, indexKey},
+		{"missing index key", []string{keyOne}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.keys, tt.indexKey); err == nil {
+				t.Error("New() error = nil, want error")
+			}
+		})
+	}
+}
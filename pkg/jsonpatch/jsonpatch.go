@@ -0,0 +1,434 @@
+// Package jsonpatch applies JSON Patch (RFC 6902) and JSON Merge Patch (RFC 7396) documents to JSON documents.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+var (
+	ErrInvalidPath = errors.New("invalid path")
+	ErrTestFailed  = errors.New("test failed")
+)
+
+// Operation is a single JSON Patch operation.
+// Value is nil when the member is missing and holds null when it is null.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch is a sequence of operations applied in order, all of them are applied or none.
+type Patch []Operation
+
+// DecodePatch decodes JSON Patch document and checks operations have members they require.
+// Returns Patch and an error.
+func DecodePatch(data []byte) (Patch, error) {
+	var p Patch
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+
+	for i, op := range p {
+		switch op.Op {
+		case OpAdd, OpReplace, OpTest:
+			if op.Value == nil {
+				return nil, fmt.Errorf("operation %d: %s requires value", i, op.Op)
+			}
+		case OpMove, OpCopy:
+			if _, err := parsePointer(op.From); err != nil {
+				return nil, fmt.Errorf("operation %d: from: %w", i, err)
+			}
+		case OpRemove:
+		default:
+			return nil, fmt.Errorf("operation %d: unknown operation %q", i, op.Op)
+		}
+
+		if _, err := parsePointer(op.Path); err != nil {
+			return nil, fmt.Errorf("operation %d: path: %w", i, err)
+		}
+	}
+
+	return p, nil
+}
+
+// Apply applies patch operations to JSON document doc.
+// Returns patched document and an error of the first failed operation.
+func (p Patch) Apply(doc []byte) ([]byte, error) {
+	node, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, op := range p {
+		if node, err = applyOperation(node, op); err != nil {
+			return nil, fmt.Errorf("operation %d: %s %s: %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return json.Marshal(node)
+}
+
+// MergePatch applies JSON Merge Patch to JSON document doc: object members of patch replace members of doc
+// recursively, null members remove them and any other patch value replaces doc as a whole.
+// Returns patched document and an error.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := decode(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(merge(target, p))
+}
+
+func merge(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+			continue
+		}
+		t[key] = merge(t[key], value)
+	}
+
+	return t
+}
+
+func applyOperation(doc interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case OpAdd:
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpRemove:
+		return remove(doc, path)
+	case OpReplace:
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := get(doc, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		return update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+			return replaceChild(parent, key, value)
+		})
+	case OpMove:
+		from, _ := parsePointer(op.From)
+		if isPrefix(from, path) && len(from) < len(path) {
+			return nil, fmt.Errorf("%w: can not move value into its child", ErrInvalidPath)
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpCopy:
+		from, _ := parsePointer(op.From)
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, deepCopy(value))
+	case OpTest:
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		current, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(current, value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[key] = value
+			return p, nil
+		case []interface{}:
+			if key == "-" {
+				return append(p, value), nil
+			}
+			i, err := arrayIndex(key, len(p)+1)
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, nil)
+			copy(p[i+1:], p[i:])
+			p[i] = value
+			return p, nil
+		default:
+			return nil, fmt.Errorf("%w: %q is not a container", ErrInvalidPath, key)
+		}
+	})
+}
+
+func remove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: can not remove the whole document", ErrInvalidPath)
+	}
+
+	return update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := p[key]; !ok {
+				return nil, fmt.Errorf("%w: member %q does not exist", ErrInvalidPath, key)
+			}
+			delete(p, key)
+			return p, nil
+		case []interface{}:
+			i, err := arrayIndex(key, len(p))
+			if err != nil {
+				return nil, err
+			}
+			return append(p[:i], p[i+1:]...), nil
+		default:
+			return nil, fmt.Errorf("%w: %q is not a container", ErrInvalidPath, key)
+		}
+	})
+}
+
+func replaceChild(parent interface{}, key string, value interface{}) (interface{}, error) {
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[key] = value
+		return p, nil
+	case []interface{}:
+		i, err := arrayIndex(key, len(p))
+		if err != nil {
+			return nil, err
+		}
+		p[i] = value
+		return p, nil
+	default:
+		return nil, fmt.Errorf("%w: %q is not a container", ErrInvalidPath, key)
+	}
+}
+
+// update walks path down to the container of the last token and replaces it with the result of fn,
+// so containers may be reallocated on insertion and removal.
+func update(node interface{}, path []string, fn func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(node, path[0])
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[path[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: member %q does not exist", ErrInvalidPath, path[0])
+		}
+		updated, err := update(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[path[0]] = updated
+		return n, nil
+	case []interface{}:
+		i, err := arrayIndex(path[0], len(n))
+		if err != nil {
+			return nil, err
+		}
+		updated, err := update(n[i], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = updated
+		return n, nil
+	default:
+		return nil, fmt.Errorf("%w: %q is not a container", ErrInvalidPath, path[0])
+	}
+}
+
+func get(node interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q does not exist", ErrInvalidPath, token)
+			}
+			node = child
+		case []interface{}:
+			i, err := arrayIndex(token, len(n))
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("%w: %q is not a container", ErrInvalidPath, token)
+		}
+	}
+
+	return node, nil
+}
+
+// parsePointer splits JSON Pointer (RFC 6901) into unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: %q should start with /", ErrInvalidPath, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens, nil
+}
+
+// arrayIndex parses array index token which should be less than size.
+func arrayIndex(token string, size int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: %q is not an array index", ErrInvalidPath, token)
+	}
+	if i >= size {
+		return 0, fmt.Errorf("%w: index %d is out of range", ErrInvalidPath, i)
+	}
+
+	return i, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equal compares decoded JSON values, numbers are compared by value.
+func equal(a, b interface{}) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+		return aerr == nil && berr == nil && af == bf
+	}
+
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			m[key] = deepCopy(child)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, child := range v {
+			a[i] = deepCopy(child)
+		}
+		return a
+	default:
+		return v
+	}
+}
+
+// decode decodes JSON keeping numbers as json.Number, so they are not rounded.
+func decode(data []byte) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,154 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPatch_Apply(t *testing.T) {
+	doc := `{"name":"Luke","items":["a","b"],"projects":[{"name":"Songbird","score":50}],"a/b":1}`
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "addMember",
+			patch: `[{"op":"add","path":"/status","value":"active"}]`,
+			want:  `{"name":"Luke","items":["a","b"],"projects":[{"name":"Songbird","score":50}],"a/b":1,"status":"active"}`,
+		},
+		{
+			name:  "insertAndAppend",
+			patch: `[{"op":"add","path":"/items/0","value":"z"},{"op":"add","path":"/items/-","value":"c"}]`,
+			want:  `{"name":"Luke","items":["z","a","b","c"],"projects":[{"name":"Songbird","score":50}],"a/b":1}`,
+		},
+		{
+			name:  "replaceNested",
+			patch: `[{"op":"test","path":"/projects/0/score","value":50.0},{"op":"replace","path":"/projects/0/score","value":100}]`,
+			want:  `{"name":"Luke","items":["a","b"],"projects":[{"name":"Songbird","score":100}],"a/b":1}`,
+		},
+		{
+			name:  "removeAndEscapedPath",
+			patch: `[{"op":"remove","path":"/items/1"},{"op":"remove","path":"/a~1b"}]`,
+			want:  `{"name":"Luke","items":["a"],"projects":[{"name":"Songbird","score":50}]}`,
+		},
+		{
+			name:  "moveAndCopy",
+			patch: `[{"op":"copy","from":"/projects/0","path":"/projects/-"},{"op":"move","from":"/name","path":"/first_name"}]`,
+			want:  `{"first_name":"Luke","items":["a","b"],"projects":[{"name":"Songbird","score":50},{"name":"Songbird","score":50}],"a/b":1}`,
+		},
+		{
+			name:    "failedTest",
+			patch:   `[{"op":"replace","path":"/name","value":"Leia"},{"op":"test","path":"/name","value":"Luke"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:    "missingMember",
+			patch:   `[{"op":"replace","path":"/missing","value":1}]`,
+			wantErr: ErrInvalidPath,
+		},
+		{
+			name:    "indexOutOfRange",
+			patch:   `[{"op":"add","path":"/items/3","value":"x"}]`,
+			wantErr: ErrInvalidPath,
+		},
+		{
+			name:    "moveIntoChild",
+			patch:   `[{"op":"move","from":"/projects","path":"/projects/0/copy"}]`,
+			wantErr: ErrInvalidPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := DecodePatch([]byte(tt.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := p.Apply([]byte(doc))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Apply() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestDecodePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{"unknownOperation", `[{"op":"merge","path":"/a"}]`},
+		{"missingValue", `[{"op":"add","path":"/a"}]`},
+		{"invalidPath", `[{"op":"remove","path":"a"}]`},
+		{"invalidFrom", `[{"op":"copy","from":"a","path":"/a"}]`},
+		{"notArray", `{"op":"remove","path":"/a"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodePatch([]byte(tt.patch)); err == nil {
+				t.Errorf("DecodePatch() error expected")
+			}
+		})
+	}
+
+	if _, err := DecodePatch([]byte(`[{"op":"add","path":"/a","value":null}]`)); err != nil {
+		t.Errorf("DecodePatch() null value error = %v", err)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"replaceMember", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"removeMember", `{"a":"b","c":"d"}`, `{"a":null}`, `{"c":"d"}`},
+		{"replaceArray", `{"a":["b","c"]}`, `{"a":["d"]}`, `{"a":["d"]}`},
+		{"mergeNested", `{"a":{"b":"c","d":"e"}}`, `{"a":{"b":null,"f":1}}`, `{"a":{"d":"e","f":1}}`},
+		{"replaceDocument", `{"a":"b"}`, `["c"]`, `["c"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
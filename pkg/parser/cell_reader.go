@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02T15:04:05"
+)
+
+// ReadOptions defines how cell values are read from a spreadsheet.
+type ReadOptions struct {
+	// CalcFormulas recalculates formula cells instead of using their cached values.
+	CalcFormulas bool `json:"calc_formulas"`
+	// IgnoreMergedCells leaves cells covered by a merged range empty, only the top left cell of the range
+	// holds the value. By default the value of the range is read from every cell it covers.
+	IgnoreMergedCells bool `json:"ignore_merged_cells"`
+	// Hyperlinks reads hyperlink targets instead of the displayed cell text.
+	Hyperlinks bool `json:"hyperlinks"`
+}
+
+type cellReader struct {
+	f        *excelize.File
+	sheet    string
+	options  ReadOptions
+	date1904 bool
+	merged   map[string]string
+}
+
+// newCellReader creates cellReader for sheet of excelize.File f according to options o.
+// Returns cellReader pointer and an error.
+func newCellReader(f *excelize.File, sheet string, o ReadOptions) (*cellReader, error) {
+	var date1904 excelize.Date1904
+	if err := f.GetWorkbookPrOptions(&date1904); err != nil {
+		return nil, err
+	}
+
+	cr := &cellReader{
+		f:        f,
+		sheet:    sheet,
+		options:  o,
+		date1904: bool(date1904),
+	}
+
+	merged, err := mergedCellsMap(f, sheet)
+	if err != nil {
+		return nil, err
+	}
+	cr.merged = merged
+
+	return cr, nil
+}
+
+// mergedCellsMap maps every cell covered by a merged range of sheet to the top left cell of that range.
+// Returns cells map and an error.
+func mergedCellsMap(f *excelize.File, sheet string) (map[string]string, error) {
+	mergeCells, err := f.GetMergeCells(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]string)
+	for _, mc := range mergeCells {
+		start := mc.GetStartAxis()
+		startCol, startRow, err := excelize.CellNameToCoordinates(start)
+		if err != nil {
+			return nil, err
+		}
+		endCol, endRow, err := excelize.CellNameToCoordinates(mc.GetEndAxis())
+		if err != nil {
+			return nil, err
+		}
+
+		for col := startCol; col <= endCol; col++ {
+			for row := startRow; row <= endRow; row++ {
+				axis, err := excelize.CoordinatesToCellName(col, row)
+				if err != nil {
+					return nil, err
+				}
+				if axis != start {
+					merged[axis] = start
+				}
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// value reads cell value by axis. Excel serial numbers are converted to dates if isDate is set.
+// Returns cell value and an error.
+func (cr *cellReader) value(axis string, isDate bool) (string, error) {
+	if start, ok := cr.merged[axis]; ok {
+		if cr.options.IgnoreMergedCells {
+			return "", nil
+		}
+		axis = start
+	}
+
+	if cr.options.Hyperlinks {
+		ok, target, err := cr.f.GetCellHyperLink(cr.sheet, axis)
+		if err != nil {
+			return "", err
+		}
+		if ok && target != "" {
+			return strings.TrimPrefix(target, "mailto:"), nil
+		}
+	}
+
+	if cr.options.CalcFormulas {
+		formula, err := cr.f.GetCellFormula(cr.sheet, axis)
+		if err != nil {
+			return "", err
+		}
+		if formula != "" {
+			// keep the cached value for formulas excelize is not able to calculate
+			if value, err := cr.f.CalcCellValue(cr.sheet, axis); err == nil {
+				if isDate {
+					return cr.toDate(value), nil
+				}
+				return value, nil
+			}
+		}
+	}
+
+	if isDate {
+		raw, err := cr.f.GetCellValue(cr.sheet, axis, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return "", err
+		}
+		return cr.toDate(raw), nil
+	}
+
+	return cr.f.GetCellValue(cr.sheet, axis)
+}
+
+// toDate converts Excel serial number value to a date string.
+// Values which are not serial numbers are returned as is.
+func (cr *cellReader) toDate(value string) string {
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+
+	t, err := excelize.ExcelDateToTime(serial, cr.date1904)
+	if err != nil {
+		return value
+	}
+
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format(dateLayout)
+	}
+
+	return t.Format(dateTimeLayout)
+}
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// EraseRecords removes rows of records having a cell equal to any of values, ignoring case and surrounding spaces,
+// from every sheet of xlsx file r. Rows are grouped into records the same way as by ParseXLSXFile with schema s.
+// Returns the file without the records, the number of removed records and an error.
+func EraseRecords(r io.Reader, s Schema, values []string) (*bytes.Buffer, int, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	e, err := newEraser(s, values)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	erased := 0
+	for _, sheetName := range f.GetSheetList() {
+		rows, err := f.GetRows(sheetName)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		remove := e.records(rows)
+		erased += len(remove)
+
+		// rows are removed from the bottom, so indexes of rows above stay valid
+		for j := len(remove) - 1; j >= 0; j-- {
+			for i := remove[j][1] - 1; i >= remove[j][0]; i-- {
+				if err := f.RemoveRow(sheetName, i+1); err != nil {
+					return nil, 0, err
+				}
+			}
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return buf, erased, nil
+}
+
+// EraseCSVRecords removes records the same way as EraseRecords from csv file r, the rest of rows are kept as they are.
+// Returns the file without the records, the number of removed records and an error.
+func EraseCSVRecords(r io.Reader, s Schema, values []string) (*bytes.Buffer, int, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1
+	rows, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	e, err := newEraser(s, values)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	remove := e.records(rows)
+	erased := len(remove)
+	kept := make([][]string, 0, len(rows))
+	for i, row := range rows {
+		if len(remove) > 0 && i >= remove[0][1] {
+			remove = remove[1:]
+		}
+		if len(remove) == 0 || i < remove[0][0] {
+			kept = append(kept, row)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	if err := w.WriteAll(kept); err != nil {
+		return nil, 0, err
+	}
+
+	return buf, erased, nil
+}
+
+type eraser struct {
+	schema   Schema
+	match    map[string]bool
+	groupCol int
+}
+
+func newEraser(s Schema, values []string) (*eraser, error) {
+	e := &eraser{schema: s, match: make(map[string]bool, len(values)), groupCol: -1}
+	for _, v := range values {
+		if v = normalizeCell(v); v != "" {
+			e.match[v] = true
+		}
+	}
+
+	if s.GroupBy != "" {
+		col, err := excelize.ColumnNameToNumber(s.GroupBy)
+		if err != nil {
+			return nil, err
+		}
+		e.groupCol = col - 1
+	}
+
+	return e, nil
+}
+
+// records returns [start, end) ranges of row indexes of matched records in ascending order.
+func (e *eraser) records(rows [][]string) [][2]int {
+	first := 0
+	if e.schema.Headers {
+		first = 1
+	}
+
+	var remove [][2]int
+	start, matched, groupValue := -1, false, ""
+	for i := first; i <= len(rows); i++ {
+		newRecord := i == len(rows) || start < 0 || e.groupCol < 0
+		if !newRecord {
+			value := normalizeCell(cell(rows[i], e.groupCol))
+			newRecord = value != "" && value != groupValue
+		}
+
+		if newRecord {
+			if start >= 0 && matched {
+				remove = append(remove, [2]int{start, i})
+			}
+			if i == len(rows) {
+				break
+			}
+			start, matched = i, false
+			if e.groupCol >= 0 {
+				groupValue = normalizeCell(cell(rows[i], e.groupCol))
+			}
+		}
+
+		for _, c := range rows[i] {
+			if e.match[normalizeCell(c)] {
+				matched = true
+				break
+			}
+		}
+	}
+
+	return remove
+}
+
+func cell(row []string, col int) string {
+	if col < len(row) {
+		return row[col]
+	}
+
+	return ""
+}
+
+func normalizeCell(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
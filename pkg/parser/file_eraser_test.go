@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestEraseRecords(t *testing.T) {
+	rows := map[string][]interface{}{
+		"A1": {"Email", "Company", "Event"},
+		"A2": {"anakin.skywalker@deathstar.imp", "Empire", "Podracing"},
+		"A3": {nil, "Rebels", "Meditation"},
+		"A4": {"obi@jedi.rules", "Jedi Order", "Duel"},
+		"A5": {" Anakin.Skywalker@DeathStar.imp", nil, "Duel"},
+	}
+
+	tests := []struct {
+		name       string
+		schema     Schema
+		values     []string
+		wantErased int
+		wantRows   [][]string
+	}{
+		{
+			"grouped rows",
+			Schema{Headers: true, GroupBy: "A"},
+			[]string{"anakin.skywalker@deathstar.imp"},
+			2,
+			[][]string{
+				{"Email", "Company", "Event"},
+				{"obi@jedi.rules", "Jedi Order", "Duel"},
+			},
+		},
+		{
+			"single rows",
+			Schema{Headers: true},
+			[]string{"anakin.skywalker@deathstar.imp"},
+			2,
+			[][]string{
+				{"Email", "Company", "Event"},
+				{"", "Rebels", "Meditation"},
+				{"obi@jedi.rules", "Jedi Order", "Duel"},
+			},
+		},
+		{
+			"any column",
+			Schema{Headers: true},
+			[]string{"rebels"},
+			1,
+			[][]string{
+				{"Email", "Company", "Event"},
+				{"anakin.skywalker@deathstar.imp", "Empire", "Podracing"},
+				{"obi@jedi.rules", "Jedi Order", "Duel"},
+				{" Anakin.Skywalker@DeathStar.imp", "", "Duel"},
+			},
+		},
+		{
+			"no match",
+			Schema{Headers: true, GroupBy: "A"},
+			[]string{"leia@rebels.org", ""},
+			0,
+			[][]string{
+				{"Email", "Company", "Event"},
+				{"anakin.skywalker@deathstar.imp", "Empire", "Podracing"},
+				{"", "Rebels", "Meditation"},
+				{"obi@jedi.rules", "Jedi Order", "Duel"},
+				{" Anakin.Skywalker@DeathStar.imp", "", "Duel"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, erased, err := EraseRecords(getFileReader(t, []string{"Sheet1"}, rows), tt.schema, tt.values)
+			if err != nil {
+				t.Fatalf("EraseRecords() error = %v", err)
+			}
+			if erased != tt.wantErased {
+				t.Errorf("EraseRecords() erased = %d, want %d", erased, tt.wantErased)
+			}
+
+			f, err := excelize.OpenReader(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := f.GetRows("Sheet1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.wantRows) {
+				t.Errorf("EraseRecords() rows = %q, want %q", got, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestEraseCSVRecords(t *testing.T) {
+	file := "Email,Company,Event\n" +
+		"anakin.skywalker@deathstar.imp,Empire,Podracing\n" +
+		",Rebels,Meditation\n" +
+		"obi@jedi.rules,Jedi Order\n" +
+		" Anakin.Skywalker@DeathStar.imp,,Duel\n"
+
+	tests := []struct {
+		name       string
+		schema     Schema
+		values     []string
+		wantErased int
+		want       string
+	}{
+		{
+			"grouped rows",
+			Schema{Headers: true, GroupBy: "A"},
+			[]string{"anakin.skywalker@deathstar.imp"},
+			2,
+			"Email,Company,Event\nobi@jedi.rules,Jedi Order\n",
+		},
+		{
+			"single rows",
+			Schema{Headers: true},
+			[]string{"anakin.skywalker@deathstar.imp"},
+			2,
+			"Email,Company,Event\n,Rebels,Meditation\nobi@jedi.rules,Jedi Order\n",
+		},
+		{
+			"no match",
+			Schema{Headers: true, GroupBy: "A"},
+			[]string{"leia@rebels.org"},
+			0,
+			"Email,Company,Event\n" +
+				"anakin.skywalker@deathstar.imp,Empire,Podracing\n" +
+				",Rebels,Meditation\n" +
+				"obi@jedi.rules,Jedi Order\n" +
+				"\" Anakin.Skywalker@DeathStar.imp\",,Duel\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, erased, err := EraseCSVRecords(strings.NewReader(file), tt.schema, tt.values)
+			if err != nil {
+				t.Fatalf("EraseCSVRecords() error = %v", err)
+			}
+			if erased != tt.wantErased {
+				t.Errorf("EraseCSVRecords() erased = %d, want %d", erased, tt.wantErased)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("EraseCSVRecords() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,8 @@ type FieldSchema struct {
 	IsMultiple bool   `json:"is_multiple"`
 	IsMap      bool   `json:"is_map"`
 	MapStart   bool   `json:"map_start"`
+	Lookup     string `json:"lookup"`
+	IsDate     bool   `json:"is_date"`
 }
 
 type Schema struct {
@@ -24,6 +26,10 @@ type Schema struct {
 	SchemaType string        `json:"schema_type"`
 	Headers    bool          `json:"headers"`
 	Fields     []FieldSchema `json:"fields"`
+	Lookups    []LookupTable `json:"lookups"`
+	Options    ReadOptions   `json:"options"`
+	// GroupBy is a column which value identifies a record spread over several consecutive rows.
+	GroupBy string `json:"group_by"`
 }
 
 // ParseCSVFile maps csv file data b to in struct pointer according to s schema.
@@ -95,26 +101,54 @@ func ParseXLSXFile[T any](in *[]T, r io.Reader, s Schema) error {
 }
 
 // getDataMapList maps excelize.File f to value map according to schema s.
+// If schema s has GroupBy column, consecutive rows with the same or empty group value are merged into one record.
 // Returns data map and error.
 func getDataMapList(f *excelize.File, s Schema) ([]map[string]interface{}, error) {
 	var dataMapList []map[string]interface{}
+	lookups, err := s.lookupTables()
+	if err != nil {
+		return nil, err
+	}
+
 	sl := f.GetSheetList()
 	for _, sheetName := range sl {
 		currRowIndex := 1
+		cr, err := newCellReader(f, sheetName, s.Options)
+		if err != nil {
+			return nil, err
+		}
+
 		rows, err := f.Rows(sheetName)
 		if err != nil {
 			return nil, err
 		}
 
+		sheetStart := len(dataMapList)
+		groupValue := ""
 		for rows.Next() {
 			if s.Headers && currRowIndex == 1 {
 				currRowIndex++
 				continue
 			}
-			fim, err := mapRow(currRowIndex, sheetName, f, s)
+			fim, err := mapRow(currRowIndex, cr, s, lookups)
 			if err != nil {
 				return nil, err
 			}
+
+			if s.GroupBy != "" {
+				value, err := cr.value(fmt.Sprintf("%s%v", s.GroupBy, currRowIndex), false)
+				if err != nil {
+					return nil, err
+				}
+
+				if len(dataMapList) > sheetStart && (value == "" || value == groupValue) {
+					mergeRows(dataMapList[len(dataMapList)-1], fim)
+					currRowIndex++
+					continue
+				}
+				groupValue = value
+			}
+
 			dataMapList = append(dataMapList, fim)
 			currRowIndex++
 		}
@@ -126,16 +160,29 @@ func getDataMapList(f *excelize.File, s Schema) ([]map[string]interface{}, error
 	return dataMapList, nil
 }
 
-// mapRow maps excelize.File f row data to value map using schema by index and sheetName.
+// mapRow maps sheet row data read by cellReader cr to value map using schema by index.
+// Values of fields with a lookup are replaced according to the lookups tables.
 // Returns row data values map and an error.
-func mapRow(index int, sheetName string, f *excelize.File, s Schema) (map[string]interface{}, error) {
+func mapRow(index int, cr *cellReader, s Schema, lookups map[string]LookupTable) (map[string]interface{}, error) {
 	fim := make(map[string]interface{})
 	for _, fs := range s.Fields {
-		value, err := f.GetCellValue(sheetName, fmt.Sprintf("%s%v", fs.Col, index))
+		axis := fmt.Sprintf("%s%v", fs.Col, index)
+		value, err := cr.value(axis, fs.IsDate)
 		if err != nil {
 			return nil, err
 		}
 
+		if value != "" && fs.Lookup != "" {
+			var keep bool
+			value, keep, err = lookups[fs.Lookup].apply(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s!%s: %w", cr.sheet, axis, err)
+			}
+			if !keep {
+				value = ""
+			}
+		}
+
 		if value != "" {
 			if fs.IsMap {
 				parts := strings.Split(fs.Name, ".")
@@ -177,3 +224,34 @@ func mapRow(index int, sheetName string, f *excelize.File, s Schema) (map[string
 	}
 	return fim, nil
 }
+
+// mergeRows merges row values map src into record values map dst.
+// Multiple values are accumulated, single values and map keys already present in dst are kept.
+func mergeRows(dst map[string]interface{}, src map[string]interface{}) {
+	for key, value := range src {
+		current, ok := dst[key]
+		if !ok {
+			dst[key] = value
+			continue
+		}
+
+		switch currentValue := current.(type) {
+		case []interface{}:
+			if values, ok := value.([]interface{}); ok {
+				dst[key] = append(currentValue, values...)
+			}
+		case []map[string]interface{}:
+			if values, ok := value.([]map[string]interface{}); ok {
+				dst[key] = append(currentValue, values...)
+			}
+		case map[string]interface{}:
+			if values, ok := value.(map[string]interface{}); ok {
+				for k, v := range values {
+					if _, ok := currentValue[k]; !ok {
+						currentValue[k] = v
+					}
+				}
+			}
+		}
+	}
+}
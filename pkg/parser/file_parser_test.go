@@ -5,6 +5,7 @@ import (
 	"io"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/xuri/excelize/v2"
 )
@@ -176,10 +177,9 @@ func TestParseXLSXFile(t *testing.T) {
 	}
 
 	schema := Schema{
-		"1",
-		"",
-		true,
-		[]FieldSchema{
+		Version: "1",
+		Headers: true,
+		Fields: []FieldSchema{
 			{Name: "first_name", Col: "A"},
 			{Name: "last_name", Col: "B"},
 			{Name: "email", Col: "C"},
@@ -313,3 +313,255 @@ func TestParseXLSXFile(t *testing.T) {
 		})
 	}
 }
+
+func TestParseXLSXFileLookups(t *testing.T) {
+	type member struct {
+		Email          string `mapstructure:"email"`
+		MembershipType string `mapstructure:"membership_type"`
+	}
+
+	rows := map[string][]interface{}{
+		"A1": {"Email", "Membership"},
+		"A2": {"anakin.skywalker@deathstar.imp", "M"},
+		"A3": {"obi@jedi.rules", " S "},
+		"A4": {"yoda@jedi.rules", "X"},
+	}
+
+	newSchema := func(onUnknown string) Schema {
+		return Schema{
+			Version: "1",
+			Headers: true,
+			Fields: []FieldSchema{
+				{Name: "email", Col: "A"},
+				{Name: "membership_type", Col: "B", Lookup: "membership"},
+			},
+			Lookups: []LookupTable{
+				{
+					Name:      "membership",
+					Values:    map[string]string{"M": "Member", "S": "Speaker"},
+					OnUnknown: onUnknown,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		s       Schema
+		want    []member
+		wantErr bool
+	}{
+		{
+			"keep unknown values",
+			newSchema(UnknownKeep),
+			[]member{
+				{"anakin.skywalker@deathstar.imp", "Member"},
+				{"obi@jedi.rules", "Speaker"},
+				{"yoda@jedi.rules", "X"},
+			},
+			false,
+		},
+		{
+			"drop unknown values",
+			newSchema(UnknownNull),
+			[]member{
+				{"anakin.skywalker@deathstar.imp", "Member"},
+				{"obi@jedi.rules", "Speaker"},
+				{"yoda@jedi.rules", ""},
+			},
+			false,
+		},
+		{
+			"fail on unknown values",
+			newSchema(UnknownError),
+			nil,
+			true,
+		},
+		{
+			"unknown lookup table",
+			Schema{
+				Headers: true,
+				Fields:  []FieldSchema{{Name: "membership_type", Col: "B", Lookup: "missing"}},
+			},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &[]member{}
+			err := ParseXLSXFile(got, getFileReader(t, []string{"Sheet1"}, rows), tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseXLSXFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ParseXLSXFile() got - %v, want - %v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseXLSXFileReadOptions(t *testing.T) {
+	type member struct {
+		Email    string `mapstructure:"email"`
+		Events   int    `mapstructure:"events"`
+		Company  string `mapstructure:"company"`
+		JoinDate string `mapstructure:"join_date"`
+	}
+
+	getReader := func(t *testing.T) io.Reader {
+		f := excelize.NewFile()
+		rows := map[string][]interface{}{
+			"A1": {"Email", "Events", "Company", "Join date"},
+			"A2": {"Anakin", 2, "Empire", 44927},
+			"A3": {"Obi-Wan", 3, nil, time.Date(2022, 12, 24, 0, 0, 0, 0, time.UTC)},
+		}
+		for addr, row := range rows {
+			row := row
+			if err := f.SetSheetRow("Sheet1", addr, &row); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for axis, link := range map[string]string{
+			"A2": "mailto:anakin.skywalker@deathstar.imp",
+			"A3": "mailto:obi@jedi.rules",
+		} {
+			if err := f.SetCellHyperLink("Sheet1", axis, link, "External"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := f.SetCellFormula("Sheet1", "B3", "B2*5"); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.MergeCell("Sheet1", "C2", "C3"); err != nil {
+			t.Fatal(err)
+		}
+
+		buffer, err := f.WriteToBuffer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return bytes.NewReader(buffer.Bytes())
+	}
+
+	fields := []FieldSchema{
+		{Name: "email", Col: "A"},
+		{Name: "events", Col: "B"},
+		{Name: "company", Col: "C"},
+		{Name: "join_date", Col: "D", IsDate: true},
+	}
+
+	tests := []struct {
+		name    string
+		options ReadOptions
+		want    []member
+	}{
+		{
+			"default options",
+			ReadOptions{},
+			[]member{
+				{"Anakin", 2, "Empire", "2023-01-01"},
+				{"Obi-Wan", 3, "Empire", "2022-12-24"},
+			},
+		},
+		{
+			"ignore merged cells",
+			ReadOptions{IgnoreMergedCells: true},
+			[]member{
+				{"Anakin", 2, "Empire", "2023-01-01"},
+				{"Obi-Wan", 3, "", "2022-12-24"},
+			},
+		},
+		{
+			"formulas and hyperlinks",
+			ReadOptions{CalcFormulas: true, Hyperlinks: true},
+			[]member{
+				{"anakin.skywalker@deathstar.imp", 2, "Empire", "2023-01-01"},
+				{"obi@jedi.rules", 10, "Empire", "2022-12-24"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &[]member{}
+			s := Schema{Version: "1", Headers: true, Fields: fields, Options: tt.options}
+			if err := ParseXLSXFile(got, getReader(t), s); err != nil {
+				t.Fatalf("ParseXLSXFile() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ParseXLSXFile() got - %v, want - %v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseXLSXFileGroupBy(t *testing.T) {
+	type event struct {
+		Name  string `mapstructure:"name"`
+		Hours int    `mapstructure:"hours"`
+	}
+	type member struct {
+		Email    string   `mapstructure:"email"`
+		Company  string   `mapstructure:"company"`
+		Tags     []string `mapstructure:"tags"`
+		Attended []event  `mapstructure:"attended"`
+	}
+
+	schema := Schema{
+		Version: "1",
+		Headers: true,
+		GroupBy: "A",
+		Fields: []FieldSchema{
+			{Name: "email", Col: "A"},
+			{Name: "company", Col: "B"},
+			{Name: "tags", Col: "C", IsMultiple: true},
+			{Name: "attended.name", Col: "D", IsMultiple: true, IsMap: true, MapStart: true},
+			{Name: "attended.hours", Col: "E", IsMultiple: true, IsMap: true},
+		},
+	}
+
+	rows := map[string][]interface{}{
+		"A1": {"Email", "Company", "Tag", "Event", "Hours"},
+		"A2": {"anakin.skywalker@deathstar.imp", "Empire", "sith", "Podracing", 2},
+		"A3": {nil, "Rebels", "pilot", "Meditation", 1},
+		"A4": {"obi@jedi.rules", nil, "jedi", "Duel", 3},
+		"A5": {"obi@jedi.rules", "Jedi Order", nil, "Mentoring", 5},
+		"A6": {"anakin.skywalker@deathstar.imp", nil, nil, "Duel", 3},
+	}
+
+	want := []member{
+		{
+			"anakin.skywalker@deathstar.imp",
+			"Empire",
+			[]string{"sith", "pilot"},
+			[]event{{"Podracing", 2}, {"Meditation", 1}},
+		},
+		{
+			"obi@jedi.rules",
+			"Jedi Order",
+			[]string{"jedi"},
+			[]event{{"Duel", 3}, {"Mentoring", 5}},
+		},
+		{
+			"anakin.skywalker@deathstar.imp",
+			"",
+			nil,
+			[]event{{"Duel", 3}},
+		},
+	}
+
+	got := &[]member{}
+	if err := ParseXLSXFile(got, getFileReader(t, []string{"Sheet1"}, rows), schema); err != nil {
+		t.Fatalf("ParseXLSXFile() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("ParseXLSXFile() got - %v, want - %v", *got, want)
+	}
+}
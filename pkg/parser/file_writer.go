@@ -0,0 +1,253 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/abdukhashimov/student_aggregator/pkg/export"
+	"github.com/mitchellh/mapstructure"
+	"github.com/xuri/excelize/v2"
+)
+
+// FileWriter writes items in the layout of a schema, so the output can be parsed back with the same schema.
+type FileWriter struct {
+	w       export.Writer
+	s       Schema
+	cols    []int
+	width   int
+	reverse map[string]map[string]string
+}
+
+// NewFileWriter creates FileWriter writing rows of schema s layout to w.
+// Header row holds field names when schema has headers.
+// Returns FileWriter pointer and an error.
+func NewFileWriter(w export.Writer, s Schema) (*FileWriter, error) {
+	lookups, err := s.lookupTables()
+	if err != nil {
+		return nil, err
+	}
+
+	fw := &FileWriter{
+		w:       w,
+		s:       s,
+		cols:    make([]int, len(s.Fields)),
+		reverse: make(map[string]map[string]string, len(lookups)),
+	}
+
+	for i, fs := range s.Fields {
+		col, err := excelize.ColumnNameToNumber(fs.Col)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", fs.Name, err)
+		}
+		fw.cols[i] = col
+		if col > fw.width {
+			fw.width = col
+		}
+	}
+
+	for name, lt := range lookups {
+		fw.reverse[name] = lt.reverse()
+	}
+
+	if s.Headers {
+		header := make([]interface{}, fw.width)
+		for i, fs := range s.Fields {
+			header[fw.cols[i]-1] = fs.Name
+		}
+		if err := w.Write(header); err != nil {
+			return nil, err
+		}
+	}
+
+	return fw, nil
+}
+
+// WriteXLSXFile writes items to w as XLSX file in the layout of schema s.
+// Returns an error.
+func WriteXLSXFile[T any](w io.Writer, items []T, s Schema) error {
+	xw, err := export.NewXLSXWriter(w, "")
+	if err != nil {
+		return err
+	}
+
+	fw, err := NewFileWriter(xw, s)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := fw.Write(item); err != nil {
+			return err
+		}
+	}
+
+	return fw.Close()
+}
+
+// Write writes item fields to the schema columns, multiple values and map groups fill their columns in order.
+// If schema has GroupBy column, values not fitting a row are written to the following rows with empty group value,
+// otherwise they are dropped.
+// Returns an error.
+func (fw *FileWriter) Write(item interface{}) error {
+	record, ok := toValue(item).(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unsupported item type %T", item)
+	}
+
+	used := make(map[string]int)
+	mapIndexes := make(map[string]int)
+	for first := true; first || (fw.s.GroupBy != "" && fw.hasMore(record, used)); first = false {
+		row := make([]interface{}, fw.width)
+		for i, fs := range fw.s.Fields {
+			value := fw.fieldValue(fs, record, first, used, mapIndexes)
+			if value == nil {
+				continue
+			}
+			if fs.Lookup != "" {
+				value = fw.unmap(fs.Lookup, value)
+			}
+			row[fw.cols[i]-1] = value
+		}
+
+		if err := fw.w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close flushes written rows.
+func (fw *FileWriter) Close() error {
+	return fw.w.Close()
+}
+
+// fieldValue takes value of field fs from record. Single values are taken from the first row of a record only,
+// used counts taken multiple values and mapIndexes holds current map of multiple map groups.
+func (fw *FileWriter) fieldValue(fs FieldSchema, record map[string]interface{}, first bool, used map[string]int, mapIndexes map[string]int) interface{} {
+	if fs.IsMap {
+		parts := strings.Split(fs.Name, ".")
+		if len(parts) != 2 {
+			return nil
+		}
+
+		if !fs.IsMultiple {
+			values, _ := record[parts[0]].(map[string]interface{})
+			if !first || values == nil {
+				return nil
+			}
+			return values[parts[1]]
+		}
+
+		if fs.MapStart {
+			mapIndexes[parts[0]] = used[parts[0]]
+			used[parts[0]]++
+		}
+		index, ok := mapIndexes[parts[0]]
+		values, _ := record[parts[0]].([]interface{})
+		if !ok || index >= len(values) {
+			return nil
+		}
+		value, _ := values[index].(map[string]interface{})
+		if value == nil {
+			return nil
+		}
+		return value[parts[1]]
+	}
+
+	if fs.IsMultiple {
+		values, _ := record[fs.Name].([]interface{})
+		index := used[fs.Name]
+		used[fs.Name]++
+		if index >= len(values) {
+			return nil
+		}
+		return values[index]
+	}
+
+	if !first {
+		return nil
+	}
+
+	return record[fs.Name]
+}
+
+// hasMore reports whether record has multiple values which are not written yet.
+func (fw *FileWriter) hasMore(record map[string]interface{}, used map[string]int) bool {
+	for name, count := range used {
+		if count == 0 {
+			continue
+		}
+		if values, ok := record[name].([]interface{}); ok && count < len(values) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unmap replaces mapped value with its source value of lookup table, so the file can be parsed back.
+func (fw *FileWriter) unmap(lookup string, value interface{}) interface{} {
+	if source, ok := fw.reverse[lookup][fmt.Sprint(value)]; ok {
+		return source
+	}
+
+	return value
+}
+
+// reverse maps lookup table values back to their sources, the smallest source is taken for repeated values.
+func (lt LookupTable) reverse() map[string]string {
+	sources := make([]string, 0, len(lt.Values))
+	for source := range lt.Values {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	reverse := make(map[string]string, len(sources))
+	for _, source := range sources {
+		if _, ok := reverse[lt.Values[source]]; !ok && lt.Values[source] != "" {
+			reverse[lt.Values[source]] = source
+		}
+	}
+
+	return reverse
+}
+
+// toValue converts structs to maps using mapstructure tags, and slices to []interface{} recursively.
+func toValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil
+		}
+		return toValue(rv.Elem().Interface())
+	case reflect.Struct:
+		m := make(map[string]interface{})
+		if err := mapstructure.Decode(v, &m); err != nil {
+			return nil
+		}
+		return toValue(m)
+	case reflect.Map:
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = toValue(iter.Value().Interface())
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		values := make([]interface{}, rv.Len())
+		for i := range values {
+			values[i] = toValue(rv.Index(i).Interface())
+		}
+		return values
+	default:
+		if rv.IsValid() && rv.IsZero() {
+			return nil
+		}
+		return v
+	}
+}
@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestWriteXLSXFileRoundTrip(t *testing.T) {
+	type event struct {
+		Name  string `mapstructure:"name"`
+		Hours int    `mapstructure:"hours"`
+	}
+	type address struct {
+		City string `mapstructure:"city"`
+	}
+	type member struct {
+		Email    string   `mapstructure:"email"`
+		Level    string   `mapstructure:"level"`
+		Tags     []string `mapstructure:"tags"`
+		Address  address  `mapstructure:"address"`
+		Attended []event  `mapstructure:"attended"`
+	}
+
+	members := []member{
+		{
+			Email:    "anakin.skywalker@deathstar.imp",
+			Level:    "padawan",
+			Tags:     []string{"sith", "pilot", "podracer"},
+			Address:  address{City: "Mos Espa"},
+			Attended: []event{{"Podracing", 2}, {"Meditation", 1}, {"Duel", 3}},
+		},
+		{
+			Email:    "obi@jedi.rules",
+			Level:    "master",
+			Tags:     []string{"jedi"},
+			Attended: []event{{"Mentoring", 5}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		schema   Schema
+		wantRows [][]string
+		want     []member
+	}{
+		{
+			"group rows",
+			Schema{
+				Headers: true,
+				GroupBy: "A",
+				Lookups: []LookupTable{
+					{Name: "levels", Values: map[string]string{"P": "padawan", "M": "master"}},
+				},
+				Fields: []FieldSchema{
+					{Name: "email", Col: "A"},
+					{Name: "level", Col: "B", Lookup: "levels"},
+					{Name: "tags", Col: "C", IsMultiple: true},
+					{Name: "tags", Col: "D", IsMultiple: true},
+					{Name: "address.city", Col: "E", IsMap: true},
+					{Name: "attended.name", Col: "F", IsMultiple: true, IsMap: true, MapStart: true},
+					{Name: "attended.hours", Col: "G", IsMultiple: true, IsMap: true},
+				},
+			},
+			[][]string{
+				{"email", "level", "tags", "tags", "address.city", "attended.name", "attended.hours"},
+				{"anakin.skywalker@deathstar.imp", "P", "sith", "pilot", "Mos Espa", "Podracing", "2"},
+				{"", "", "podracer", "", "", "Meditation", "1"},
+				{"", "", "", "", "", "Duel", "3"},
+				{"obi@jedi.rules", "M", "jedi", "", "", "Mentoring", "5"},
+			},
+			members,
+		},
+		{
+			"values not fitting columns are dropped without group column",
+			Schema{
+				Fields: []FieldSchema{
+					{Name: "email", Col: "B"},
+					{Name: "attended.name", Col: "C", IsMultiple: true, IsMap: true, MapStart: true},
+					{Name: "attended.hours", Col: "D", IsMultiple: true, IsMap: true},
+				},
+			},
+			[][]string{
+				{"", "anakin.skywalker@deathstar.imp", "Podracing", "2"},
+				{"", "obi@jedi.rules", "Mentoring", "5"},
+			},
+			[]member{
+				{Email: "anakin.skywalker@deathstar.imp", Attended: []event{{"Podracing", 2}}},
+				{Email: "obi@jedi.rules", Attended: []event{{"Mentoring", 5}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteXLSXFile(&buf, members, tt.schema); err != nil {
+				t.Fatalf("WriteXLSXFile() error = %v", err)
+			}
+
+			f, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows, err := f.GetRows("Sheet1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(padRows(rows, len(tt.wantRows[0])), tt.wantRows) {
+				t.Errorf("rows = %q, want %q", rows, tt.wantRows)
+			}
+
+			got := &[]member{}
+			if err := ParseXLSXFile(got, bytes.NewReader(buf.Bytes()), tt.schema); err != nil {
+				t.Fatalf("ParseXLSXFile() error = %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ParseXLSXFile() = %v, want %v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func padRows(rows [][]string, width int) [][]string {
+	for i := range rows {
+		for len(rows[i]) < width {
+			rows[i] = append(rows[i], "")
+		}
+	}
+
+	return rows
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	UnknownKeep  = "keep"
+	UnknownNull  = "null"
+	UnknownError = "error"
+)
+
+type LookupTable struct {
+	Name      string            `json:"name"`
+	Values    map[string]string `json:"values"`
+	OnUnknown string            `json:"on_unknown"`
+}
+
+// lookupTables indexes schema s lookup tables by name and checks that every field refers to an existing table.
+// Returns lookup tables map and an error.
+func (s Schema) lookupTables() (map[string]LookupTable, error) {
+	tables := make(map[string]LookupTable, len(s.Lookups))
+	for _, lt := range s.Lookups {
+		switch lt.OnUnknown {
+		case "", UnknownKeep, UnknownNull, UnknownError:
+		default:
+			return nil, fmt.Errorf("lookup %q: unsupported unknown value behavior %q", lt.Name, lt.OnUnknown)
+		}
+		tables[lt.Name] = lt
+	}
+
+	for _, fs := range s.Fields {
+		if fs.Lookup == "" {
+			continue
+		}
+		if _, ok := tables[fs.Lookup]; !ok {
+			return nil, fmt.Errorf("field %q refers to unknown lookup %q", fs.Name, fs.Lookup)
+		}
+	}
+
+	return tables, nil
+}
+
+// apply maps value through lookup table lt.
+// Returns mapped value, false if value should be dropped, and an error for unknown values when required.
+func (lt LookupTable) apply(value string) (string, bool, error) {
+	if mapped, ok := lt.Values[strings.TrimSpace(value)]; ok {
+		return mapped, mapped != "", nil
+	}
+
+	switch lt.OnUnknown {
+	case UnknownNull:
+		return "", false, nil
+	case UnknownError:
+		return "", false, fmt.Errorf("lookup %q: unknown value %q", lt.Name, value)
+	default:
+		return value, true, nil
+	}
+}
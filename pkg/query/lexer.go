@@ -0,0 +1,87 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+const operatorChars = "=!<>~"
+
+// tokenize splits input to words, quoted strings, operators, parentheses and commas.
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokenComma, ",", i})
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, &Error{start, "unterminated string"}
+			}
+			tokens = append(tokens, token{tokenString, sb.String(), start})
+			i++
+		case strings.ContainsRune(operatorChars, r):
+			start := i
+			for i < len(runes) && strings.ContainsRune(operatorChars, runes[i]) {
+				i++
+			}
+			op := string(runes[start:i])
+			switch Operator(op) {
+			case Eq, Ne, Gt, Gte, Lt, Lte, Regex:
+			default:
+				return nil, &Error{start, "unknown operator " + op}
+			}
+			tokens = append(tokens, token{tokenOperator, op, start})
+		default:
+			start := i
+			for i < len(runes) && !isDelimiter(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenWord, string(runes[start:i]), start})
+		}
+	}
+
+	return append(tokens, token{tokenEOF, "", len(runes)}), nil
+}
+
+func isDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(operatorChars+"(),\"'", r)
+}
@@ -0,0 +1,314 @@
+package query
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxConditions  = 50
+	maxDepth       = 10
+	maxRegexLength = 100
+)
+
+type parser struct {
+	tokens     []token
+	pos        int
+	fields     Fields
+	conditions int
+	depth      int
+}
+
+// Parse parses filter expression input allowing only fields whitelisted in fields, e.g.
+//
+//	student_rss.projects.score>=80 AND (source=RSS OR email contains "@gmail.com")
+//
+// Supported operators are = != > >= < <= ~ (regex), in (...), exists and contains,
+// regular expressions may not have groups and may repeat single characters or character classes only,
+// conditions can be combined with AND, OR, NOT and parentheses.
+// Returns nil for an empty input.
+func Parse(input string, fields Fields) (Expr, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %q", t.value)
+	}
+
+	return expr, nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []Expr{expr}
+	for p.keyword("or") {
+		p.next()
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+
+	return Or{Exprs: exprs}, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	expr, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []Expr{expr}
+	for p.keyword("and") {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+
+	return And{Exprs: exprs}, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.keyword("not") {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: expr}, nil
+	}
+
+	if t := p.peek(); t.kind == tokenLParen {
+		p.depth++
+		if p.depth > maxDepth {
+			return nil, p.errorf(t, "too deeply nested expression")
+		}
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokenRParen {
+			return nil, p.errorf(t, "expected )")
+		}
+		p.depth--
+		return expr, nil
+	}
+
+	return p.parseCondition()
+}
+
+func (p *parser) parseCondition() (Expr, error) {
+	t := p.next()
+	if t.kind != tokenWord {
+		return nil, p.errorf(t, "expected field name")
+	}
+
+	fieldType, ok := p.fields[t.value]
+	if !ok {
+		return nil, p.errorf(t, "unknown field %q", t.value)
+	}
+
+	p.conditions++
+	if p.conditions > maxConditions {
+		return nil, p.errorf(t, "too many conditions")
+	}
+
+	cond := Condition{Field: t.value, Type: fieldType}
+
+	negate := false
+	if p.keyword("not") {
+		p.next()
+		negate = true
+	}
+
+	opToken := p.next()
+	switch {
+	case opToken.kind == tokenOperator && !negate:
+		cond.Op = Operator(opToken.value)
+		value, err := p.parseValue(cond)
+		if err != nil {
+			return nil, err
+		}
+		cond.Values = []interface{}{value}
+	case opToken.kind == tokenWord && strings.EqualFold(opToken.value, string(In)):
+		cond.Op = In
+		values, err := p.parseList(cond)
+		if err != nil {
+			return nil, err
+		}
+		cond.Values = values
+	case opToken.kind == tokenWord && strings.EqualFold(opToken.value, string(Exists)):
+		cond.Op = Exists
+	case opToken.kind == tokenWord && strings.EqualFold(opToken.value, string(Contains)):
+		cond.Op = Contains
+		value, err := p.parseValue(cond)
+		if err != nil {
+			return nil, err
+		}
+		cond.Values = []interface{}{value}
+	default:
+		return nil, p.errorf(opToken, "expected operator after %q", cond.Field)
+	}
+
+	if (cond.Op == Regex || cond.Op == Contains) && cond.Type != String {
+		return nil, p.errorf(opToken, "operator %s is allowed for text fields only", cond.Op)
+	}
+
+	if negate {
+		return Not{Expr: cond}, nil
+	}
+
+	return cond, nil
+}
+
+func (p *parser) parseList(cond Condition) ([]interface{}, error) {
+	if t := p.next(); t.kind != tokenLParen {
+		return nil, p.errorf(t, "expected (")
+	}
+
+	var values []interface{}
+	for {
+		value, err := p.parseValue(cond)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		t := p.next()
+		if t.kind == tokenRParen {
+			return values, nil
+		}
+		if t.kind != tokenComma {
+			return nil, p.errorf(t, "expected , or )")
+		}
+	}
+}
+
+// parseValue converts next token to value of the cond field type.
+func (p *parser) parseValue(cond Condition) (interface{}, error) {
+	t := p.next()
+	if t.kind != tokenWord && t.kind != tokenString {
+		return nil, p.errorf(t, "expected value for %q", cond.Field)
+	}
+
+	if cond.Op == Regex {
+		if len(t.value) > maxRegexLength {
+			return nil, p.errorf(t, "regular expression is too long")
+		}
+		re, err := syntax.Parse(t.value, syntax.Perl)
+		if err != nil {
+			return nil, p.errorf(t, "invalid regular expression")
+		}
+		if err := safeRegex(re); err != nil {
+			return nil, p.errorf(t, "%s", err.Error())
+		}
+		return t.value, nil
+	}
+
+	switch cond.Type {
+	case Number:
+		value, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, p.errorf(t, "%q expects a number", cond.Field)
+		}
+		return value, nil
+	case Bool:
+		value, err := strconv.ParseBool(t.value)
+		if err != nil {
+			return nil, p.errorf(t, "%q expects true or false", cond.Field)
+		}
+		return value, nil
+	case Date:
+		value, err := ParseDate(t.value)
+		if err != nil {
+			return nil, p.errorf(t, "%q expects a date like 2006-01-02", cond.Field)
+		}
+		return value, nil
+	default:
+		return t.value, nil
+	}
+}
+
+// safeRegex checks regular expression re has no groups and repeats single characters or character classes only.
+// MongoDB runs regular expressions with a backtracking engine, so nested or alternated repetitions
+// like (a+)+ may take exponential time.
+func safeRegex(re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpCapture:
+		return fmt.Errorf("groups are not allowed in regular expression")
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		switch sub := re.Sub[0]; {
+		case sub.Op == syntax.OpLiteral && len(sub.Rune) == 1,
+			sub.Op == syntax.OpCharClass, sub.Op == syntax.OpAnyChar, sub.Op == syntax.OpAnyCharNotNL:
+			return nil
+		default:
+			return fmt.Errorf("only single characters or character classes may be repeated in regular expression")
+		}
+	}
+
+	for _, sub := range re.Sub {
+		if err := safeRegex(sub); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+
+	return t.kind == tokenWord && strings.EqualFold(t.value, word)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	if t.kind == tokenEOF {
+		return &Error{t.pos, "unexpected end of filter, " + fmt.Sprintf(format, args...)}
+	}
+
+	return &Error{t.pos, fmt.Sprintf(format, args...)}
+}
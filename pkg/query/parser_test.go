@@ -0,0 +1,111 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testFields = Fields{
+	"source":                                 String,
+	"email":                                  String,
+	"imported_at":                            Date,
+	"student_rss.projects.score":             Number,
+	"student_wac.attended_events":            Number,
+	"student_wac.receives_community_updates": Bool,
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Expr
+	}{
+		{
+			"empty",
+			"  ",
+			nil,
+		},
+		{
+			"comparison",
+			"student_rss.projects.score>=80",
+			Condition{Field: "student_rss.projects.score", Type: Number, Op: Gte, Values: []interface{}{80.0}},
+		},
+		{
+			"and has priority over or",
+			`source = RSS OR source = 'WAC' and student_wac.attended_events > 2`,
+			Or{Exprs: []Expr{
+				Condition{Field: "source", Type: String, Op: Eq, Values: []interface{}{"RSS"}},
+				And{Exprs: []Expr{
+					Condition{Field: "source", Type: String, Op: Eq, Values: []interface{}{"WAC"}},
+					Condition{Field: "student_wac.attended_events", Type: Number, Op: Gt, Values: []interface{}{2.0}},
+				}},
+			}},
+		},
+		{
+			"parentheses, in and not exists",
+			`(source in (RSS, WAC) AND email NOT EXISTS) or NOT student_wac.receives_community_updates=true`,
+			Or{Exprs: []Expr{
+				And{Exprs: []Expr{
+					Condition{Field: "source", Type: String, Op: In, Values: []interface{}{"RSS", "WAC"}},
+					Not{Expr: Condition{Field: "email", Type: String, Op: Exists}},
+				}},
+				Not{Expr: Condition{Field: "student_wac.receives_community_updates", Type: Bool, Op: Eq, Values: []interface{}{true}}},
+			}},
+		},
+		{
+			"dates, contains and regex",
+			`imported_at >= 2022-11-01 AND email contains "@gmail.com" AND email ~ "^obi" AND email ~ "^[a-z]+.?k*@jedi[.]rules$"`,
+			And{Exprs: []Expr{
+				Condition{Field: "imported_at", Type: Date, Op: Gte, Values: []interface{}{time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)}},
+				Condition{Field: "email", Type: String, Op: Contains, Values: []interface{}{"@gmail.com"}},
+				Condition{Field: "email", Type: String, Op: Regex, Values: []interface{}{"^obi"}},
+				Condition{Field: "email", Type: String, Op: Regex, Values: []interface{}{"^[a-z]+.?k*@jedi[.]rules$"}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, testFields)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unknown field", "password = 123"},
+		{"operator injection", `email = {"$ne": null}`},
+		{"not a number", "student_rss.projects.score > high"},
+		{"not a date", "imported_at > yesterday"},
+		{"contains on number", "student_wac.attended_events contains 1"},
+		{"invalid regex", `email ~ "(["`},
+		{"nested quantifiers", `email ~ "(a+)+$"`},
+		{"repeated group", `email ~ "(?:ab)*c"`},
+		{"repeated alternation", `email ~ "(?:a|aa)*$"`},
+		{"backreference", `email ~ "(a)\\1"`},
+		{"unterminated string", `email = "obi`},
+		{"missing parenthesis", "(source = RSS"},
+		{"missing value", "source ="},
+		{"dangling and", "source = RSS AND"},
+		{"unknown operator", "source => RSS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input, testFields)
+			var qErr *Error
+			if !errors.As(err, &qErr) {
+				t.Errorf("Parse() error = %v, want query error", err)
+			}
+		})
+	}
+}
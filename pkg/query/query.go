@@ -0,0 +1,93 @@
+package query
+
+import (
+	"fmt"
+	"time"
+)
+
+type Type int
+
+const (
+	String Type = iota
+	Number
+	Bool
+	Date
+)
+
+// Fields whitelists fields available in expressions with their value types.
+type Fields map[string]Type
+
+type Operator string
+
+const (
+	Eq       Operator = "="
+	Ne       Operator = "!="
+	Gt       Operator = ">"
+	Gte      Operator = ">="
+	Lt       Operator = "<"
+	Lte      Operator = "<="
+	Regex    Operator = "~"
+	In       Operator = "in"
+	Exists   Operator = "exists"
+	Contains Operator = "contains"
+)
+
+// Expr is a parsed filter expression: And, Or, Not or Condition.
+type Expr interface {
+	expr()
+}
+
+type And struct {
+	Exprs []Expr
+}
+
+type Or struct {
+	Exprs []Expr
+}
+
+type Not struct {
+	Expr Expr
+}
+
+// Condition compares Field with Values converted to the field type:
+// string for String, float64 for Number, bool for Bool and time.Time for Date fields.
+type Condition struct {
+	Field  string
+	Type   Type
+	Op     Operator
+	Values []interface{}
+}
+
+func (And) expr()       {}
+func (Or) expr()        {}
+func (Not) expr()       {}
+func (Condition) expr() {}
+
+// Error describes an invalid expression and the position where the problem was found.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("filter: %s at position %d", e.Msg, e.Pos+1)
+}
+
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
+// ParseDate parses value in one of the supported date layouts.
+func ParseDate(value string) (time.Time, error) {
+	var err error
+	for _, layout := range dateLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, err
+}
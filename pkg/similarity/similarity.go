@@ -0,0 +1,84 @@
+package similarity
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Levenshtein returns the minimum number of single rune edits needed to turn a into b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// Ratio returns similarity of a and b in range [0, 1] based on Levenshtein distance.
+// Two empty strings are considered different.
+func Ratio(a, b string) float64 {
+	la, lb := len([]rune(a)), len([]rune(b))
+	if la == 0 || lb == 0 {
+		return 0
+	}
+
+	maxLen := la
+	if lb > maxLen {
+		maxLen = lb
+	}
+
+	return 1 - float64(Levenshtein(a, b))/float64(maxLen)
+}
+
+// TokenSortRatio compares a and b ignoring case, punctuation and words order.
+func TokenSortRatio(a, b string) float64 {
+	return Ratio(sortedTokens(a), sortedTokens(b))
+}
+
+// Tokens splits s to lower cased words consisting of letters and digits.
+func Tokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func sortedTokens(s string) string {
+	tokens := Tokens(s)
+	sort.Strings(tokens)
+
+	return strings.Join(tokens, " ")
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,54 @@
+package similarity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"anakin", "anakin", 0},
+		{"jürgen", "jurgen", 1},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRatio(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"equal", "obi", "obi", 1},
+		{"empty", "", "", 0},
+		{"one typo", "skywalker", "skywalkr", 1 - 1.0/9},
+		{"different", "abc", "xyz", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Ratio(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Ratio(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenSortRatio(t *testing.T) {
+	if got := TokenSortRatio("Skywalker, Anakin", "anakin skywalker"); got != 1 {
+		t.Errorf("TokenSortRatio() = %v, want 1", got)
+	}
+
+	if got := TokenSortRatio("Obi-Wan Kenobi", "Anakin Skywalker"); got > 0.5 {
+		t.Errorf("TokenSortRatio() = %v, want less than 0.5", got)
+	}
+}